@@ -0,0 +1,76 @@
+package bitcoin
+
+import (
+	"context"
+	"math"
+
+	"github.com/spf13/viper"
+)
+
+// ChainID is the chainId deposits and withdrawals use for native bitcoin, "bitcoin.chain-id".
+func ChainID() int {
+	return viper.GetInt("bitcoin.chain-id")
+}
+
+// Address is "bitcoin.address", the platform address native bitcoin deposits are paid to.
+func Address() string {
+	return viper.GetString("bitcoin.address")
+}
+
+// Satoshi rounds btc to satoshi, so amounts are compared without float error.
+func Satoshi(btc float64) int64 {
+	return int64(math.Round(btc * 1e8))
+}
+
+// AmountTo sums outputs paying address.
+func (t *TransactionInfo) AmountTo(address string) float64 {
+	var satoshi int64
+	for _, output := range t.Outputs {
+		if output.Address == address {
+			satoshi += Satoshi(output.Amount)
+		}
+	}
+	return float64(satoshi) / 1e8
+}
+
+// QueryTransactionClientFn returns outputs and confirmations of txid. Pending is true until it has "bitcoin.confirmations",
+// the transaction is still returned so the caller can log it.
+type QueryTransactionClientFn func(ctx context.Context, txid string) (*TransactionInfo, bool, error)
+
+func NewQueryTransactionClientFn(rpcClient *RPCClient) QueryTransactionClientFn {
+	return func(ctx context.Context, txid string) (*TransactionInfo, bool, error) {
+		if rpcClient == nil {
+			return nil, false, ErrBitcoinDisabled
+		}
+		var raw rawTransaction
+		if err := rpcClient.Call(ctx, "getrawtransaction", &raw, txid, true); err != nil {
+			return nil, false, err
+		}
+
+		txnInfo := TransactionInfo{
+			TxnHash:       raw.TxID,
+			BlockHash:     raw.BlockHash,
+			Confirmations: raw.Confirmations,
+			Outputs:       make([]Output, 0, len(raw.Vout)),
+		}
+		for _, vout := range raw.Vout {
+			address := vout.ScriptPubKey.Address
+			if address == "" && len(vout.ScriptPubKey.Addresses) == 1 {
+				address = vout.ScriptPubKey.Addresses[0]
+			}
+			txnInfo.Outputs = append(txnInfo.Outputs, Output{
+				Index:   vout.N,
+				Address: address,
+				Amount:  vout.Value,
+			})
+		}
+		if raw.BlockHash != "" {
+			var header blockHeader
+			if err := rpcClient.Call(ctx, "getblockheader", &header, raw.BlockHash); err != nil {
+				return nil, false, err
+			}
+			txnInfo.Block = header.Height
+		}
+		return &txnInfo, txnInfo.Confirmations < viper.GetInt64("bitcoin.confirmations"), nil
+	}
+}
@@ -0,0 +1,132 @@
+package bitcoin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	testTxID      = "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b"
+	testBlockHash = "0000000000000000000590fc0f3eba193a278534220b2b37e9849e1a770ca959"
+	testAddress   = "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq"
+)
+
+// newRPCStub answers getrawtransaction with transactions and getblockheader with the height of the test block,
+// the same way bitcoind does including errors with a non-200 status.
+func newRPCStub(t *testing.T, transactions map[string]string) *RPCClient {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "user" || password != "pass" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var req rpcRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("cannot decode request: %v", err)
+			return
+		}
+		var result string
+		switch req.Method {
+		case "getrawtransaction":
+			if len(req.Params) != 2 || req.Params[1] != true {
+				t.Errorf("getrawtransaction params = %v, want [txid true]", req.Params)
+			}
+			tx, ok := transactions[req.Params[0].(string)]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"result": nil,
+					"error":  map[string]interface{}{"code": ErrCodeNoTransaction, "message": "No such mempool or blockchain transaction."},
+					"id":     req.ID,
+				})
+				return
+			}
+			result = tx
+		case "getblockheader":
+			if req.Params[0] != testBlockHash {
+				t.Errorf("getblockheader of %v, want %s", req.Params[0], testBlockHash)
+			}
+			result = `{"hash":"` + testBlockHash + `","height":700000}`
+		default:
+			t.Errorf("unexpected method %s", req.Method)
+		}
+		w.Write([]byte(`{"result":` + result + `,"error":null,"id":1}`))
+	}))
+	t.Cleanup(server.Close)
+	return NewRPCClient(server.URL, "user", "pass", time.Second)
+}
+
+func TestQueryTransactionClientFn(t *testing.T) {
+	viper.Set("bitcoin.confirmations", 3)
+	defer viper.Set("bitcoin.confirmations", nil)
+
+	transactions := map[string]string{
+		// Bitcoin Core 22.0 and later name a single address.
+		"confirmed": `{"txid":"` + testTxID + `","blockhash":"` + testBlockHash + `","confirmations":3,"vout":[
+			{"value":0.3,"n":0,"scriptPubKey":{"address":"` + testAddress + `"}},
+			{"value":0.2,"n":1,"scriptPubKey":{"address":"` + testAddress + `"}},
+			{"value":0.0001,"n":2,"scriptPubKey":{"address":"bc1qchange"}}]}`,
+		// earlier versions list addresses.
+		"legacy": `{"txid":"` + testTxID + `","blockhash":"` + testBlockHash + `","confirmations":5,"vout":[
+			{"value":0.5,"n":0,"scriptPubKey":{"addresses":["` + testAddress + `"]}}]}`,
+		"shallow": `{"txid":"` + testTxID + `","blockhash":"` + testBlockHash + `","confirmations":2,"vout":[
+			{"value":0.5,"n":0,"scriptPubKey":{"address":"` + testAddress + `"}}]}`,
+		"mempool": `{"txid":"` + testTxID + `","vout":[
+			{"value":0.5,"n":0,"scriptPubKey":{"address":"` + testAddress + `"}}]}`,
+	}
+	queryTransaction := NewQueryTransactionClientFn(newRPCStub(t, transactions))
+
+	tests := []struct {
+		txid    string
+		pending bool
+		block   int64
+	}{
+		{txid: "confirmed", pending: false, block: 700000},
+		{txid: "legacy", pending: false, block: 700000},
+		{txid: "shallow", pending: true, block: 700000},
+		{txid: "mempool", pending: true, block: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.txid, func(t *testing.T) {
+			txnInfo, pending, err := queryTransaction(context.Background(), tt.txid)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pending != tt.pending {
+				t.Errorf("pending = %t, want %t", pending, tt.pending)
+			}
+			if txnInfo.Block != tt.block {
+				t.Errorf("block = %d, want %d", txnInfo.Block, tt.block)
+			}
+			if got := txnInfo.AmountTo(testAddress); Satoshi(got) != Satoshi(0.5) {
+				t.Errorf("amount to %s = %f, want 0.5", testAddress, got)
+			}
+		})
+	}
+}
+
+func TestQueryTransactionClientFnNoTransaction(t *testing.T) {
+	queryTransaction := NewQueryTransactionClientFn(newRPCStub(t, nil))
+
+	_, _, err := queryTransaction(context.Background(), testTxID)
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != ErrCodeNoTransaction {
+		t.Fatalf("err = %v, want rpc error %d", err, ErrCodeNoTransaction)
+	}
+}
+
+func TestQueryTransactionClientFnDisabled(t *testing.T) {
+	queryTransaction := NewQueryTransactionClientFn(nil)
+
+	if _, _, err := queryTransaction(context.Background(), testTxID); err != ErrBitcoinDisabled {
+		t.Fatalf("err = %v, want %v", err, ErrBitcoinDisabled)
+	}
+}
@@ -0,0 +1,35 @@
+package bitcoin
+
+type TransactionInfo struct {
+	TxnHash       string   `json:"txnHash" example:"4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b"`
+	Block         int64    `json:"block" example:"700000"`
+	BlockHash     string   `json:"blockHash" example:"0000000000000000000590fc0f3eba193a278534220b2b37e9849e1a770ca959"`
+	Confirmations int64    `json:"confirmations" example:"3"`
+	Outputs       []Output `json:"outputs"`
+}
+
+type Output struct {
+	Index   int     `json:"index" example:"0"`
+	Address string  `json:"address" example:"bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq"`
+	Amount  float64 `json:"amount" example:"0.5"`
+}
+
+// rawTransaction is the verbose result of getrawtransaction. Bitcoin Core before 22.0 puts addresses in a list.
+type rawTransaction struct {
+	TxID          string `json:"txid"`
+	BlockHash     string `json:"blockhash"`
+	Confirmations int64  `json:"confirmations"`
+	Vout          []struct {
+		Value        float64 `json:"value"`
+		N            int     `json:"n"`
+		ScriptPubKey struct {
+			Address   string   `json:"address"`
+			Addresses []string `json:"addresses"`
+		} `json:"scriptPubKey"`
+	} `json:"vout"`
+}
+
+type blockHeader struct {
+	Hash   string `json:"hash"`
+	Height int64  `json:"height"`
+}
@@ -0,0 +1,117 @@
+package bitcoin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+var ErrBitcoinDisabled = errors.New("bitcoin rpc isn't configured")
+
+// RPCClient calls Bitcoin Core JSON-RPC. It works against a full node with txindex, a regtest node or any stub speaking the same protocol.
+// It doesn't use client.Client because that logs request headers, which carry the rpc password here.
+type RPCClient struct {
+	url        string
+	user       string
+	password   string
+	httpClient *http.Client
+	id         uint64
+}
+
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error"`
+}
+
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("bitcoin rpc error %d: %s", e.Code, e.Message)
+}
+
+// ErrCodeNoTransaction is returned by getrawtransaction for unknown txid.
+const ErrCodeNoTransaction = -5
+
+func NewRPCClient(url string, user string, password string, timeout time.Duration) *RPCClient {
+	return &RPCClient{
+		url:      url,
+		user:     user,
+		password: password,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// NewRPCClientFromConfig returns nil client when "bitcoin.rpc.url" isn't set.
+func NewRPCClientFromConfig() *RPCClient {
+	url := viper.GetString("bitcoin.rpc.url")
+	if url == "" {
+		return nil
+	}
+	return NewRPCClient(url, viper.GetString("bitcoin.rpc.user"), viper.GetString("bitcoin.rpc.password"), viper.GetDuration("bitcoin.rpc.timeout"))
+}
+
+// Call runs method with params and decodes its result into result.
+func (c *RPCClient) Call(ctx context.Context, method string, result interface{}, params ...interface{}) error {
+	if params == nil {
+		params = make([]interface{}, 0)
+	}
+	body, err := json.Marshal(rpcRequest{
+		JSONRPC: "1.0",
+		ID:      atomic.AddUint64(&c.id, 1),
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.user != "" {
+		httpReq.SetBasicAuth(c.user, c.password)
+	}
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	// bitcoind answers rpc errors with http 404/500 and a json body, so the body is read before the status.
+	var rpcResp rpcResponse
+	if err := json.Unmarshal(respBody, &rpcResp); err != nil {
+		return errors.Wrapf(err, "bitcoin rpc %s returned http %d", method, resp.StatusCode)
+	}
+	if rpcResp.Error != nil {
+		return rpcResp.Error
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(rpcResp.Result, result)
+}
@@ -0,0 +1,95 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+var ErrInvalidRound = errors.New("invalid price feed round")
+
+// RoundData is the latest round of a Chainlink aggregator with its answer scaled by the feed's decimals.
+type RoundData struct {
+	Feed      string
+	RoundID   *big.Int
+	Answer    float64
+	UpdatedAt time.Time
+}
+
+// QueryLatestRoundClientFn reads latestRoundData of the AggregatorV3Interface feed on the chain. A round that isn't complete,
+// was answered in an earlier round or has a non-positive answer is ErrInvalidRound.
+type QueryLatestRoundClientFn func(ctx context.Context, chainId int, feed string) (*RoundData, error)
+
+func NewQueryLatestRoundClientFn(registry *ChainRegistry) QueryLatestRoundClientFn {
+	// decimals of a feed never change, they're read once per chain and feed.
+	var decimals sync.Map
+	return func(ctx context.Context, chainId int, feed string) (*RoundData, error) {
+		chain, err := registry.Chain(chainId)
+		if err != nil {
+			return nil, err
+		}
+		if !IsValidAddress(feed) {
+			return nil, errors.Errorf("price feed '%s' is not an address", feed)
+		}
+		aggregatorAbi, err := abi.JSON(strings.NewReader(aggregatorV3Abi))
+		if err != nil {
+			return nil, err
+		}
+		address := common.HexToAddress(feed)
+		call := func(method string) ([]interface{}, error) {
+			data, err := aggregatorAbi.Pack(method)
+			if err != nil {
+				return nil, err
+			}
+			output, err := chain.Client.CallContract(ctx, ethereum.CallMsg{To: &address, Data: data}, nil)
+			if err != nil {
+				return nil, err
+			}
+			return aggregatorAbi.Unpack(method, output)
+		}
+
+		key := fmt.Sprintf("%d:%s", chainId, address.Hex())
+		scale, ok := decimals.Load(key)
+		if !ok {
+			results, err := call("decimals")
+			if err != nil {
+				return nil, errors.Wrapf(err, "decimals of %s", feed)
+			}
+			scale = int(results[0].(uint8))
+			decimals.Store(key, scale)
+		}
+
+		results, err := call("latestRoundData")
+		if err != nil {
+			return nil, errors.Wrapf(err, "latestRoundData of %s", feed)
+		}
+		roundId := results[0].(*big.Int)
+		answer := results[1].(*big.Int)
+		updatedAt := results[3].(*big.Int)
+		answeredInRound := results[4].(*big.Int)
+		switch {
+		case updatedAt.Sign() == 0:
+			return nil, errors.Wrapf(ErrInvalidRound, "round %s of %s isn't complete", roundId, feed)
+		case answeredInRound.Cmp(roundId) < 0:
+			return nil, errors.Wrapf(ErrInvalidRound, "round %s of %s was answered in round %s", roundId, feed, answeredInRound)
+		case answer.Sign() <= 0:
+			return nil, errors.Wrapf(ErrInvalidRound, "round %s of %s answered %s", roundId, feed, answer)
+		}
+
+		value, _ := ToDecimal(answer, scale.(int)).Float64()
+		return &RoundData{
+			Feed:      feed,
+			RoundID:   roundId,
+			Answer:    value,
+			UpdatedAt: time.Unix(updatedAt.Int64(), 0),
+		}, nil
+	}
+}
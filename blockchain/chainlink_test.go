@@ -0,0 +1,127 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"lending-engine/blockchain/chaintest"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// feedChain is a simulated chain registered as chain 1337 whose key deploys and updates aggregator mocks.
+type feedChain struct {
+	registry *ChainRegistry
+	auth     *bind.TransactOpts
+	client   *SimulatedChain
+}
+
+func newFeedChain(t *testing.T) *feedChain {
+	t.Helper()
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, big.NewInt(simulatedChainId))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewSimulatedChain(backends.NewSimulatedBackend(core.GenesisAlloc{
+		auth.From: {Balance: chaintest.Wei(1000)},
+	}, 30000000))
+	t.Cleanup(client.Close)
+	return &feedChain{
+		registry: NewChainRegistryFromChains(&Chain{ChainID: simulatedChainId, Name: "simulated", Client: client}),
+		auth:     auth,
+		client:   client,
+	}
+}
+
+// deployFeed deploys an aggregator mock of decimals and returns its address and contract.
+func (c *feedChain) deployFeed(t *testing.T, decimals uint8) (string, *bind.BoundContract) {
+	t.Helper()
+	address, contract, err := chaintest.DeployAggregator(c.auth, c.client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := contract.Transact(c.auth, "setDecimals", decimals); err != nil {
+		t.Fatal(err)
+	}
+	return address.Hex(), contract
+}
+
+func (c *feedChain) setRound(t *testing.T, contract *bind.BoundContract, roundId int64, answer *big.Int, updatedAt int64, answeredInRound int64) {
+	t.Helper()
+	if _, err := contract.Transact(c.auth, "setRoundData", big.NewInt(roundId), answer, big.NewInt(updatedAt), big.NewInt(updatedAt), big.NewInt(answeredInRound)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestQueryLatestRoundScalesByDecimals(t *testing.T) {
+	chain := newFeedChain(t)
+	queryLatestRound := NewQueryLatestRoundClientFn(chain.registry)
+	updatedAt := time.Now().Add(-time.Minute).Unix()
+
+	tests := []struct {
+		name     string
+		decimals uint8
+		answer   *big.Int
+		want     float64
+	}{
+		// BTC/USD feeds answer with 8 decimals.
+		{name: "8 decimals", decimals: 8, answer: big.NewInt(3000012345678), want: 30000.12345678},
+		// ETH-quoted feeds answer with 18 decimals.
+		{name: "18 decimals", decimals: 18, answer: new(big.Int).Mul(big.NewInt(25), new(big.Int).Exp(big.NewInt(10), big.NewInt(15), nil)), want: 0.025},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed, contract := chain.deployFeed(t, tt.decimals)
+			chain.setRound(t, contract, 7, tt.answer, updatedAt, 7)
+
+			round, err := queryLatestRound(context.Background(), simulatedChainId, feed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if round.Answer != tt.want {
+				t.Errorf("answer = %v, want %v", round.Answer, tt.want)
+			}
+			if round.RoundID.Int64() != 7 || round.UpdatedAt.Unix() != updatedAt {
+				t.Errorf("round %s updated at %d, want 7 updated at %d", round.RoundID, round.UpdatedAt.Unix(), updatedAt)
+			}
+		})
+	}
+}
+
+func TestQueryLatestRoundRejectsInvalidRound(t *testing.T) {
+	chain := newFeedChain(t)
+	queryLatestRound := NewQueryLatestRoundClientFn(chain.registry)
+	updatedAt := time.Now().Unix()
+
+	tests := []struct {
+		name            string
+		answer          int64
+		updatedAt       int64
+		answeredInRound int64
+	}{
+		{name: "incomplete round", answer: 3000000000000, updatedAt: 0, answeredInRound: 7},
+		{name: "answered in earlier round", answer: 3000000000000, updatedAt: updatedAt, answeredInRound: 6},
+		{name: "zero answer", answer: 0, updatedAt: updatedAt, answeredInRound: 7},
+		{name: "negative answer", answer: -1, updatedAt: updatedAt, answeredInRound: 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed, contract := chain.deployFeed(t, 8)
+			chain.setRound(t, contract, 7, big.NewInt(tt.answer), tt.updatedAt, tt.answeredInRound)
+
+			if _, err := queryLatestRound(context.Background(), simulatedChainId, feed); !errors.Is(err, ErrInvalidRound) {
+				t.Fatalf("err = %v, want %v", err, ErrInvalidRound)
+			}
+		})
+	}
+}
@@ -0,0 +1,476 @@
+// Package chaintest deploys the contracts the engine talks to on go-ethereum's simulated backend, so deposit, withdraw
+// and price feed code can be tested without a node. The contracts are written in EVM assembly because tests can't
+// depend on a solidity compiler; they implement only what the engine calls.
+package chaintest
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/asm"
+	"github.com/pkg/errors"
+)
+
+// TokenDecimals is the decimals of the token deployed by DeployToken.
+const TokenDecimals = 18
+
+const tokenAbi = `[
+{"inputs":[{"name":"account","type":"address"}],"name":"balanceOf","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"},
+{"inputs":[{"name":"recipient","type":"address"},{"name":"amount","type":"uint256"}],"name":"transfer","outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable","type":"function"},
+{"inputs":[{"name":"spender","type":"address"},{"name":"amount","type":"uint256"}],"name":"approve","outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable","type":"function"},
+{"inputs":[{"name":"owner","type":"address"},{"name":"spender","type":"address"}],"name":"allowance","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"},
+{"inputs":[{"name":"sender","type":"address"},{"name":"recipient","type":"address"},{"name":"amount","type":"uint256"}],"name":"transferFrom","outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable","type":"function"},
+{"inputs":[],"name":"decimals","outputs":[{"name":"","type":"uint8"}],"stateMutability":"view","type":"function"},
+{"inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"name":"mint","outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable","type":"function"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"from","type":"address"},{"indexed":true,"name":"to","type":"address"},{"indexed":false,"name":"value","type":"uint256"}],"name":"Transfer","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"owner","type":"address"},{"indexed":true,"name":"spender","type":"address"},{"indexed":false,"name":"value","type":"uint256"}],"name":"Approval","type":"event"}
+]`
+
+// tokenAsm is an ERC-20 with 18 decimals whose mint is open to anyone. Balances are kept at the slot of the holder's
+// address and allowances at keccak256(owner, spender).
+const tokenAsm = `
+	PUSH 0
+	CALLDATALOAD
+	PUSH 0xe0
+	SHR
+	DUP1
+	;; balanceOf(address)
+	PUSH 0x70a08231
+	EQ
+	JUMPI @balanceOf
+	DUP1
+	;; transfer(address,uint256)
+	PUSH 0xa9059cbb
+	EQ
+	JUMPI @transfer
+	DUP1
+	;; approve(address,uint256)
+	PUSH 0x095ea7b3
+	EQ
+	JUMPI @approve
+	DUP1
+	;; allowance(address,address)
+	PUSH 0xdd62ed3e
+	EQ
+	JUMPI @allowance
+	DUP1
+	;; transferFrom(address,address,uint256)
+	PUSH 0x23b872dd
+	EQ
+	JUMPI @transferFrom
+	DUP1
+	;; decimals()
+	PUSH 0x313ce567
+	EQ
+	JUMPI @decimals
+	DUP1
+	;; mint(address,uint256)
+	PUSH 0x40c10f19
+	EQ
+	JUMPI @mint
+fail:
+	PUSH 0
+	DUP1
+	REVERT
+balanceOf:
+	PUSH 4
+	CALLDATALOAD
+	SLOAD
+	PUSH 0
+	MSTORE
+	PUSH 0x20
+	PUSH 0
+	RETURN
+decimals:
+	PUSH 18
+	PUSH 0
+	MSTORE
+	PUSH 0x20
+	PUSH 0
+	RETURN
+allowance:
+	PUSH 4
+	CALLDATALOAD
+	PUSH 0
+	MSTORE
+	PUSH 0x24
+	CALLDATALOAD
+	PUSH 0x20
+	MSTORE
+	PUSH 0x40
+	PUSH 0
+	SHA3
+	SLOAD
+	PUSH 0
+	MSTORE
+	PUSH 0x20
+	PUSH 0
+	RETURN
+approve:
+	CALLER
+	PUSH 0
+	MSTORE
+	PUSH 4
+	CALLDATALOAD
+	PUSH 0x20
+	MSTORE
+	PUSH 0x24
+	CALLDATALOAD
+	DUP1
+	PUSH 0x40
+	PUSH 0
+	SHA3
+	SSTORE
+	PUSH 0x40
+	MSTORE
+	PUSH 4
+	CALLDATALOAD
+	CALLER
+	;; Approval(address,address,uint256)
+	PUSH 0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925
+	PUSH 0x20
+	PUSH 0x40
+	LOG3
+	JUMP @success
+mint:
+	PUSH 4
+	CALLDATALOAD
+	DUP1
+	SLOAD
+	PUSH 0x24
+	CALLDATALOAD
+	DUP1
+	PUSH 0
+	MSTORE
+	ADD
+	DUP2
+	SSTORE
+	PUSH 0
+	;; Transfer(address,address,uint256)
+	PUSH 0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef
+	PUSH 0x20
+	PUSH 0
+	LOG3
+	JUMP @success
+transfer:
+	CALLER
+	PUSH 4
+	CALLDATALOAD
+	PUSH 0x24
+	CALLDATALOAD
+	JUMP @move
+transferFrom:
+	PUSH 4
+	CALLDATALOAD
+	PUSH 0
+	MSTORE
+	CALLER
+	PUSH 0x20
+	MSTORE
+	PUSH 0x40
+	PUSH 0
+	SHA3
+	DUP1
+	SLOAD
+	PUSH 0x44
+	CALLDATALOAD
+	DUP1
+	DUP3
+	LT
+	JUMPI @fail
+	SWAP1
+	SUB
+	SWAP1
+	SSTORE
+	PUSH 4
+	CALLDATALOAD
+	PUSH 0x24
+	CALLDATALOAD
+	PUSH 0x44
+	CALLDATALOAD
+	JUMP @move
+move:
+	;; stack: amount, to, from
+	DUP3
+	SLOAD
+	DUP2
+	DUP2
+	LT
+	JUMPI @fail
+	DUP2
+	SWAP1
+	SUB
+	DUP4
+	SSTORE
+	DUP2
+	SLOAD
+	DUP2
+	ADD
+	DUP3
+	SSTORE
+	PUSH 0
+	MSTORE
+	SWAP1
+	;; Transfer(address,address,uint256)
+	PUSH 0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef
+	PUSH 0x20
+	PUSH 0
+	LOG3
+	JUMP @success
+success:
+	PUSH 1
+	PUSH 0
+	MSTORE
+	PUSH 0x20
+	PUSH 0
+	RETURN
+`
+
+const multisendAbi = `[{"inputs":[{"name":"token","type":"address"},{"name":"recipients","type":"address[]"},{"name":"values","type":"uint256[]"}],"name":"disperseToken","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+
+// multisendAsm is disperseToken of Disperse, it pulls every value from the caller to its recipient with transferFrom.
+const multisendAsm = `
+	PUSH 0
+	CALLDATALOAD
+	PUSH 0xe0
+	SHR
+	;; disperseToken(address,address[],uint256[])
+	PUSH 0xc73a2d60
+	EQ
+	JUMPI @disperse
+fail:
+	PUSH 0
+	DUP1
+	REVERT
+disperse:
+	PUSH 0x24
+	CALLDATALOAD
+	PUSH 4
+	ADD
+	PUSH 0x44
+	CALLDATALOAD
+	PUSH 4
+	ADD
+	DUP2
+	CALLDATALOAD
+	PUSH 0
+loop:
+	;; stack: i, n, values, recipients
+	DUP2
+	DUP2
+	LT
+	ISZERO
+	JUMPI @done
+	;; transferFrom(address,address,uint256)
+	PUSH 0x23b872dd
+	PUSH 0xe0
+	SHL
+	PUSH 0
+	MSTORE
+	CALLER
+	PUSH 4
+	MSTORE
+	DUP1
+	PUSH 1
+	ADD
+	PUSH 5
+	SHL
+	DUP1
+	DUP6
+	ADD
+	CALLDATALOAD
+	PUSH 0x24
+	MSTORE
+	DUP4
+	ADD
+	CALLDATALOAD
+	PUSH 0x44
+	MSTORE
+	PUSH 0x20
+	PUSH 0
+	PUSH 0x64
+	PUSH 0
+	PUSH 0
+	PUSH 4
+	CALLDATALOAD
+	GAS
+	CALL
+	ISZERO
+	JUMPI @fail
+	PUSH 0
+	MLOAD
+	ISZERO
+	JUMPI @fail
+	PUSH 1
+	ADD
+	JUMP @loop
+done:
+	STOP
+`
+
+const aggregatorAbi = `[
+{"inputs":[],"name":"decimals","outputs":[{"name":"","type":"uint8"}],"stateMutability":"view","type":"function"},
+{"inputs":[],"name":"latestRoundData","outputs":[{"name":"roundId","type":"uint80"},{"name":"answer","type":"int256"},{"name":"startedAt","type":"uint256"},{"name":"updatedAt","type":"uint256"},{"name":"answeredInRound","type":"uint80"}],"stateMutability":"view","type":"function"},
+{"inputs":[{"name":"decimals","type":"uint8"}],"name":"setDecimals","outputs":[],"stateMutability":"nonpayable","type":"function"},
+{"inputs":[{"name":"roundId","type":"uint80"},{"name":"answer","type":"int256"},{"name":"startedAt","type":"uint256"},{"name":"updatedAt","type":"uint256"},{"name":"answeredInRound","type":"uint80"}],"name":"setRoundData","outputs":[],"stateMutability":"nonpayable","type":"function"}
+]`
+
+// aggregatorAsm is a Chainlink AggregatorV3Interface mock whose decimals and latest round are set directly, so tests can
+// also build the incomplete and carried-over rounds a real feed rarely returns.
+const aggregatorAsm = `
+	PUSH 0
+	CALLDATALOAD
+	PUSH 0xe0
+	SHR
+	DUP1
+	;; decimals()
+	PUSH 0x313ce567
+	EQ
+	JUMPI @decimals
+	DUP1
+	;; latestRoundData()
+	PUSH 0xfeaf968c
+	EQ
+	JUMPI @latestRoundData
+	DUP1
+	;; setDecimals(uint8)
+	PUSH 0x7a1395aa
+	EQ
+	JUMPI @setDecimals
+	DUP1
+	;; setRoundData(uint80,int256,uint256,uint256,uint80)
+	PUSH 0x1c12940a
+	EQ
+	JUMPI @setRoundData
+	PUSH 0
+	DUP1
+	REVERT
+decimals:
+	PUSH 5
+	SLOAD
+	PUSH 0
+	MSTORE
+	PUSH 0x20
+	PUSH 0
+	RETURN
+latestRoundData:
+	PUSH 0
+	SLOAD
+	PUSH 0
+	MSTORE
+	PUSH 1
+	SLOAD
+	PUSH 0x20
+	MSTORE
+	PUSH 2
+	SLOAD
+	PUSH 0x40
+	MSTORE
+	PUSH 3
+	SLOAD
+	PUSH 0x60
+	MSTORE
+	PUSH 4
+	SLOAD
+	PUSH 0x80
+	MSTORE
+	PUSH 0xa0
+	PUSH 0
+	RETURN
+setDecimals:
+	PUSH 4
+	CALLDATALOAD
+	PUSH 5
+	SSTORE
+	STOP
+setRoundData:
+	PUSH 0x04
+	CALLDATALOAD
+	PUSH 0
+	SSTORE
+	PUSH 0x24
+	CALLDATALOAD
+	PUSH 1
+	SSTORE
+	PUSH 0x44
+	CALLDATALOAD
+	PUSH 2
+	SSTORE
+	PUSH 0x64
+	CALLDATALOAD
+	PUSH 3
+	SSTORE
+	PUSH 0x84
+	CALLDATALOAD
+	PUSH 4
+	SSTORE
+	STOP
+`
+
+var (
+	TokenABI      = mustParseABI(tokenAbi)
+	MultisendABI  = mustParseABI(multisendAbi)
+	AggregatorABI = mustParseABI(aggregatorAbi)
+)
+
+func mustParseABI(abiJSON string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// DeployToken deploys the ERC-20 of tokenAsm.
+func DeployToken(auth *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *bind.BoundContract, error) {
+	return deploy(auth, backend, TokenABI, tokenAsm)
+}
+
+// DeployMultisend deploys the Disperse contract of multisendAsm.
+func DeployMultisend(auth *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *bind.BoundContract, error) {
+	return deploy(auth, backend, MultisendABI, multisendAsm)
+}
+
+// DeployAggregator deploys the aggregator mock of aggregatorAsm with no round yet.
+func DeployAggregator(auth *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *bind.BoundContract, error) {
+	return deploy(auth, backend, AggregatorABI, aggregatorAsm)
+}
+
+func deploy(auth *bind.TransactOpts, backend bind.ContractBackend, contractAbi abi.ABI, source string) (common.Address, *bind.BoundContract, error) {
+	code, err := initCode(source)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	address, _, contract, err := bind.DeployContract(auth, contractAbi, code, backend)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	return address, contract, nil
+}
+
+// initCode compiles runtime assembly and prefixes it with the constructor that returns it.
+func initCode(source string) ([]byte, error) {
+	compiler := asm.NewCompiler(false)
+	compiler.Feed(asm.Lex([]byte(strings.TrimPrefix(source, "\n")), false))
+	runtime, errs := compiler.Compile()
+	if len(errs) > 0 {
+		return nil, errors.Errorf("cannot compile contract: %v", errs)
+	}
+	code, err := hexutil.Decode("0x" + runtime)
+	if err != nil {
+		return nil, err
+	}
+	// PUSH2 len DUP1 PUSH1 12 PUSH1 0 CODECOPY PUSH1 0 RETURN
+	constructor := hexutil.MustDecode(fmt.Sprintf("0x61%04x80600c6000396000f3", len(code)))
+	return append(constructor, code...), nil
+}
+
+// Wei converts volume in token units to the token's smallest unit.
+func Wei(volume int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(volume), new(big.Int).Exp(big.NewInt(10), big.NewInt(TokenDecimals), nil))
+}
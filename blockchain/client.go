@@ -7,26 +7,21 @@ import (
 	"math/big"
 	"strings"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/spf13/viper"
 )
 
 type QueryTransactionClientFn func(ctx context.Context, chainId int, txnHash string) (*TransactionInfo, bool, error)
 
-func NewQueryTransactionClientFn(ethCli *ethclient.Client, bscCli *ethclient.Client) QueryTransactionClientFn {
+func NewQueryTransactionClientFn(registry *ChainRegistry) QueryTransactionClientFn {
 	return func(ctx context.Context, chainId int, txnHash string) (*TransactionInfo, bool, error) {
-		var cli *ethclient.Client
-		switch chainId {
-		case viper.GetInt("blockchain.ethereum.chainId"):
-			cli = ethCli
-		case viper.GetInt("blockchain.binance.chainId"):
-			cli = bscCli
-		default:
-			cli = bscCli
+		chain, err := registry.Chain(chainId)
+		if err != nil {
+			return nil, false, err
 		}
+		cli := chain.Client
 
 		tx, pending, err := cli.TransactionByHash(ctx, common.HexToHash(txnHash))
 		if err != nil {
@@ -41,7 +36,7 @@ func NewQueryTransactionClientFn(ethCli *ethclient.Client, bscCli *ethclient.Cli
 			return nil, pending, err
 		}
 
-		msg, err := tx.AsMessage(types.NewEIP155Signer(tx.ChainId()), tx.GasFeeCap())
+		msg, err := tx.AsMessage(types.LatestSignerForChainID(tx.ChainId()), tx.GasFeeCap())
 		if err != nil {
 			return nil, pending, err
 		}
@@ -51,6 +46,18 @@ func NewQueryTransactionClientFn(ethCli *ethclient.Client, bscCli *ethclient.Cli
 			return nil, pending, err
 		}
 
+		head, err := cli.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return nil, pending, err
+		}
+		confirmations := new(big.Int).Sub(head.Number, receipt.BlockNumber).Int64() + 1
+		if confirmations < chain.Confirmations {
+			return nil, true, nil
+		}
+
+		if tx.To() == nil || len(tx.Data()) < 4 {
+			return nil, pending, fmt.Errorf("txn %s isn't a token transfer", txnHash)
+		}
 		data := fmt.Sprintf("%x", tx.Data())
 		abi, err := abi.JSON(strings.NewReader(bep20Abi))
 		if err != nil {
@@ -82,12 +89,19 @@ func NewQueryTransactionClientFn(ethCli *ethclient.Client, bscCli *ethclient.Cli
 		value, _ := ToDecimal(tx.Value(), 18).Float64()
 		gasPrice, _ := ToDecimal(tx.GasPrice(), 18).Float64()
 		txnFee, _ := ToDecimal(CalcGasCost(tx.Gas(), tx.GasPrice()), 18).Float64()
-		amount, _ := ToDecimal(params[1].(*big.Int), 18).Float64()
+		decimals := 18
+		for _, token := range chain.Tokens {
+			if token.Address == *tx.To() {
+				decimals = token.Decimals
+			}
+		}
+		amount, _ := ToDecimal(params[1].(*big.Int), decimals).Float64()
 
 		txnInfo := TransactionInfo{
 			TxnHash:        tx.Hash().Hex(),
 			Status:         int64(receipt.Status),
 			Block:          receipt.BlockNumber.Int64(),
+			BlockHash:      receipt.BlockHash.Hex(),
 			Timestamp:      int64(block.Time()),
 			From:           msg.From().Hex(),
 			InteractedWith: tx.To().Hex(), // Token address
@@ -96,13 +110,214 @@ func NewQueryTransactionClientFn(ethCli *ethclient.Client, bscCli *ethclient.Cli
 				To:     params[0].(common.Address).Hex(),
 				Amount: amount,
 			},
-			Value:    value,                  // BNB หน่วย ether
-			TxnFee:   txnFee,                 // BNB
-			GasPrice: gasPrice,               // BNB
-			GasLimit: int64(tx.Gas()),        // amount
-			GasUsed:  int64(receipt.GasUsed), // amount
-			Nonce:    int64(tx.Nonce()),
+			Value:         value,                  // BNB หน่วย ether
+			TxnFee:        txnFee,                 // BNB
+			GasPrice:      gasPrice,               // BNB
+			GasLimit:      int64(tx.Gas()),        // amount
+			GasUsed:       int64(receipt.GasUsed), // amount
+			Nonce:         int64(tx.Nonce()),
+			Confirmations: confirmations,
 		}
 		return &txnInfo, pending, nil
 	}
 }
+
+// QueryReceiptClientFn reports whether txnHash is unknown (dropped), pending, reverted or mined with enough confirmations.
+type QueryReceiptClientFn func(ctx context.Context, chainId int, txnHash string) (*ReceiptInfo, error)
+
+func NewQueryReceiptClientFn(registry *ChainRegistry) QueryReceiptClientFn {
+	return func(ctx context.Context, chainId int, txnHash string) (*ReceiptInfo, error) {
+		chain, err := registry.Chain(chainId)
+		if err != nil {
+			return nil, err
+		}
+		cli := chain.Client
+
+		receiptInfo := ReceiptInfo{
+			TxnHash: txnHash,
+		}
+		_, pending, err := cli.TransactionByHash(ctx, common.HexToHash(txnHash))
+		if err != nil {
+			if err == ethereum.NotFound {
+				return &receiptInfo, nil
+			}
+			return nil, err
+		}
+		receiptInfo.Found = true
+		if pending {
+			receiptInfo.Pending = true
+			return &receiptInfo, nil
+		}
+
+		receipt, err := cli.TransactionReceipt(ctx, common.HexToHash(txnHash))
+		if err != nil {
+			return nil, err
+		}
+		head, err := cli.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		receiptInfo.Status = receipt.Status
+		receiptInfo.Block = receipt.BlockNumber.Int64()
+		receiptInfo.BlockHash = receipt.BlockHash.Hex()
+		receiptInfo.Confirmations = new(big.Int).Sub(head.Number, receipt.BlockNumber).Int64() + 1
+		receiptInfo.Confirmed = receiptInfo.Confirmations >= chain.Confirmations
+		return &receiptInfo, nil
+	}
+}
+
+// QuerySafeBlockClientFn returns the latest block that already has the confirmations required by the chain.
+type QuerySafeBlockClientFn func(ctx context.Context, chainId int) (int64, error)
+
+func NewQuerySafeBlockClientFn(registry *ChainRegistry) QuerySafeBlockClientFn {
+	return func(ctx context.Context, chainId int) (int64, error) {
+		chain, err := registry.Chain(chainId)
+		if err != nil {
+			return 0, err
+		}
+		head, err := chain.Client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return 0, err
+		}
+		return head.Number.Int64() - chain.Confirmations + 1, nil
+	}
+}
+
+// QueryTransferLogClientFn returns Transfer events of every configured token on the chain sent to one of recipients between fromBlock and toBlock.
+type QueryTransferLogClientFn func(ctx context.Context, chainId int, fromBlock int64, toBlock int64, recipients []string) (*[]TransferLog, error)
+
+func NewQueryTransferLogClientFn(registry *ChainRegistry) QueryTransferLogClientFn {
+	return func(ctx context.Context, chainId int, fromBlock int64, toBlock int64, recipients []string) (*[]TransferLog, error) {
+		chain, err := registry.Chain(chainId)
+		if err != nil {
+			return nil, err
+		}
+		transferLogs := make([]TransferLog, 0)
+		if len(recipients) == 0 || len(chain.Tokens) == 0 {
+			return &transferLogs, nil
+		}
+
+		tokenAbi, err := abi.JSON(strings.NewReader(bep20Abi))
+		if err != nil {
+			return nil, err
+		}
+		symbols := make(map[common.Address]string)
+		contracts := make([]common.Address, 0, len(chain.Tokens))
+		for symbol, token := range chain.Tokens {
+			symbols[token.Address] = symbol
+			contracts = append(contracts, token.Address)
+		}
+		toTopics := make([]common.Hash, 0, len(recipients))
+		for _, recipient := range recipients {
+			toTopics = append(toTopics, common.HexToAddress(recipient).Hash())
+		}
+
+		logs, err := chain.Client.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: big.NewInt(fromBlock),
+			ToBlock:   big.NewInt(toBlock),
+			Addresses: contracts,
+			Topics:    [][]common.Hash{{tokenAbi.Events["Transfer"].ID}, nil, toTopics},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, log := range logs {
+			if log.Removed || len(log.Topics) != 3 {
+				continue
+			}
+			symbol := symbols[log.Address]
+			value := new(big.Int).SetBytes(log.Data)
+			amount, _ := ToDecimal(value, chain.Tokens[symbol].Decimals).Float64()
+			transferLogs = append(transferLogs, TransferLog{
+				TxnHash:        log.TxHash.Hex(),
+				Block:          int64(log.BlockNumber),
+				BlockHash:      log.BlockHash.Hex(),
+				CollateralType: symbol,
+				From:           common.BytesToAddress(log.Topics[1].Bytes()).Hex(),
+				To:             common.BytesToAddress(log.Topics[2].Bytes()).Hex(),
+				Amount:         amount,
+			})
+		}
+		return &transferLogs, nil
+	}
+}
+
+// QueryBlockHashClientFn returns hash of the canonical block at blockNumber.
+type QueryBlockHashClientFn func(ctx context.Context, chainId int, blockNumber int64) (string, error)
+
+func NewQueryBlockHashClientFn(registry *ChainRegistry) QueryBlockHashClientFn {
+	return func(ctx context.Context, chainId int, blockNumber int64) (string, error) {
+		chain, err := registry.Chain(chainId)
+		if err != nil {
+			return "", err
+		}
+		header, err := chain.Client.HeaderByNumber(ctx, big.NewInt(blockNumber))
+		if err != nil {
+			return "", err
+		}
+		return header.Hash().Hex(), nil
+	}
+}
+
+// QueryGasCostClientFn estimates the network fee of one token transfer on the chain, in its native coin.
+type QueryGasCostClientFn func(ctx context.Context, chainId int) (*GasCost, error)
+
+func NewQueryGasCostClientFn(registry *ChainRegistry) QueryGasCostClientFn {
+	return func(ctx context.Context, chainId int) (*GasCost, error) {
+		chain, err := registry.Chain(chainId)
+		if err != nil {
+			return nil, err
+		}
+		gasPrice, err := chain.Client.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, err
+		}
+		price, _ := ToDecimal(gasPrice, 18).Float64()
+		cost, _ := ToDecimal(CalcGasCost(chain.TransferGas, gasPrice), 18).Float64()
+		gasCost := GasCost{
+			Native:   chain.Native,
+			GasLimit: chain.TransferGas,
+			GasPrice: price,
+			Cost:     cost,
+		}
+		return &gasCost, nil
+	}
+}
+
+// QueryTokenBalanceClientFn sums balanceOf of collateral type token over owners on the chain.
+type QueryTokenBalanceClientFn func(ctx context.Context, chainId int, collateralType string, owners []string) (float64, error)
+
+func NewQueryTokenBalanceClientFn(registry *ChainRegistry) QueryTokenBalanceClientFn {
+	return func(ctx context.Context, chainId int, collateralType string, owners []string) (float64, error) {
+		chain, err := registry.Chain(chainId)
+		if err != nil {
+			return 0, err
+		}
+		token, err := chain.Token(collateralType)
+		if err != nil {
+			return 0, err
+		}
+		tokenAbi, err := abi.JSON(strings.NewReader(bep20Abi))
+		if err != nil {
+			return 0, err
+		}
+		total := new(big.Int)
+		for _, owner := range owners {
+			data, err := tokenAbi.Pack("balanceOf", common.HexToAddress(owner))
+			if err != nil {
+				return 0, err
+			}
+			output, err := chain.Client.CallContract(ctx, ethereum.CallMsg{To: &token.Address, Data: data}, nil)
+			if err != nil {
+				return 0, err
+			}
+			results, err := tokenAbi.Unpack("balanceOf", output)
+			if err != nil {
+				return 0, err
+			}
+			total.Add(total, results[0].(*big.Int))
+		}
+		balance, _ := ToDecimal(total, token.Decimals).Float64()
+		return balance, nil
+	}
+}
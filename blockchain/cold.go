@@ -0,0 +1,177 @@
+package blockchain
+
+import (
+	"context"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+var (
+	ErrColdWalletDisabled = errors.New("cold wallet isn't configured")
+	ErrSignedTxMismatch   = errors.New("signed transaction doesn't match withdraw")
+)
+
+// UnsignedTransaction is what the air-gapped signer needs to sign a token transfer from the cold wallet.
+// Numbers are decimal strings, fee fields are set by the transaction type.
+type UnsignedTransaction struct {
+	Type                 string `json:"type" example:"0x2"`
+	ChainID              int    `json:"chainId" example:"56"`
+	From                 string `json:"from" example:"0xa9B6D99bA92D7d691c6EF4f49A1DC909822Cee46"`
+	To                   string `json:"to" example:"0x7130d2A12B9BCbFAe4f2634d864A1Ee1Ce3Ead9c"`
+	Nonce                uint64 `json:"nonce" example:"12"`
+	Gas                  uint64 `json:"gas" example:"52000"`
+	GasPrice             string `json:"gasPrice,omitempty" example:"5000000000"`
+	MaxFeePerGas         string `json:"maxFeePerGas,omitempty" example:"60000000000"`
+	MaxPriorityFeePerGas string `json:"maxPriorityFeePerGas,omitempty" example:"1500000000"`
+	Value                string `json:"value" example:"0"`
+	Data                 string `json:"data" example:"0xa9059cbb000000000000000000000000c26880a0af2ea0c7e8130e6ec47af756465452e80000000000000000000000000000000000000000000000000de0b6b3a7640000"`
+}
+
+// ColdWalletAddress is "blockchain.cold-wallet.address", empty when withdrawals are only sent by the hot wallet.
+func ColdWalletAddress() string {
+	return viper.GetString("blockchain.cold-wallet.address")
+}
+
+// BuildUnsignedTransferClientFn builds token transfer of volume to address from the cold wallet, with the cold wallet's next nonce.
+type BuildUnsignedTransferClientFn func(ctx context.Context, chainId int, collateralType string, to string, volume float64) (*UnsignedTransaction, error)
+
+func NewBuildUnsignedTransferClientFn(registry *ChainRegistry) BuildUnsignedTransferClientFn {
+	return func(ctx context.Context, chainId int, collateralType string, to string, volume float64) (*UnsignedTransaction, error) {
+		from := ColdWalletAddress()
+		if from == "" {
+			return nil, ErrColdWalletDisabled
+		}
+		chain, err := registry.Chain(chainId)
+		if err != nil {
+			return nil, err
+		}
+		token, err := chain.Token(collateralType)
+		if err != nil {
+			return nil, err
+		}
+		data, err := transferData(common.HexToAddress(to), ToWei(volume, token.Decimals))
+		if err != nil {
+			return nil, err
+		}
+		nonce, err := chain.Client.PendingNonceAt(ctx, common.HexToAddress(from))
+		if err != nil {
+			return nil, err
+		}
+		gas, err := chain.Client.EstimateGas(ctx, ethereum.CallMsg{
+			From: common.HexToAddress(from),
+			To:   &token.Address,
+			Data: data,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot estimate gas")
+		}
+		tx, err := newTx(ctx, chain.Client, int64(chainId), nonce, token.Address, big.NewInt(0), gas, data)
+		if err != nil {
+			return nil, err
+		}
+		return newUnsignedTransaction(chainId, common.HexToAddress(from), tx), nil
+	}
+}
+
+// BroadcastSignedTransferClientFn checks that rawTransaction is signed by the cold wallet on the chain and transfers exactly volume
+// of collateral type to address, then broadcasts it and returns its hash. A mismatch returns ErrSignedTxMismatch.
+type BroadcastSignedTransferClientFn func(ctx context.Context, chainId int, rawTransaction string, collateralType string, to string, volume float64) (string, error)
+
+func NewBroadcastSignedTransferClientFn(registry *ChainRegistry) BroadcastSignedTransferClientFn {
+	return func(ctx context.Context, chainId int, rawTransaction string, collateralType string, to string, volume float64) (string, error) {
+		from := ColdWalletAddress()
+		if from == "" {
+			return "", ErrColdWalletDisabled
+		}
+		chain, err := registry.Chain(chainId)
+		if err != nil {
+			return "", err
+		}
+		token, err := chain.Token(collateralType)
+		if err != nil {
+			return "", err
+		}
+		tx, err := decodeSignedTransaction(chainId, rawTransaction, common.HexToAddress(from), token.Address, ErrSignedTxMismatch)
+		if err != nil {
+			return "", err
+		}
+		data, err := transferData(common.HexToAddress(to), ToWei(volume, token.Decimals))
+		if err != nil {
+			return "", err
+		}
+		if hexutil.Encode(tx.Data()) != hexutil.Encode(data) {
+			return "", errors.Wrapf(ErrSignedTxMismatch, "calldata isn't transfer of %f %s to %s", volume, collateralType, to)
+		}
+
+		if err := chain.Client.SendTransaction(ctx, tx); err != nil {
+			return "", err
+		}
+		return tx.Hash().Hex(), nil
+	}
+}
+
+func newUnsignedTransaction(chainId int, from common.Address, tx *types.Transaction) *UnsignedTransaction {
+	unsignedTransaction := UnsignedTransaction{
+		Type:    hexutil.EncodeUint64(uint64(tx.Type())),
+		ChainID: chainId,
+		From:    from.Hex(),
+		To:      tx.To().Hex(),
+		Nonce:   tx.Nonce(),
+		Gas:     tx.Gas(),
+		Value:   tx.Value().String(),
+		Data:    hexutil.Encode(tx.Data()),
+	}
+	if tx.Type() == types.DynamicFeeTxType {
+		unsignedTransaction.MaxFeePerGas = tx.GasFeeCap().String()
+		unsignedTransaction.MaxPriorityFeePerGas = tx.GasTipCap().String()
+	} else {
+		unsignedTransaction.GasPrice = tx.GasPrice().String()
+	}
+	return &unsignedTransaction
+}
+
+// decodeSignedTransaction decodes rawTransaction and checks it is signed by from on the chain and calls token without value.
+// Every mismatch wraps mismatch, calldata is left to the caller.
+func decodeSignedTransaction(chainId int, rawTransaction string, from common.Address, token common.Address, mismatch error) (*types.Transaction, error) {
+	raw, err := hexutil.Decode(rawTransaction)
+	if err != nil {
+		return nil, errors.Wrap(mismatch, err.Error())
+	}
+	var tx types.Transaction
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return nil, errors.Wrap(mismatch, err.Error())
+	}
+	if tx.ChainId().Int64() != int64(chainId) {
+		return nil, errors.Wrapf(mismatch, "chain id %s, expected %d", tx.ChainId().String(), chainId)
+	}
+	sender, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), &tx)
+	if err != nil {
+		return nil, errors.Wrap(mismatch, err.Error())
+	}
+	if sender != from {
+		return nil, errors.Wrapf(mismatch, "signed by %s, expected %s", sender.Hex(), from.Hex())
+	}
+	if tx.To() == nil || *tx.To() != token {
+		return nil, errors.Wrapf(mismatch, "not sent to token %s", token.Hex())
+	}
+	if tx.Value().Sign() != 0 {
+		return nil, errors.Wrap(mismatch, "value must be 0")
+	}
+	return &tx, nil
+}
+
+func transferData(to common.Address, amount *big.Int) ([]byte, error) {
+	tokenAbi, err := abi.JSON(strings.NewReader(bep20Abi))
+	if err != nil {
+		return nil, err
+	}
+	return tokenAbi.Pack("transfer", to, amount)
+}
@@ -0,0 +1,285 @@
+package blockchain
+
+import (
+	"context"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+var ErrHotWalletDisabled = errors.New("hot wallet isn't configured")
+
+// Executor signs transactions with the hot wallet key. Backend is any bind.ContractTransactor,
+// so the same code runs against ethclient or the simulated backend.
+type Executor struct {
+	key    *keystore.Key
+	mu     sync.Mutex
+	nonces map[int64]uint64
+}
+
+func NewExecutor(key *keystore.Key) *Executor {
+	return &Executor{
+		key:    key,
+		nonces: make(map[int64]uint64),
+	}
+}
+
+// NewExecutorFromKeystore decrypts the keystore file of "blockchain.hot-wallet". It returns nil executor when keystore isn't set.
+func NewExecutorFromKeystore() (*Executor, error) {
+	key, err := readKeystore("blockchain.hot-wallet")
+	if err != nil || key == nil {
+		return nil, err
+	}
+	return NewExecutor(key), nil
+}
+
+// readKeystore decrypts "<prefix>.keystore" with "<prefix>.passphrase". It returns nil key when keystore isn't set.
+func readKeystore(prefix string) (*keystore.Key, error) {
+	path := viper.GetString(prefix + ".keystore")
+	if path == "" {
+		return nil, nil
+	}
+	keyJSON, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := keystore.DecryptKey(keyJSON, viper.GetString(prefix+".passphrase"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot decrypt %s keystore", prefix)
+	}
+	return key, nil
+}
+
+func (e *Executor) Address() common.Address {
+	return e.key.Address
+}
+
+// TransferToken sends ERC-20 transfer(to, amount) from the hot wallet and returns the signed transaction once the node accepts it.
+func (e *Executor) TransferToken(ctx context.Context, backend bind.ContractTransactor, chainId int64, token common.Address, to common.Address, amount *big.Int) (*types.Transaction, error) {
+	tokenAbi, err := abi.JSON(strings.NewReader(bep20Abi))
+	if err != nil {
+		return nil, err
+	}
+	data, err := tokenAbi.Pack("transfer", to, amount)
+	if err != nil {
+		return nil, err
+	}
+	return e.Send(ctx, backend, chainId, token, big.NewInt(0), data)
+}
+
+// Send builds, signs and broadcasts a transaction. EIP-1559 fee is used when the latest header has base fee, otherwise legacy gas price.
+func (e *Executor) Send(ctx context.Context, backend bind.ContractTransactor, chainId int64, to common.Address, value *big.Int, data []byte) (*types.Transaction, error) {
+	return e.SendWithGasLimit(ctx, backend, chainId, to, value, data, 0)
+}
+
+// SendWithGasLimit is Send with a fixed gas limit, the gas is estimated when gasLimit is 0.
+func (e *Executor) SendWithGasLimit(ctx context.Context, backend bind.ContractTransactor, chainId int64, to common.Address, value *big.Int, data []byte, gasLimit uint64) (*types.Transaction, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	nonce, err := e.nonce(ctx, backend, chainId)
+	if err != nil {
+		return nil, err
+	}
+	if gasLimit == 0 {
+		gasLimit, err = backend.EstimateGas(ctx, ethereum.CallMsg{
+			From:  e.key.Address,
+			To:    &to,
+			Value: value,
+			Data:  data,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot estimate gas")
+		}
+	}
+	tx, err := newTx(ctx, backend, chainId, nonce, to, value, gasLimit, data)
+	if err != nil {
+		return nil, err
+	}
+	signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(big.NewInt(chainId)), e.key.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	if err := backend.SendTransaction(ctx, signedTx); err != nil {
+		// nonce may be out of sync with the node, read it again on next send.
+		delete(e.nonces, chainId)
+		return nil, err
+	}
+	e.nonces[chainId] = nonce + 1
+	return signedTx, nil
+}
+
+// nonce takes the higher of pending nonce on the node and the local counter, so back-to-back sends don't reuse a nonce before the node sees them.
+func (e *Executor) nonce(ctx context.Context, backend bind.ContractTransactor, chainId int64) (uint64, error) {
+	pending, err := backend.PendingNonceAt(ctx, e.key.Address)
+	if err != nil {
+		return 0, err
+	}
+	if local, ok := e.nonces[chainId]; ok && local > pending {
+		return local, nil
+	}
+	return pending, nil
+}
+
+// newTx builds an unsigned transaction. EIP-1559 fee is used when the latest header has base fee, otherwise legacy gas price.
+func newTx(ctx context.Context, backend bind.ContractTransactor, chainId int64, nonce uint64, to common.Address, value *big.Int, gasLimit uint64, data []byte) (*types.Transaction, error) {
+	head, err := backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if head.BaseFee != nil {
+		tip, err := backend.SuggestGasTipCap(ctx)
+		if err != nil {
+			return nil, err
+		}
+		feeCap := new(big.Int).Add(tip, new(big.Int).Mul(head.BaseFee, big.NewInt(2)))
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID:   big.NewInt(chainId),
+			Nonce:     nonce,
+			GasTipCap: tip,
+			GasFeeCap: feeCap,
+			Gas:       gasLimit,
+			To:        &to,
+			Value:     value,
+			Data:      data,
+		}), nil
+	}
+	gasPrice, err := backend.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewTx(&types.LegacyTx{
+		Nonce:    nonce,
+		GasPrice: gasPrice,
+		Gas:      gasLimit,
+		To:       &to,
+		Value:    value,
+		Data:     data,
+	}), nil
+}
+
+// Replace re-sends tx with the same nonce and bumped fee, so the stuck transaction is sped up.
+func (e *Executor) Replace(ctx context.Context, backend bind.ContractTransactor, chainId int64, tx *types.Transaction) (*types.Transaction, error) {
+	signer := types.LatestSignerForChainID(big.NewInt(chainId))
+	from, err := types.Sender(signer, tx)
+	if err != nil {
+		return nil, err
+	}
+	if from != e.key.Address {
+		return nil, errors.Errorf("transaction %s isn't sent from hot wallet", tx.Hash().Hex())
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	suggestTx, err := newTx(ctx, backend, chainId, tx.Nonce(), *tx.To(), tx.Value(), tx.Gas(), tx.Data())
+	if err != nil {
+		return nil, err
+	}
+	var replaceTx *types.Transaction
+	if tx.Type() == types.DynamicFeeTxType {
+		replaceTx = types.NewTx(&types.DynamicFeeTx{
+			ChainID:   big.NewInt(chainId),
+			Nonce:     tx.Nonce(),
+			GasTipCap: maxBig(bumpFee(tx.GasTipCap()), suggestTx.GasTipCap()),
+			GasFeeCap: maxBig(bumpFee(tx.GasFeeCap()), suggestTx.GasFeeCap()),
+			Gas:       tx.Gas(),
+			To:        tx.To(),
+			Value:     tx.Value(),
+			Data:      tx.Data(),
+		})
+	} else {
+		replaceTx = types.NewTx(&types.LegacyTx{
+			Nonce:    tx.Nonce(),
+			GasPrice: maxBig(bumpFee(tx.GasPrice()), suggestTx.GasPrice()),
+			Gas:      tx.Gas(),
+			To:       tx.To(),
+			Value:    tx.Value(),
+			Data:     tx.Data(),
+		})
+	}
+	signedTx, err := types.SignTx(replaceTx, signer, e.key.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	if err := backend.SendTransaction(ctx, signedTx); err != nil {
+		return nil, err
+	}
+	return signedTx, nil
+}
+
+// bumpFee adds 12.5%, a bit more than the 10% most nodes require to accept a replacement.
+func bumpFee(fee *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(9))
+	bumped.Div(bumped, big.NewInt(8))
+	return bumped.Add(bumped, big.NewInt(1))
+}
+
+func maxBig(x *big.Int, y *big.Int) *big.Int {
+	if x.Cmp(y) >= 0 {
+		return x
+	}
+	return y
+}
+
+type TransferTokenClientFn func(ctx context.Context, chainId int, collateralType string, to string, volume float64) (string, error)
+
+func NewTransferTokenClientFn(registry *ChainRegistry, executor *Executor) TransferTokenClientFn {
+	return func(ctx context.Context, chainId int, collateralType string, to string, volume float64) (string, error) {
+		if executor == nil {
+			return "", ErrHotWalletDisabled
+		}
+		chain, err := registry.Chain(chainId)
+		if err != nil {
+			return "", err
+		}
+		token, err := chain.Token(collateralType)
+		if err != nil {
+			return "", err
+		}
+		if !IsValidAddress(to) {
+			return "", errors.Errorf("invalid address '%s'", to)
+		}
+		tx, err := executor.TransferToken(ctx, chain.Client, int64(chain.ChainID), token.Address, common.HexToAddress(to), ToWei(volume, token.Decimals))
+		if err != nil {
+			return "", err
+		}
+		return tx.Hash().Hex(), nil
+	}
+}
+
+type SpeedUpTransactionClientFn func(ctx context.Context, chainId int, txnHash string) (string, error)
+
+func NewSpeedUpTransactionClientFn(registry *ChainRegistry, executor *Executor) SpeedUpTransactionClientFn {
+	return func(ctx context.Context, chainId int, txnHash string) (string, error) {
+		if executor == nil {
+			return "", ErrHotWalletDisabled
+		}
+		chain, err := registry.Chain(chainId)
+		if err != nil {
+			return "", err
+		}
+		tx, pending, err := chain.Client.TransactionByHash(ctx, common.HexToHash(txnHash))
+		if err != nil {
+			return "", errors.Wrapf(err, "transaction %s", txnHash)
+		}
+		if !pending {
+			return "", errors.Errorf("transaction %s has already mined", txnHash)
+		}
+		replaceTx, err := executor.Replace(ctx, chain.Client, int64(chain.ChainID), tx)
+		if err != nil {
+			return "", err
+		}
+		return replaceTx.Hash().Hex(), nil
+	}
+}
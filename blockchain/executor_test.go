@@ -0,0 +1,215 @@
+package blockchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"lending-engine/blockchain/chaintest"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const simulatedChainId = 1337
+
+// testChain is a simulated backend whose hot wallet holds ether and the test token.
+type testChain struct {
+	backend *backends.SimulatedBackend
+	key     *keystore.Key
+	auth    *bind.TransactOpts
+	token   common.Address
+}
+
+func newTestChain(t *testing.T) *testChain {
+	t.Helper()
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := newTestKey(privateKey)
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{
+		key.Address: {Balance: chaintest.Wei(1000)},
+	}, 30000000)
+	t.Cleanup(func() { backend.Close() })
+
+	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, big.NewInt(simulatedChainId))
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, contract, err := chaintest.DeployToken(auth, backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	if _, err := contract.Transact(auth, "mint", key.Address, chaintest.Wei(100)); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	return &testChain{
+		backend: backend,
+		key:     key,
+		auth:    auth,
+		token:   token,
+	}
+}
+
+func newTestKey(privateKey *ecdsa.PrivateKey) *keystore.Key {
+	return &keystore.Key{
+		Address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+		PrivateKey: privateKey,
+	}
+}
+
+func (c *testChain) balanceOf(t *testing.T, account common.Address) *big.Int {
+	t.Helper()
+	contract := bind.NewBoundContract(c.token, chaintest.TokenABI, c.backend, c.backend, c.backend)
+	var out []interface{}
+	if err := contract.Call(nil, &out, "balanceOf", account); err != nil {
+		t.Fatal(err)
+	}
+	return out[0].(*big.Int)
+}
+
+func (c *testChain) receipt(t *testing.T, tx *types.Transaction) *types.Receipt {
+	t.Helper()
+	receipt, err := c.backend.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		t.Fatalf("transaction %s isn't mined: %v", tx.Hash().Hex(), err)
+	}
+	return receipt
+}
+
+// laggingNode reports the nonce of the latest block as pending, like a node behind a load balancer that hasn't
+// seen the transactions just sent to it.
+type laggingNode struct {
+	*backends.SimulatedBackend
+}
+
+func (n laggingNode) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return n.NonceAt(ctx, account, nil)
+}
+
+// legacyNode hides the base fee of the latest header, like a chain before London.
+type legacyNode struct {
+	*backends.SimulatedBackend
+}
+
+func (n legacyNode) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	head, err := n.SimulatedBackend.HeaderByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	head = types.CopyHeader(head)
+	head.BaseFee = nil
+	return head, nil
+}
+
+// SuggestGasPrice of the simulated backend is 1 wei, below the base fee its blocks still enforce.
+func (n legacyNode) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	head, err := n.SimulatedBackend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).Mul(head.BaseFee, big.NewInt(2)), nil
+}
+
+func TestExecutorSendSequencesNonce(t *testing.T) {
+	chain := newTestChain(t)
+	executor := NewExecutor(chain.key)
+	ctx := context.Background()
+	backend := laggingNode{chain.backend}
+	recipient := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+
+	var txs []*types.Transaction
+	for i := 0; i < 3; i++ {
+		tx, err := executor.TransferToken(ctx, backend, simulatedChainId, chain.token, recipient, chaintest.Wei(1))
+		if err != nil {
+			t.Fatalf("send %d: %v", i, err)
+		}
+		txs = append(txs, tx)
+	}
+	start, err := chain.backend.NonceAt(ctx, chain.key.Address, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, tx := range txs {
+		if tx.Nonce() != start+uint64(i) {
+			t.Errorf("send %d has nonce %d, want %d", i, tx.Nonce(), start+uint64(i))
+		}
+	}
+	chain.backend.Commit()
+
+	for _, tx := range txs {
+		if receipt := chain.receipt(t, tx); receipt.Status != types.ReceiptStatusSuccessful {
+			t.Errorf("transaction %s failed", tx.Hash().Hex())
+		}
+	}
+	if got := chain.balanceOf(t, recipient); got.Cmp(chaintest.Wei(3)) != 0 {
+		t.Errorf("recipient balance = %s, want %s", got, chaintest.Wei(3))
+	}
+}
+
+func TestExecutorSendFee(t *testing.T) {
+	tests := []struct {
+		name    string
+		backend func(*backends.SimulatedBackend) bind.ContractTransactor
+		txType  uint8
+	}{
+		{
+			name:    "eip1559",
+			backend: func(b *backends.SimulatedBackend) bind.ContractTransactor { return b },
+			txType:  types.DynamicFeeTxType,
+		},
+		{
+			name:    "legacy",
+			backend: func(b *backends.SimulatedBackend) bind.ContractTransactor { return legacyNode{b} },
+			txType:  types.LegacyTxType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := newTestChain(t)
+			executor := NewExecutor(chain.key)
+			ctx := context.Background()
+			recipient := common.HexToAddress("0x00000000000000000000000000000000000000a3")
+
+			tx, err := executor.TransferToken(ctx, tt.backend(chain.backend), simulatedChainId, chain.token, recipient, chaintest.Wei(2))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tx.Type() != tt.txType {
+				t.Fatalf("transaction type = %d, want %d", tx.Type(), tt.txType)
+			}
+			head, err := chain.backend.HeaderByNumber(ctx, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			switch tt.txType {
+			case types.DynamicFeeTxType:
+				wantCap := new(big.Int).Add(tx.GasTipCap(), new(big.Int).Mul(head.BaseFee, big.NewInt(2)))
+				if tx.GasFeeCap().Cmp(wantCap) != 0 {
+					t.Errorf("fee cap = %s, want %s", tx.GasFeeCap(), wantCap)
+				}
+			case types.LegacyTxType:
+				if tx.GasPrice().Cmp(head.BaseFee) <= 0 {
+					t.Errorf("gas price %s isn't above base fee %s", tx.GasPrice(), head.BaseFee)
+				}
+			}
+			chain.backend.Commit()
+
+			if receipt := chain.receipt(t, tx); receipt.Status != types.ReceiptStatusSuccessful {
+				t.Fatalf("transaction %s failed", tx.Hash().Hex())
+			}
+			if got := chain.balanceOf(t, recipient); got.Cmp(chaintest.Wei(2)) != 0 {
+				t.Errorf("recipient balance = %s, want %s", got, chaintest.Wei(2))
+			}
+		})
+	}
+}
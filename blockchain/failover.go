@@ -0,0 +1,346 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/pkg/errors"
+)
+
+// latencyWeight is the weight of the newest sample in the moving average of latency and error rate.
+const latencyWeight = 0.2
+
+// endpoint keeps health of one rpc url.
+type endpoint struct {
+	url       string
+	client    *ethclient.Client
+	latency   time.Duration
+	errorRate float64
+	failures  int
+	downUntil time.Time
+}
+
+func (e *endpoint) isDown(now time.Time) bool {
+	return now.Before(e.downUntil)
+}
+
+// FailoverClient sends every call to the healthiest rpc endpoint of a chain and fails over to the next one on error or timeout.
+// Endpoints are ranked by error rate then latency. After maxFailures consecutive errors an endpoint is skipped for cooldown.
+// It implements bind.ContractBackend so it can be used by the executor and contract bindings.
+type FailoverClient struct {
+	mu          sync.Mutex
+	endpoints   []*endpoint
+	timeout     time.Duration
+	maxFailures int
+	cooldown    time.Duration
+	dialContext func(ctx context.Context, url string) (*ethclient.Client, error)
+}
+
+func NewFailoverClient(urls []string, timeout time.Duration, maxFailures int, cooldown time.Duration) *FailoverClient {
+	f := FailoverClient{
+		endpoints:   make([]*endpoint, 0, len(urls)),
+		timeout:     timeout,
+		maxFailures: maxFailures,
+		cooldown:    cooldown,
+		dialContext: ethclient.DialContext,
+	}
+	for _, url := range urls {
+		f.endpoints = append(f.endpoints, &endpoint{url: url})
+	}
+	return &f
+}
+
+// IsDown is true when every endpoint is in cooldown.
+func (f *FailoverClient) IsDown() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	for _, e := range f.endpoints {
+		if !e.isDown(now) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *FailoverClient) Close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, e := range f.endpoints {
+		if e.client != nil {
+			e.client.Close()
+		}
+	}
+}
+
+// ranked returns endpoints that are up before the ones in cooldown, each group ordered by error rate then latency.
+func (f *FailoverClient) ranked(now time.Time) []*endpoint {
+	endpoints := make([]*endpoint, len(f.endpoints))
+	copy(endpoints, f.endpoints)
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		a, b := endpoints[i], endpoints[j]
+		if a.isDown(now) != b.isDown(now) {
+			return !a.isDown(now)
+		}
+		if a.errorRate != b.errorRate {
+			return a.errorRate < b.errorRate
+		}
+		return a.latency < b.latency
+	})
+	return endpoints
+}
+
+func (f *FailoverClient) record(e *endpoint, latency time.Duration, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if e.latency == 0 {
+		e.latency = latency
+	} else {
+		e.latency = time.Duration((1-latencyWeight)*float64(e.latency) + latencyWeight*float64(latency))
+	}
+	if err == nil {
+		e.errorRate = (1 - latencyWeight) * e.errorRate
+		e.failures = 0
+		e.downUntil = time.Time{}
+		return
+	}
+	e.errorRate = (1-latencyWeight)*e.errorRate + latencyWeight
+	e.failures++
+	if e.failures >= f.maxFailures {
+		e.downUntil = time.Now().Add(f.cooldown)
+	}
+}
+
+// dial returns the client of e, connecting it first if needed. The connection is made without holding the lock so
+// a slow endpoint doesn't hold up calls to the others.
+func (f *FailoverClient) dial(ctx context.Context, e *endpoint) (*ethclient.Client, error) {
+	f.mu.Lock()
+	cli := e.client
+	f.mu.Unlock()
+	if cli != nil {
+		return cli, nil
+	}
+
+	cli, err := f.dialContext(ctx, e.url)
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	// another call connected it meanwhile, the first client is kept.
+	if e.client != nil {
+		cli.Close()
+		return e.client, nil
+	}
+	e.client = cli
+	return cli, nil
+}
+
+// do runs call on endpoints in rank order until one succeeds. Results that are answers from the node,
+// like ethereum.NotFound or a reverted call, are returned as is without trying other endpoints.
+func (f *FailoverClient) do(ctx context.Context, call func(ctx context.Context, cli *ethclient.Client) error) error {
+	f.mu.Lock()
+	endpoints := f.ranked(time.Now())
+	f.mu.Unlock()
+
+	errs := make([]string, 0, len(endpoints))
+	for _, e := range endpoints {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		callCtx, cancel := context.WithTimeout(ctx, f.timeout)
+		start := time.Now()
+		cli, err := f.dial(callCtx, e)
+		if err == nil {
+			err = call(callCtx, cli)
+		}
+		cancel()
+		if err == nil || isNodeAnswer(err) {
+			f.record(e, time.Since(start), nil)
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		f.record(e, time.Since(start), err)
+		errs = append(errs, fmt.Sprintf("%s: %s", e.url, err.Error()))
+	}
+	return errors.Errorf("every rpc endpoint failed (%s)", strings.Join(errs, ", "))
+}
+
+// isNodeAnswer tells errors that the node returned on purpose apart from transport or node failures.
+func isNodeAnswer(err error) bool {
+	if err == ethereum.NotFound {
+		return true
+	}
+	msg := err.Error()
+	for _, answer := range []string{"execution reverted", "nonce too low", "replacement transaction underpriced", "insufficient funds", "already known", "gas required exceeds"} {
+		if strings.Contains(msg, answer) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *FailoverClient) ChainID(ctx context.Context) (*big.Int, error) {
+	var result *big.Int
+	err := f.do(ctx, func(ctx context.Context, cli *ethclient.Client) (err error) {
+		result, err = cli.ChainID(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	var result *types.Block
+	err := f.do(ctx, func(ctx context.Context, cli *ethclient.Client) (err error) {
+		result, err = cli.BlockByNumber(ctx, number)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	var result *types.Header
+	err := f.do(ctx, func(ctx context.Context, cli *ethclient.Client) (err error) {
+		result, err = cli.HeaderByNumber(ctx, number)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	var result *types.Transaction
+	var pending bool
+	err := f.do(ctx, func(ctx context.Context, cli *ethclient.Client) (err error) {
+		result, pending, err = cli.TransactionByHash(ctx, hash)
+		return err
+	})
+	return result, pending, err
+}
+
+func (f *FailoverClient) TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	var result *types.Receipt
+	err := f.do(ctx, func(ctx context.Context, cli *ethclient.Client) (err error) {
+		result, err = cli.TransactionReceipt(ctx, hash)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	var result *big.Int
+	err := f.do(ctx, func(ctx context.Context, cli *ethclient.Client) (err error) {
+		result, err = cli.BalanceAt(ctx, account, blockNumber)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	var result []types.Log
+	err := f.do(ctx, func(ctx context.Context, cli *ethclient.Client) (err error) {
+		result, err = cli.FilterLogs(ctx, query)
+		return err
+	})
+	return result, err
+}
+
+// SubscribeFilterLogs needs a long-lived connection, so it's opened on the healthiest endpoint without failover.
+func (f *FailoverClient) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	f.mu.Lock()
+	e := f.ranked(time.Now())[0]
+	f.mu.Unlock()
+
+	cli, err := f.dial(ctx, e)
+	if err != nil {
+		return nil, err
+	}
+	return cli.SubscribeFilterLogs(ctx, query, ch)
+}
+
+func (f *FailoverClient) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	var result []byte
+	err := f.do(ctx, func(ctx context.Context, cli *ethclient.Client) (err error) {
+		result, err = cli.CodeAt(ctx, contract, blockNumber)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	var result []byte
+	err := f.do(ctx, func(ctx context.Context, cli *ethclient.Client) (err error) {
+		result, err = cli.CallContract(ctx, call, blockNumber)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	var result []byte
+	err := f.do(ctx, func(ctx context.Context, cli *ethclient.Client) (err error) {
+		result, err = cli.PendingCodeAt(ctx, account)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	var result uint64
+	err := f.do(ctx, func(ctx context.Context, cli *ethclient.Client) (err error) {
+		result, err = cli.PendingNonceAt(ctx, account)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	var result *big.Int
+	err := f.do(ctx, func(ctx context.Context, cli *ethclient.Client) (err error) {
+		result, err = cli.SuggestGasPrice(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	var result *big.Int
+	err := f.do(ctx, func(ctx context.Context, cli *ethclient.Client) (err error) {
+		result, err = cli.SuggestGasTipCap(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (f *FailoverClient) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	var result uint64
+	err := f.do(ctx, func(ctx context.Context, cli *ethclient.Client) (err error) {
+		result, err = cli.EstimateGas(ctx, call)
+		return err
+	})
+	return result, err
+}
+
+// SendTransaction may reach a node that already got the same transaction from a timed out attempt, "already known" is treated as sent.
+func (f *FailoverClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	err := f.do(ctx, func(ctx context.Context, cli *ethclient.Client) error {
+		return cli.SendTransaction(ctx, tx)
+	})
+	if err != nil && strings.Contains(err.Error(), "already known") {
+		return nil
+	}
+	return err
+}
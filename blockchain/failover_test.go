@@ -0,0 +1,192 @@
+package blockchain
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// stubNode answers eth_chainId over JSON-RPC after delay, or fails with 503 while down.
+type stubNode struct {
+	*httptest.Server
+	mu    sync.Mutex
+	calls int
+	delay time.Duration
+	down  bool
+}
+
+func newStubNode(t *testing.T, delay time.Duration) *stubNode {
+	t.Helper()
+	n := &stubNode{delay: delay}
+	n.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		n.mu.Lock()
+		n.calls++
+		delay, down := n.delay, n.down
+		n.mu.Unlock()
+
+		time.Sleep(delay)
+		if down {
+			http.Error(w, "node is syncing", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": "0x539"})
+	}))
+	t.Cleanup(n.Close)
+	return n
+}
+
+func (n *stubNode) setDown(down bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.down = down
+}
+
+func (n *stubNode) callCount() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.calls
+}
+
+func chainIdOf(t *testing.T, f *FailoverClient) {
+	t.Helper()
+	chainId, err := f.ChainID(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chainId.Int64() != simulatedChainId {
+		t.Fatalf("chain id = %s, want %d", chainId, simulatedChainId)
+	}
+}
+
+func TestFailoverClientPrefersLowerLatency(t *testing.T) {
+	slow := newStubNode(t, 50*time.Millisecond)
+	fast := newStubNode(t, 0)
+	f := NewFailoverClient([]string{slow.URL, fast.URL}, time.Second, 2, time.Minute)
+	defer f.Close()
+
+	// the first call goes to the first url, both are unranked.
+	for i := 0; i < 5; i++ {
+		chainIdOf(t, f)
+	}
+	if slow.callCount() != 1 || fast.callCount() != 4 {
+		t.Errorf("slow node got %d calls and fast node %d, want 1 and 4", slow.callCount(), fast.callCount())
+	}
+}
+
+func TestFailoverClientMovesToNextEndpoint(t *testing.T) {
+	first := newStubNode(t, 0)
+	second := newStubNode(t, 0)
+	f := NewFailoverClient([]string{first.URL, second.URL}, time.Second, 2, time.Minute)
+	defer f.Close()
+
+	first.setDown(true)
+	chainIdOf(t, f)
+	if first.callCount() != 1 || second.callCount() != 1 {
+		t.Fatalf("first node got %d calls and second %d, want one each", first.callCount(), second.callCount())
+	}
+
+	// a failing endpoint ranks after the healthy one even when it comes back.
+	first.setDown(false)
+	chainIdOf(t, f)
+	if first.callCount() != 1 || second.callCount() != 2 {
+		t.Errorf("first node got %d calls and second %d, want 1 and 2", first.callCount(), second.callCount())
+	}
+
+	// every endpoint failing is an error naming each of them.
+	first.setDown(true)
+	second.setDown(true)
+	_, err := f.ChainID(context.Background())
+	if err == nil || !strings.Contains(err.Error(), first.URL) || !strings.Contains(err.Error(), second.URL) {
+		t.Errorf("err = %v, want both endpoints failed", err)
+	}
+}
+
+func TestFailoverClientCooldown(t *testing.T) {
+	first := newStubNode(t, 0)
+	second := newStubNode(t, 0)
+	f := NewFailoverClient([]string{first.URL, second.URL}, time.Second, 2, 100*time.Millisecond)
+	defer f.Close()
+
+	// below maxFailures in a row an endpoint stays up.
+	first.setDown(true)
+	second.setDown(true)
+	if _, err := f.ChainID(context.Background()); err == nil {
+		t.Fatal("no error with every endpoint down")
+	}
+	if f.IsDown() {
+		t.Fatal("down after one failure of each endpoint, want up until 2")
+	}
+	if _, err := f.ChainID(context.Background()); err == nil {
+		t.Fatal("no error with every endpoint down")
+	}
+	if !f.IsDown() {
+		t.Fatal("up after 2 failures of each endpoint, want every endpoint in cooldown")
+	}
+
+	// an endpoint in cooldown is still tried as a last resort, and a success ends its cooldown.
+	second.setDown(false)
+	chainIdOf(t, f)
+	if f.IsDown() {
+		t.Error("down after a successful call")
+	}
+
+	// the first endpoint comes out of cooldown by itself.
+	time.Sleep(150 * time.Millisecond)
+	f.mu.Lock()
+	down := f.endpoints[0].isDown(time.Now())
+	f.mu.Unlock()
+	if down {
+		t.Error("first endpoint still in cooldown after it ended")
+	}
+}
+
+func TestFailoverClientDialsWithoutBlockingOtherCalls(t *testing.T) {
+	node := newStubNode(t, 0)
+	f := NewFailoverClient([]string{node.URL}, time.Second, 2, time.Minute)
+	defer f.Close()
+
+	dialing := make(chan struct{})
+	release := make(chan struct{})
+	f.dialContext = func(ctx context.Context, url string) (*ethclient.Client, error) {
+		close(dialing)
+		<-release
+		return ethclient.DialContext(ctx, url)
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, err := f.ChainID(context.Background())
+		done <- err
+	}()
+	<-dialing
+
+	checked := make(chan struct{})
+	go func() {
+		f.IsDown()
+		close(checked)
+	}()
+	select {
+	case <-checked:
+	case <-time.After(time.Second):
+		t.Error("IsDown blocked by a dial in progress")
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
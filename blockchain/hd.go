@@ -0,0 +1,80 @@
+package blockchain
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+var ErrHDWalletDisabled = errors.New("deposit xpub isn't configured")
+
+// AddressDeriver derives deposit addresses from the account-level extended public key (m/44'/60'/0'),
+// so only public keys are ever known by the engine.
+type AddressDeriver struct {
+	xpub *hdkeychain.ExtendedKey
+}
+
+func NewAddressDeriver(xpub string) (*AddressDeriver, error) {
+	key, err := hdkeychain.NewKeyFromString(xpub)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid xpub")
+	}
+	if key.IsPrivate() {
+		return nil, errors.New("deposit key must be an extended public key, not a private key")
+	}
+	return &AddressDeriver{
+		xpub: key,
+	}, nil
+}
+
+// NewAddressDeriverFromConfig returns nil deriver when "blockchain.hd.xpub" isn't set.
+func NewAddressDeriverFromConfig() (*AddressDeriver, error) {
+	xpub := viper.GetString("blockchain.hd.xpub")
+	if xpub == "" {
+		return nil, nil
+	}
+	return NewAddressDeriver(xpub)
+}
+
+// Derive returns the address of external chain index, m/44'/60'/0'/0/index.
+func (d *AddressDeriver) Derive(index uint32) (common.Address, error) {
+	if index >= hdkeychain.HardenedKeyStart {
+		return common.Address{}, fmt.Errorf("index %d is out of non-hardened range", index)
+	}
+	external, err := d.xpub.Child(0)
+	if err != nil {
+		return common.Address{}, err
+	}
+	child, err := external.Child(index)
+	if err != nil {
+		return common.Address{}, err
+	}
+	pub, err := child.ECPubKey()
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub.ToECDSA()), nil
+}
+
+func DerivationPath(index uint32) string {
+	return fmt.Sprintf("m/44'/60'/0'/0/%d", index)
+}
+
+type DeriveAddressFn func(index uint32) (string, error)
+
+func NewDeriveAddressFn(deriver *AddressDeriver) DeriveAddressFn {
+	return func(index uint32) (string, error) {
+		if deriver == nil {
+			return "", ErrHDWalletDisabled
+		}
+		address, err := deriver.Derive(index)
+		if err != nil {
+			return "", err
+		}
+		return address.Hex(), nil
+	}
+}
@@ -0,0 +1,75 @@
+package blockchain
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil/hdkeychain"
+)
+
+// testSeed is the BIP-39 seed of "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+// without passphrase.
+const testSeed = "5eb00bbddcf069084889a8ab9155568165f5c453ccb85e70811aaed6f6da5fc19a5ac40b389cd370d086206dec8aa6c43daea6690f20ad3d8d48b2d2ce9e38e4"
+
+// testAccountKey returns the extended key of m/44'/60'/0' of testSeed, private or neutered.
+func testAccountKey(t *testing.T, private bool) string {
+	t.Helper()
+	seed, err := hex.DecodeString(testSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := hdkeychain.NewMaster(seed, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, index := range []uint32{44, 60, 0} {
+		if key, err = key.Child(hdkeychain.HardenedKeyStart + index); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !private {
+		if key, err = key.Neuter(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return key.String()
+}
+
+func TestAddressDeriverDerive(t *testing.T) {
+	deriver, err := NewAddressDeriver(testAccountKey(t, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// addresses of m/44'/60'/0'/0/index every BIP-44 wallet derives from the test mnemonic.
+	tests := []struct {
+		index   uint32
+		address string
+	}{
+		{index: 0, address: "0x9858EfFD232B4033E47d90003D41EC34EcaEda94"},
+		{index: 1, address: "0x6Fac4D18c912343BF86fa7049364Dd4E424Ab9C0"},
+		{index: 2, address: "0xb6716976A3ebe8D39aCEB04372f22Ff8e6802D7A"},
+	}
+	for _, tt := range tests {
+		address, err := deriver.Derive(tt.index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if address.Hex() != tt.address {
+			t.Errorf("%s = %s, want %s", DerivationPath(tt.index), address.Hex(), tt.address)
+		}
+	}
+
+	if _, err := deriver.Derive(hdkeychain.HardenedKeyStart); err == nil {
+		t.Error("hardened index is derived from an xpub")
+	}
+}
+
+func TestNewAddressDeriverRejectsPrivateKey(t *testing.T) {
+	if _, err := NewAddressDeriver(testAccountKey(t, true)); err == nil {
+		t.Fatal("xprv is accepted as deposit key")
+	}
+	if _, err := NewAddressDeriver("xpub"); err == nil {
+		t.Fatal("invalid xpub is accepted")
+	}
+}
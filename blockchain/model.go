@@ -4,6 +4,7 @@ type TransactionInfo struct {
 	TxnHash        string        `json:"txnHash" example:"0xf5a3aa87c40b05e6a308b61186eeded8996b654a9895401b8089a2966b54f618"`
 	Status         int64         `json:"status" example:"1"`
 	Block          int64         `json:"block" example:"12870267"`
+	BlockHash      string        `json:"blockHash" example:"0x3ad2c1b9a5ed6e1e0a0e6a1b0c6a4cfd1c4e7b8e0c5a9b7d2c8e1f0a6b4d3c2e"`
 	Timestamp      int64         `json:"timestamp" example:"1527211625"`
 	From           string        `json:"from" example:"0x0dcf57635f6562897cba35168b232fb302de0748"`
 	InteractedWith string        `json:"interactWith" example:"0x2b54a9350de2bf0be86a09253d9382829e74084a"`
@@ -14,6 +15,7 @@ type TransactionInfo struct {
 	GasLimit       int64         `json:"gasLimit" example:"21000"`
 	GasUsed        int64         `json:"gasUsed" example:"21000"`
 	Nonce          int64         `json:"nonce" example:"629"`
+	Confirmations  int64         `json:"confirmations" example:"12"`
 }
 
 type TokenTransfer struct {
@@ -21,3 +23,31 @@ type TokenTransfer struct {
 	To     string  `json:"to" example:"0xa9b6d99ba92d7d691c6ef4f49a1dc909822cee46"`
 	Amount float64 `json:"amount" example:"123456789"`
 }
+
+type ReceiptInfo struct {
+	TxnHash       string `json:"txnHash" example:"0xf5a3aa87c40b05e6a308b61186eeded8996b654a9895401b8089a2966b54f618"`
+	Found         bool   `json:"found" example:"true"`
+	Pending       bool   `json:"pending" example:"false"`
+	Status        uint64 `json:"status" example:"1"`
+	Block         int64  `json:"block" example:"12870267"`
+	BlockHash     string `json:"blockHash" example:"0x3ad2c1b9a5ed6e1e0a0e6a1b0c6a4cfd1c4e7b8e0c5a9b7d2c8e1f0a6b4d3c2e"`
+	Confirmations int64  `json:"confirmations" example:"12"`
+	Confirmed     bool   `json:"confirmed" example:"true"`
+}
+
+type TransferLog struct {
+	TxnHash        string  `json:"txnHash" example:"0xf5a3aa87c40b05e6a308b61186eeded8996b654a9895401b8089a2966b54f618"`
+	Block          int64   `json:"block" example:"12870267"`
+	BlockHash      string  `json:"blockHash" example:"0x3ad2c1b9a5ed6e1e0a0e6a1b0c6a4cfd1c4e7b8e0c5a9b7d2c8e1f0a6b4d3c2e"`
+	CollateralType string  `json:"collateralType" example:"BTC"`
+	From           string  `json:"from" example:"0xC26880A0AF2EA0c7E8130e6EC47Af756465452E8"`
+	To             string  `json:"to" example:"0x9858EfFD232B4033E47d90003D41EC34EcaEda94"`
+	Amount         float64 `json:"amount" example:"0.5"`
+}
+
+type GasCost struct {
+	Native   string  `json:"native" example:"BNB"`
+	GasLimit uint64  `json:"gasLimit" example:"65000"`
+	GasPrice float64 `json:"gasPrice" example:"0.000000005"`
+	Cost     float64 `json:"cost" example:"0.000325"`
+}
@@ -0,0 +1,176 @@
+package blockchain
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+var (
+	ErrMultisendDisabled   = errors.New("multisend contract isn't configured")
+	ErrInsufficientBalance = errors.New("hot wallet balance is insufficient")
+)
+
+type Transfer struct {
+	To     string  `json:"to" example:"0xC26880A0AF2EA0c7E8130e6EC47Af756465452E8"`
+	Volume float64 `json:"volume" example:"0.5"`
+}
+
+// multisendMethod returns "blockchain.multisend.method" of "blockchain.multisend.abi", Disperse's disperseToken when they're empty.
+// It must take (address token, address[] recipients, uint256[] values) and pull tokens with transferFrom.
+func multisendMethod() (abi.ABI, string, error) {
+	abiJSON := viper.GetString("blockchain.multisend.abi")
+	if abiJSON == "" {
+		abiJSON = disperseAbi
+	}
+	multisendAbi, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return abi.ABI{}, "", errors.Wrap(err, "invalid multisend abi")
+	}
+	name := viper.GetString("blockchain.multisend.method")
+	if name == "" {
+		name = "disperseToken"
+	}
+	method, ok := multisendAbi.Methods[name]
+	if !ok {
+		return abi.ABI{}, "", errors.Errorf("multisend abi has no method '%s'", name)
+	}
+	if len(method.Inputs) != 3 || method.Inputs[0].Type.T != abi.AddressTy || method.Inputs[1].Type.T != abi.SliceTy || method.Inputs[2].Type.T != abi.SliceTy {
+		return abi.ABI{}, "", errors.Errorf("multisend method '%s' must take (address, address[], uint256[])", name)
+	}
+	return multisendAbi, name, nil
+}
+
+// QueryHotWalletBalanceClientFn returns collateral type balance of the hot wallet on the chain.
+type QueryHotWalletBalanceClientFn func(ctx context.Context, chainId int, collateralType string) (float64, error)
+
+func NewQueryHotWalletBalanceClientFn(registry *ChainRegistry, executor *Executor) QueryHotWalletBalanceClientFn {
+	queryTokenBalanceClientFn := NewQueryTokenBalanceClientFn(registry)
+	return func(ctx context.Context, chainId int, collateralType string) (float64, error) {
+		if executor == nil {
+			return 0, ErrHotWalletDisabled
+		}
+		return queryTokenBalanceClientFn(ctx, chainId, collateralType, []string{executor.Address().Hex()})
+	}
+}
+
+// MultisendTokenClientFn sends every transfer of collateral type from the hot wallet in one call to the chain's multisend contract
+// and returns its hash. The contract is approved for the batch total first when its allowance is short, and the batch is sent once
+// the approval is mined. The batch gas is estimated unless "blockchain.multisend.gas-limit" is set.
+type MultisendTokenClientFn func(ctx context.Context, chainId int, collateralType string, transfers []Transfer) (string, error)
+
+func NewMultisendTokenClientFn(registry *ChainRegistry, executor *Executor) MultisendTokenClientFn {
+	return func(ctx context.Context, chainId int, collateralType string, transfers []Transfer) (string, error) {
+		if executor == nil {
+			return "", ErrHotWalletDisabled
+		}
+		chain, err := registry.Chain(chainId)
+		if err != nil {
+			return "", err
+		}
+		if chain.Multisend == "" {
+			return "", ErrMultisendDisabled
+		}
+		token, err := chain.Token(collateralType)
+		if err != nil {
+			return "", err
+		}
+		multisendAbi, method, err := multisendMethod()
+		if err != nil {
+			return "", err
+		}
+
+		recipients := make([]common.Address, 0, len(transfers))
+		values := make([]*big.Int, 0, len(transfers))
+		total := new(big.Int)
+		for _, transfer := range transfers {
+			if !IsValidAddress(transfer.To) {
+				return "", errors.Errorf("invalid address '%s'", transfer.To)
+			}
+			value := ToWei(transfer.Volume, token.Decimals)
+			recipients = append(recipients, common.HexToAddress(transfer.To))
+			values = append(values, value)
+			total.Add(total, value)
+		}
+
+		tokenAbi, err := abi.JSON(strings.NewReader(bep20Abi))
+		if err != nil {
+			return "", err
+		}
+		balance, err := callUint(ctx, chain, tokenAbi, token.Address, "balanceOf", executor.Address())
+		if err != nil {
+			return "", err
+		}
+		if balance.Cmp(total) < 0 {
+			return "", errors.Wrapf(ErrInsufficientBalance, "%s balance %s, batch total %s", collateralType, ToDecimal(balance, token.Decimals).String(), ToDecimal(total, token.Decimals).String())
+		}
+		multisend := common.HexToAddress(chain.Multisend)
+		allowance, err := callUint(ctx, chain, tokenAbi, token.Address, "allowance", executor.Address(), multisend)
+		if err != nil {
+			return "", err
+		}
+		if allowance.Cmp(total) < 0 {
+			data, err := tokenAbi.Pack("approve", multisend, total)
+			if err != nil {
+				return "", err
+			}
+			approveTx, err := executor.Send(ctx, chain.Client, int64(chain.ChainID), token.Address, big.NewInt(0), data)
+			if err != nil {
+				return "", errors.Wrap(err, "cannot approve multisend")
+			}
+			// transferFrom of the batch reverts until the approval is mined, and so would its gas estimate.
+			if err := waitSuccess(ctx, chain, approveTx, viper.GetDuration("blockchain.multisend.approve-timeout")); err != nil {
+				return "", errors.Wrap(err, "cannot approve multisend")
+			}
+		}
+
+		data, err := multisendAbi.Pack(method, token.Address, recipients, values)
+		if err != nil {
+			return "", err
+		}
+		tx, err := executor.SendWithGasLimit(ctx, chain.Client, int64(chain.ChainID), multisend, big.NewInt(0), data, viper.GetUint64("blockchain.multisend.gas-limit"))
+		if err != nil {
+			return "", err
+		}
+		return tx.Hash().Hex(), nil
+	}
+}
+
+// waitSuccess waits up to timeout for tx to be mined and fails when it reverted.
+func waitSuccess(ctx context.Context, chain *Chain, tx *types.Transaction, timeout time.Duration) error {
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	receipt, err := bind.WaitMined(waitCtx, chain.Client, tx)
+	if err != nil {
+		return errors.Wrapf(err, "transaction %s isn't mined", tx.Hash().Hex())
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return errors.Errorf("transaction %s reverted", tx.Hash().Hex())
+	}
+	return nil
+}
+
+func callUint(ctx context.Context, chain *Chain, contractAbi abi.ABI, contract common.Address, method string, args ...interface{}) (*big.Int, error) {
+	data, err := contractAbi.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	output, err := chain.Client.CallContract(ctx, ethereum.CallMsg{To: &contract, Data: data}, nil)
+	if err != nil {
+		return nil, err
+	}
+	results, err := contractAbi.Unpack(method, output)
+	if err != nil {
+		return nil, err
+	}
+	return results[0].(*big.Int), nil
+}
@@ -0,0 +1,165 @@
+package blockchain
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"lending-engine/blockchain/chaintest"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+// latestNode mines on its own every few milliseconds and estimates gas against the latest block like a real node,
+// so a call depending on a transaction that isn't mined yet can't be estimated.
+type latestNode struct {
+	*backends.SimulatedBackend
+}
+
+func (n latestNode) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	pending, err := n.PendingNonceAt(ctx, call.From)
+	if err != nil {
+		return 0, err
+	}
+	latest, err := n.NonceAt(ctx, call.From, nil)
+	if err != nil {
+		return 0, err
+	}
+	if pending != latest {
+		return 0, errors.New("execution reverted")
+	}
+	return n.SimulatedBackend.EstimateGas(ctx, call)
+}
+
+func (n latestNode) Close() {}
+
+func newLatestNode(t *testing.T, backend *backends.SimulatedBackend) latestNode {
+	t.Helper()
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(20 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				backend.Commit()
+			}
+		}
+	}()
+	t.Cleanup(func() {
+		close(done)
+		<-stopped
+	})
+	return latestNode{backend}
+}
+
+func newMultisendChain(t *testing.T, chain *testChain) (*Chain, common.Address) {
+	t.Helper()
+	multisend, _, err := chaintest.DeployMultisend(chain.auth, chain.backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain.backend.Commit()
+	return &Chain{
+		ChainID:       simulatedChainId,
+		Name:          "simulated",
+		Confirmations: 1,
+		Multisend:     multisend.Hex(),
+		Tokens: map[string]Token{
+			"BTC": {Address: chain.token, Decimals: chaintest.TokenDecimals},
+		},
+		Client: newLatestNode(t, chain.backend),
+	}, multisend
+}
+
+func TestMultisendTokenClientFn(t *testing.T) {
+	viper.Set("blockchain.multisend.approve-timeout", "10s")
+	defer viper.Set("blockchain.multisend.approve-timeout", nil)
+
+	chain := newTestChain(t)
+	multisendChain, multisend := newMultisendChain(t, chain)
+	multisendTokenFn := NewMultisendTokenClientFn(NewChainRegistryFromChains(multisendChain), NewExecutor(chain.key))
+
+	recipients := []string{
+		"0x00000000000000000000000000000000000000b1",
+		"0x00000000000000000000000000000000000000b2",
+	}
+	txnHash, err := multisendTokenFn(context.Background(), simulatedChainId, "BTC", []Transfer{
+		{To: recipients[0], Volume: 1},
+		{To: recipients[1], Volume: 2.5},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx, _, err := chain.backend.TransactionByHash(context.Background(), common.HexToHash(txnHash))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := waitSuccess(context.Background(), multisendChain, tx, 10*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	wants := []*big.Int{chaintest.Wei(1), new(big.Int).Div(chaintest.Wei(5), big.NewInt(2))}
+	for i, recipient := range recipients {
+		if got := chain.balanceOf(t, common.HexToAddress(recipient)); got.Cmp(wants[i]) != 0 {
+			t.Errorf("balance of %s = %s, want %s", recipient, got, wants[i])
+		}
+	}
+
+	token := bind.NewBoundContract(chain.token, chaintest.TokenABI, chain.backend, chain.backend, chain.backend)
+	var out []interface{}
+	if err := token.Call(nil, &out, "allowance", chain.key.Address, multisend); err != nil {
+		t.Fatal(err)
+	}
+	if allowance := out[0].(*big.Int); allowance.Sign() != 0 {
+		t.Errorf("allowance left = %s, want 0", allowance)
+	}
+}
+
+func TestMultisendTokenClientFnGasLimit(t *testing.T) {
+	viper.Set("blockchain.multisend.approve-timeout", "10s")
+	viper.Set("blockchain.multisend.gas-limit", 400000)
+	defer viper.Set("blockchain.multisend.approve-timeout", nil)
+	defer viper.Set("blockchain.multisend.gas-limit", nil)
+
+	chain := newTestChain(t)
+	multisendChain, _ := newMultisendChain(t, chain)
+	multisendTokenFn := NewMultisendTokenClientFn(NewChainRegistryFromChains(multisendChain), NewExecutor(chain.key))
+
+	txnHash, err := multisendTokenFn(context.Background(), simulatedChainId, "BTC", []Transfer{
+		{To: "0x00000000000000000000000000000000000000b3", Volume: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, _, err := chain.backend.TransactionByHash(context.Background(), common.HexToHash(txnHash))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Gas() != 400000 {
+		t.Errorf("gas limit = %d, want 400000", tx.Gas())
+	}
+}
+
+func TestMultisendTokenClientFnInsufficientBalance(t *testing.T) {
+	chain := newTestChain(t)
+	multisendChain, _ := newMultisendChain(t, chain)
+	multisendTokenFn := NewMultisendTokenClientFn(NewChainRegistryFromChains(multisendChain), NewExecutor(chain.key))
+
+	_, err := multisendTokenFn(context.Background(), simulatedChainId, "BTC", []Transfer{
+		{To: "0x00000000000000000000000000000000000000b4", Volume: 101},
+	})
+	if !errors.Is(err, ErrInsufficientBalance) {
+		t.Fatalf("err = %v, want %v", err, ErrInsufficientBalance)
+	}
+}
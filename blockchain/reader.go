@@ -0,0 +1,68 @@
+package blockchain
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ChainReader is what deposit and withdraw verification read from a chain.
+type ChainReader interface {
+	TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error)
+	TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error)
+	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error)
+}
+
+// ChainClient is a ChainReader that can also call contracts and send transactions, it's the type of Chain.Client.
+// FailoverClient adapts ethclient for real nodes and SimulatedChain adapts the in-process simulated backend.
+type ChainClient interface {
+	ChainReader
+	bind.ContractBackend
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+	Close()
+}
+
+var (
+	_ ChainClient = (*ethclient.Client)(nil)
+	_ ChainClient = (*FailoverClient)(nil)
+	_ ChainClient = (*SimulatedChain)(nil)
+)
+
+// SimulatedChain runs a chain in process. Every sent transaction is mined into its own block right away,
+// so deposit and withdraw flows can go through without a node.
+type SimulatedChain struct {
+	*backends.SimulatedBackend
+}
+
+func NewSimulatedChain(backend *backends.SimulatedBackend) *SimulatedChain {
+	return &SimulatedChain{
+		SimulatedBackend: backend,
+	}
+}
+
+func (s *SimulatedChain) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := s.SimulatedBackend.SendTransaction(ctx, tx); err != nil {
+		return err
+	}
+	s.Commit()
+	return nil
+}
+
+// Mine commits n empty blocks, e.g. to give a transaction its confirmations.
+func (s *SimulatedChain) Mine(n int) {
+	for i := 0; i < n; i++ {
+		s.Commit()
+	}
+}
+
+func (s *SimulatedChain) Close() {
+	s.SimulatedBackend.Close()
+}
@@ -0,0 +1,178 @@
+package blockchain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+var (
+	ErrUnknownChain = errors.New("unknown chain id")
+	ErrUnknownToken = errors.New("unknown token")
+)
+
+type Chain struct {
+	ChainID       int
+	Name          string
+	RPCURLs       []string
+	Confirmations int64
+	Address       string
+	Custody       []string
+	Multisend     string
+	Native        string
+	TransferGas   uint64
+	Tokens        map[string]Token
+	Client        ChainClient
+}
+
+type Token struct {
+	Address  common.Address
+	Decimals int
+}
+
+// Token returns the contract of collateral type on this chain.
+func (c *Chain) Token(collateralType string) (Token, error) {
+	token, ok := c.Tokens[strings.ToUpper(collateralType)]
+	if !ok {
+		return Token{}, errors.Wrapf(ErrUnknownToken, "%s on chain %d", collateralType, c.ChainID)
+	}
+	return token, nil
+}
+
+// CustodyAddresses returns the deposit address of the chain and every other wallet holding collateral on it.
+func (c *Chain) CustodyAddresses() []string {
+	return append([]string{c.Address}, c.Custody...)
+}
+
+type ChainRegistry struct {
+	chains map[int]*Chain
+}
+
+// NewChainRegistry builds a registry from every entry under "blockchain.chains". Rpc urls are dialed on first use,
+// so an unreachable node doesn't stop the engine from starting.
+func NewChainRegistry() (*ChainRegistry, error) {
+	registry := ChainRegistry{
+		chains: make(map[int]*Chain),
+	}
+	for name := range viper.GetStringMap("blockchain.chains") {
+		key := fmt.Sprintf("blockchain.chains.%s", name)
+		chain := Chain{
+			ChainID:       viper.GetInt(key + ".chainId"),
+			Name:          name,
+			RPCURLs:       viper.GetStringSlice(key + ".rpc"),
+			Confirmations: viper.GetInt64(key + ".confirmations"),
+			Address:       viper.GetString(key + ".address"),
+			Custody:       viper.GetStringSlice(key + ".custody"),
+			Multisend:     viper.GetString(key + ".multisend"),
+			Native:        strings.ToUpper(viper.GetString(key + ".native")),
+			TransferGas:   viper.GetUint64("blockchain.transfer-gas"),
+			Tokens:        make(map[string]Token),
+		}
+		if chain.ChainID == 0 {
+			return nil, fmt.Errorf("chain '%s' must have chainId", name)
+		}
+		if len(chain.RPCURLs) == 0 {
+			return nil, fmt.Errorf("chain '%s' must have at least one rpc url", name)
+		}
+		if !IsValidAddress(chain.Address) {
+			return nil, fmt.Errorf("chain '%s' has invalid address '%s'", name, chain.Address)
+		}
+		for _, address := range chain.Custody {
+			if !IsValidAddress(address) {
+				return nil, fmt.Errorf("chain '%s' has invalid custody address '%s'", name, address)
+			}
+		}
+		if chain.Multisend != "" && !IsValidAddress(chain.Multisend) {
+			return nil, fmt.Errorf("chain '%s' has invalid multisend address '%s'", name, chain.Multisend)
+		}
+		if viper.IsSet(key + ".transfer-gas") {
+			chain.TransferGas = viper.GetUint64(key + ".transfer-gas")
+		}
+		for symbol := range viper.GetStringMap(key + ".tokens") {
+			tokenKey := fmt.Sprintf("%s.tokens.%s", key, symbol)
+			if !IsValidAddress(viper.GetString(tokenKey + ".address")) {
+				return nil, fmt.Errorf("chain '%s' has invalid token address of '%s'", name, symbol)
+			}
+			chain.Tokens[strings.ToUpper(symbol)] = Token{
+				Address:  common.HexToAddress(viper.GetString(tokenKey + ".address")),
+				Decimals: viper.GetInt(tokenKey + ".decimals"),
+			}
+		}
+		if exist, ok := registry.chains[chain.ChainID]; ok {
+			return nil, fmt.Errorf("chain '%s' and '%s' have the same chainId %d", exist.Name, name, chain.ChainID)
+		}
+		timeout := viper.GetDuration("blockchain.rpc.timeout")
+		if viper.IsSet(key + ".timeout") {
+			timeout = viper.GetDuration(key + ".timeout")
+		}
+		chain.Client = NewFailoverClient(chain.RPCURLs, timeout, viper.GetInt("blockchain.rpc.max-failures"), viper.GetDuration("blockchain.rpc.cooldown"))
+		registry.chains[chain.ChainID] = &chain
+	}
+	return &registry, nil
+}
+
+// NewChainRegistryFromChains builds a registry of chains whose Client is already set, e.g. SimulatedChain.
+func NewChainRegistryFromChains(chains ...*Chain) *ChainRegistry {
+	registry := ChainRegistry{
+		chains: make(map[int]*Chain),
+	}
+	for _, chain := range chains {
+		registry.chains[chain.ChainID] = chain
+	}
+	return &registry
+}
+
+// Chain returns ErrUnknownChain when chainId isn't configured.
+func (r *ChainRegistry) Chain(chainId int) (*Chain, error) {
+	chain, ok := r.chains[chainId]
+	if !ok {
+		return nil, errors.Wrapf(ErrUnknownChain, "%d", chainId)
+	}
+	return chain, nil
+}
+
+func (r *ChainRegistry) Chains() []*Chain {
+	chains := make([]*Chain, 0, len(r.chains))
+	for _, chain := range r.chains {
+		chains = append(chains, chain)
+	}
+	sort.Slice(chains, func(i, j int) bool { return chains[i].ChainID < chains[j].ChainID })
+	return chains
+}
+
+// DownChains returns names of chains whose every rpc endpoint is failing.
+func (r *ChainRegistry) DownChains() []string {
+	names := make([]string, 0)
+	for _, chain := range r.Chains() {
+		if health, ok := chain.Client.(interface{ IsDown() bool }); ok && health.IsDown() {
+			names = append(names, chain.Name)
+		}
+	}
+	return names
+}
+
+func (r *ChainRegistry) Close() {
+	for _, chain := range r.chains {
+		chain.Client.Close()
+	}
+}
+
+type GetChainFn func(chainId int) (*Chain, error)
+
+func NewGetChainFn(registry *ChainRegistry) GetChainFn {
+	return func(chainId int) (*Chain, error) {
+		return registry.Chain(chainId)
+	}
+}
+
+type ListChainFn func() []*Chain
+
+func NewListChainFn(registry *ChainRegistry) ListChainFn {
+	return func() []*Chain {
+		return registry.Chains()
+	}
+}
@@ -0,0 +1,61 @@
+package blockchain
+
+import (
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+)
+
+var ErrSignerDisabled = errors.New("report signer isn't configured")
+
+// Signer signs reports published by the engine with EIP-191 personal_sign, so anyone can check them with VerifyPersonalSign.
+type Signer struct {
+	key *keystore.Key
+}
+
+func NewSigner(key *keystore.Key) *Signer {
+	return &Signer{
+		key: key,
+	}
+}
+
+// NewSignerFromKeystore decrypts the keystore file of prefix, e.g. "reserves.signer". It returns nil signer when keystore isn't set.
+func NewSignerFromKeystore(prefix string) (*Signer, error) {
+	key, err := readKeystore(prefix)
+	if err != nil || key == nil {
+		return nil, err
+	}
+	return NewSigner(key), nil
+}
+
+func (s *Signer) Address() string {
+	return s.key.Address.Hex()
+}
+
+// SignPersonal returns the 65-byte signature with v of 27/28, the way wallets return it.
+func (s *Signer) SignPersonal(message string) (string, error) {
+	sig, err := crypto.Sign(accounts.TextHash([]byte(message)), s.key.PrivateKey)
+	if err != nil {
+		return "", err
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+	return hexutil.Encode(sig), nil
+}
+
+// SignMessageFn returns signature and signer address of message.
+type SignMessageFn func(message string) (string, string, error)
+
+func NewSignMessageFn(signer *Signer) SignMessageFn {
+	return func(message string) (string, string, error) {
+		if signer == nil {
+			return "", "", ErrSignerDisabled
+		}
+		signature, err := signer.SignPersonal(message)
+		if err != nil {
+			return "", "", err
+		}
+		return signature, signer.Address(), nil
+	}
+}
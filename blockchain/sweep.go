@@ -0,0 +1,184 @@
+package blockchain
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+)
+
+var ErrSignedSweepMismatch = errors.New("signed transaction doesn't match sweep")
+
+// sweepTransfer builds unsigned transfer of the whole token balance of from to the chain's address.
+func sweepTransfer(ctx context.Context, chain *Chain, collateralType string, from common.Address) (*types.Transaction, *big.Int, error) {
+	token, err := chain.Token(collateralType)
+	if err != nil {
+		return nil, nil, err
+	}
+	tokenAbi, err := abi.JSON(strings.NewReader(bep20Abi))
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := tokenAbi.Pack("balanceOf", from)
+	if err != nil {
+		return nil, nil, err
+	}
+	output, err := chain.Client.CallContract(ctx, ethereum.CallMsg{To: &token.Address, Data: data}, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	results, err := tokenAbi.Unpack("balanceOf", output)
+	if err != nil {
+		return nil, nil, err
+	}
+	amount := results[0].(*big.Int)
+	if amount.Sign() == 0 {
+		return nil, amount, nil
+	}
+
+	data, err = transferData(common.HexToAddress(chain.Address), amount)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce, err := chain.Client.PendingNonceAt(ctx, from)
+	if err != nil {
+		return nil, nil, err
+	}
+	gas, err := chain.Client.EstimateGas(ctx, ethereum.CallMsg{
+		From: from,
+		To:   &token.Address,
+		Data: data,
+	})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "cannot estimate gas")
+	}
+	tx, err := newTx(ctx, chain.Client, int64(chain.ChainID), nonce, token.Address, big.NewInt(0), gas, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tx, amount, nil
+}
+
+// maxTxCost is the most gas a transaction can be charged, fee cap for EIP-1559 or gas price for legacy.
+func maxTxCost(tx *types.Transaction) *big.Int {
+	return CalcGasCost(tx.Gas(), tx.GasFeeCap())
+}
+
+// FundSweepGasClientFn tops up native coin of deposit address from the hot wallet when it can't pay gas of its sweep.
+// It returns the funding transaction hash, or empty hash when the address already has enough gas.
+type FundSweepGasClientFn func(ctx context.Context, chainId int, collateralType string, address string) (string, error)
+
+func NewFundSweepGasClientFn(registry *ChainRegistry, executor *Executor) FundSweepGasClientFn {
+	return func(ctx context.Context, chainId int, collateralType string, address string) (string, error) {
+		if executor == nil {
+			return "", ErrHotWalletDisabled
+		}
+		chain, err := registry.Chain(chainId)
+		if err != nil {
+			return "", err
+		}
+		from := common.HexToAddress(address)
+		tx, _, err := sweepTransfer(ctx, chain, collateralType, from)
+		if err != nil || tx == nil {
+			return "", err
+		}
+		balance, err := chain.Client.BalanceAt(ctx, from, nil)
+		if err != nil {
+			return "", err
+		}
+		cost := maxTxCost(tx)
+		if balance.Cmp(cost) >= 0 {
+			return "", nil
+		}
+		fundTx, err := executor.Send(ctx, chain.Client, int64(chain.ChainID), from, new(big.Int).Sub(cost, balance), nil)
+		if err != nil {
+			return "", err
+		}
+		return fundTx.Hash().Hex(), nil
+	}
+}
+
+// BuildUnsignedSweepClientFn builds transfer of the whole collateral type balance of deposit address to the chain's address,
+// for the offline signer holding the extended private key of "blockchain.hd.xpub", so deposit keys never reach the engine.
+// It returns nil transaction when there is nothing to sweep.
+type BuildUnsignedSweepClientFn func(ctx context.Context, chainId int, collateralType string, address string) (*UnsignedTransaction, float64, error)
+
+func NewBuildUnsignedSweepClientFn(registry *ChainRegistry) BuildUnsignedSweepClientFn {
+	return func(ctx context.Context, chainId int, collateralType string, address string) (*UnsignedTransaction, float64, error) {
+		chain, err := registry.Chain(chainId)
+		if err != nil {
+			return nil, 0, err
+		}
+		token, err := chain.Token(collateralType)
+		if err != nil {
+			return nil, 0, err
+		}
+		from := common.HexToAddress(address)
+		tx, amount, err := sweepTransfer(ctx, chain, collateralType, from)
+		if err != nil || tx == nil {
+			return nil, 0, err
+		}
+		volume, _ := ToDecimal(amount, token.Decimals).Float64()
+		return newUnsignedTransaction(chainId, from, tx), volume, nil
+	}
+}
+
+// BroadcastSignedSweepClientFn checks that rawTransaction is signed by deposit address on the chain and transfers collateral type
+// to the chain's address, then broadcasts it and returns its hash and swept volume. A mismatch returns ErrSignedSweepMismatch.
+type BroadcastSignedSweepClientFn func(ctx context.Context, chainId int, rawTransaction string, collateralType string, address string) (string, float64, error)
+
+func NewBroadcastSignedSweepClientFn(registry *ChainRegistry) BroadcastSignedSweepClientFn {
+	return func(ctx context.Context, chainId int, rawTransaction string, collateralType string, address string) (string, float64, error) {
+		chain, err := registry.Chain(chainId)
+		if err != nil {
+			return "", 0, err
+		}
+		token, err := chain.Token(collateralType)
+		if err != nil {
+			return "", 0, err
+		}
+		tx, err := decodeSignedTransaction(chainId, rawTransaction, common.HexToAddress(address), token.Address, ErrSignedSweepMismatch)
+		if err != nil {
+			return "", 0, err
+		}
+		to, amount, err := unpackTransfer(tx.Data())
+		if err != nil {
+			return "", 0, errors.Wrap(ErrSignedSweepMismatch, err.Error())
+		}
+		if to != common.HexToAddress(chain.Address) {
+			return "", 0, errors.Wrapf(ErrSignedSweepMismatch, "transfers to %s, expected %s", to.Hex(), common.HexToAddress(chain.Address).Hex())
+		}
+		if amount.Sign() <= 0 {
+			return "", 0, errors.Wrap(ErrSignedSweepMismatch, "amount must be positive")
+		}
+
+		if err := chain.Client.SendTransaction(ctx, tx); err != nil {
+			return "", 0, err
+		}
+		volume, _ := ToDecimal(amount, token.Decimals).Float64()
+		return tx.Hash().Hex(), volume, nil
+	}
+}
+
+// unpackTransfer returns recipient and amount of transfer calldata.
+func unpackTransfer(data []byte) (common.Address, *big.Int, error) {
+	tokenAbi, err := abi.JSON(strings.NewReader(bep20Abi))
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	method := tokenAbi.Methods["transfer"]
+	if len(data) < 4 || !bytes.Equal(data[:4], method.ID) {
+		return common.Address{}, nil, errors.New("calldata isn't transfer")
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	return args[0].(common.Address), args[1].(*big.Int), nil
+}
@@ -9,8 +9,10 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/shopspring/decimal"
 	"golang.org/x/crypto/sha3"
@@ -157,3 +159,28 @@ func SigRSV(isig interface{}) ([32]byte, [32]byte, uint8) {
 
 	return R, S, V
 }
+
+// IsChecksumAddress validate hex address is written in EIP-55 mixed-case checksum
+func IsChecksumAddress(address string) bool {
+	return IsValidAddress(address) && common.HexToAddress(address).Hex() == address
+}
+
+// VerifyPersonalSign check that signature is an EIP-191 personal_sign of message by address
+func VerifyPersonalSign(address string, message string, signature string) (bool, error) {
+	sig, err := hexutil.Decode(signature)
+	if err != nil {
+		return false, err
+	}
+	if len(sig) != crypto.SignatureLength {
+		return false, fmt.Errorf("signature must be %d bytes", crypto.SignatureLength)
+	}
+	// wallets return v as 27/28, crypto expects 0/1.
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+	pub, err := crypto.SigToPub(accounts.TextHash([]byte(message)), sig)
+	if err != nil {
+		return false, err
+	}
+	return crypto.PubkeyToAddress(*pub) == common.HexToAddress(address), nil
+}
@@ -0,0 +1,87 @@
+package blockchain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestIsChecksumAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		want    bool
+	}{
+		// from EIP-55.
+		{name: "checksum", address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", want: true},
+		{name: "checksum all caps", address: "0x52908400098527886E0F7030069857D2E4169EE7", want: true},
+		{name: "lowercase", address: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"},
+		{name: "uppercase", address: "0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED"},
+		{name: "broken checksum", address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD"},
+		{name: "no prefix", address: "5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"},
+		{name: "too short", address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsChecksumAddress(tt.address); got != tt.want {
+				t.Errorf("IsChecksumAddress(%s) = %t, want %t", tt.address, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyPersonalSign(t *testing.T) {
+	key, err := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := NewSigner(&keystore.Key{Address: crypto.PubkeyToAddress(key.PublicKey), PrivateKey: key})
+	other, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := "Withdraw to 0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed from account 1"
+
+	// SignPersonal returns v as 27/28 like wallets, crypto.Sign as 0/1.
+	walletSignature, err := signer.SignPersonal(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := crypto.Sign(accounts.TextHash([]byte(message)), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rawSignature := hexutil.Encode(sig)
+
+	tests := []struct {
+		name      string
+		address   string
+		message   string
+		signature string
+		want      bool
+		err       bool
+	}{
+		{name: "v 27/28", address: signer.Address(), message: message, signature: walletSignature, want: true},
+		{name: "v 0/1", address: signer.Address(), message: message, signature: rawSignature, want: true},
+		{name: "lowercase address", address: strings.ToLower(signer.Address()), message: message, signature: walletSignature, want: true},
+		{name: "wrong signer", address: crypto.PubkeyToAddress(other.PublicKey).Hex(), message: message, signature: walletSignature},
+		{name: "wrong message", address: signer.Address(), message: message + ".", signature: walletSignature},
+		{name: "truncated", address: signer.Address(), message: message, signature: walletSignature[:len(walletSignature)-2], err: true},
+		{name: "not hex", address: signer.Address(), message: message, signature: "signature", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VerifyPersonalSign(tt.address, tt.message, tt.signature)
+			if (err != nil) != tt.err {
+				t.Fatalf("err = %v, want error %t", err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("VerifyPersonalSign = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
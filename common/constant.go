@@ -10,6 +10,7 @@ const (
 const (
 	ContentType     string = "Content-Type"
 	ApplicationJSON string = "application/json"
+	TextEventStream string = "text/event-stream"
 	XRequestID      string = "X-Request-ID"
 	LocaleKey       string = "locale"
 	JWTClaimsKey    string = "claims"
@@ -25,15 +26,31 @@ const (
 )
 
 const (
-	THBBTCRedis    string = "THB/BTC"
-	THBETHRedis    string = "THB/ETH"
-	PendingStatus  string = "PENDING"
-	ConfirmStatus  string = "CONFIRMED"
-	RejectStatus   string = "REJECTED"
-	OngoingStatus  string = "ONGOING"
-	ClosedStatus   string = "CLOSED"
-	DepositStatus  string = "DEPOSIT"
-	WithdrawStatus string = "WITHDRAW"
+	THBBTCRedis        string = "THB/BTC"
+	THBETHRedis        string = "THB/ETH"
+	PendingStatus      string = "PENDING"
+	ConfirmStatus      string = "CONFIRMED"
+	RejectStatus       string = "REJECTED"
+	BroadcastingStatus string = "BROADCASTING"
+	BroadcastStatus    string = "BROADCAST"
+	MinedStatus        string = "MINED"
+	FailedStatus       string = "FAILED"
+	OrphanedStatus     string = "ORPHANED"
+	FundingStatus      string = "FUNDING"
+	UnsignedStatus     string = "UNSIGNED"
+	OngoingStatus      string = "ONGOING"
+	ClosedStatus       string = "CLOSED"
+	SupersededStatus   string = "SUPERSEDED"
+	DepositStatus      string = "DEPOSIT"
+	WithdrawStatus     string = "WITHDRAW"
+	WithdrawFeeType    string = "WITHDRAW_FEE"
+)
+
+const (
+	MarginNoneStatus         string = "NONE"
+	MarginAtRiskStatus       string = "AT_RISK"
+	MarginCalledStatus       string = "CALLED"
+	MarginLiquidatableStatus string = "LIQUIDATABLE"
 )
 
 const (
@@ -6,6 +6,8 @@ require (
 	github.com/alecthomas/template v0.0.0-20190718012654-fb15b899a751
 	github.com/andybalholm/brotli v1.0.3 // indirect
 	github.com/arsmn/fiber-swagger/v2 v2.13.0
+	github.com/btcsuite/btcd v0.20.1-beta
+	github.com/btcsuite/btcutil v1.0.2
 	github.com/dgrijalva/jwt-go v3.2.0+incompatible
 	github.com/ethereum/go-ethereum v1.10.5
 	github.com/go-openapi/jsonreference v0.19.6 // indirect
@@ -16,12 +18,13 @@ require (
 	github.com/golang-jwt/jwt v3.2.1+incompatible
 	github.com/gomodule/redigo v1.8.5
 	github.com/google/uuid v1.3.0
+	github.com/gorilla/websocket v1.4.2
 	github.com/jmoiron/sqlx v1.3.4
 	github.com/klauspost/compress v1.13.1 // indirect
 	github.com/lib/pq v1.10.2
 	github.com/mailru/easyjson v0.7.7 // indirect
 	github.com/pkg/errors v0.9.1
-	github.com/shopspring/decimal v1.2.0 // indirect
+	github.com/shopspring/decimal v1.2.0
 	github.com/spf13/viper v1.8.1
 	github.com/swaggo/swag v1.7.0
 	github.com/valyala/fasthttp v1.28.0 // indirect
@@ -0,0 +1,150 @@
+package ingester
+
+import (
+	"context"
+	"fmt"
+	"lending-engine/internal/redis"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// StorePriceFn writes a tick into the engine's price store.
+type StorePriceFn func(tick Tick) error
+
+// NewStorePriceFn writes "THB/<asset>" and its unix seconds to "THB/<asset>:timestamp", the keys read by the oracle's redis source.
+func NewStorePriceFn(setDataNoExpireRedisFn redis.SetDataNoExpireRedisFn) StorePriceFn {
+	return func(tick Tick) error {
+		key := fmt.Sprintf("THB/%s", tick.Asset)
+		if err := setDataNoExpireRedisFn(key, tick.Price); err != nil {
+			return err
+		}
+		return setDataNoExpireRedisFn(key+":timestamp", tick.Timestamp.Unix())
+	}
+}
+
+// Ingester keeps a websocket to an exchange ticker and stores every tick. A connection without a tick for "ingester.heartbeat-timeout"
+// is dropped even while pongs or other messages arrive, and reconnects back off from "ingester.backoff.min" doubling up to
+// "ingester.backoff.max".
+type Ingester struct {
+	url          string
+	subscribe    string
+	normalizer   *normalizer
+	storePriceFn StorePriceFn
+	logger       *zap.Logger
+}
+
+func NewIngester(url string, subscribe string, normalizer *normalizer, storePriceFn StorePriceFn, logger *zap.Logger) *Ingester {
+	return &Ingester{
+		url:          url,
+		subscribe:    subscribe,
+		normalizer:   normalizer,
+		storePriceFn: storePriceFn,
+		logger:       logger.With(zap.String("job", "price-ingester")),
+	}
+}
+
+func NewIngesterFromConfig(storePriceFn StorePriceFn, logger *zap.Logger) (*Ingester, error) {
+	url := viper.GetString("ingester.url")
+	if url == "" {
+		return nil, errors.New("ingester.url is empty")
+	}
+	normalizer, err := newNormalizerFromConfig()
+	if err != nil {
+		return nil, err
+	}
+	return NewIngester(url, viper.GetString("ingester.subscribe"), normalizer, storePriceFn, logger), nil
+}
+
+// Run connects and reconnects until ctx is done.
+func (i *Ingester) Run(ctx context.Context) {
+	backoff := viper.GetDuration("ingester.backoff.min")
+	for {
+		ticks, err := i.session(ctx)
+		if ctx.Err() != nil {
+			i.logger.Info("price-ingester stopped")
+			return
+		}
+		if ticks > 0 {
+			backoff = viper.GetDuration("ingester.backoff.min")
+		}
+		i.logger.Error(fmt.Sprintf("Ticks: %d | %v, reconnect in %s", ticks, err, backoff))
+		select {
+		case <-ctx.Done():
+			i.logger.Info("price-ingester stopped")
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > viper.GetDuration("ingester.backoff.max") {
+			backoff = viper.GetDuration("ingester.backoff.max")
+		}
+	}
+}
+
+// session reads one connection until it fails and returns the number of ticks stored.
+func (i *Ingester) session(ctx context.Context) (int, error) {
+	dialer := websocket.Dialer{
+		HandshakeTimeout: viper.GetDuration("ingester.heartbeat-timeout"),
+	}
+	conn, _, err := dialer.DialContext(ctx, i.url, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	i.logger.Info(fmt.Sprintf("price-ingester connected to %s", i.url))
+
+	if i.subscribe != "" {
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(i.subscribe)); err != nil {
+			return 0, err
+		}
+	}
+
+	// only ticks extend the deadline, pings just keep the connection open through proxies.
+	heartbeat := viper.GetDuration("ingester.heartbeat-timeout")
+	conn.SetReadDeadline(time.Now().Add(heartbeat))
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ping := time.NewTicker(heartbeat / 3)
+		defer ping.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				// unblocks ReadMessage.
+				conn.Close()
+				return
+			case <-done:
+				return
+			case <-ping.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(heartbeat)); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	ticks := 0
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return ticks, err
+		}
+		tick, ok, err := i.normalizer.normalize(message, time.Now())
+		if err != nil {
+			i.logger.Error(err.Error())
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if err := i.storePriceFn(*tick); err != nil {
+			return ticks, err
+		}
+		ticks++
+		conn.SetReadDeadline(time.Now().Add(heartbeat))
+	}
+}
@@ -0,0 +1,215 @@
+package ingester
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+const (
+	testSymbolField   = "stream"
+	testSymbolPattern = `^market\.ticker\.thb_(\w+)$`
+	testPriceField    = "data.last"
+)
+
+func newTestNormalizer(t *testing.T) *normalizer {
+	t.Helper()
+	normalizer, err := newNormalizer(testSymbolField, testSymbolPattern, testPriceField)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return normalizer
+}
+
+func TestNormalize(t *testing.T) {
+	normalizer := newTestNormalizer(t)
+	received := time.Date(2021, 1, 2, 12, 13, 14, 0, time.Local)
+
+	tests := []struct {
+		name    string
+		message string
+		asset   string
+		price   float64
+		ok      bool
+		err     bool
+	}{
+		{name: "number price", message: `{"stream":"market.ticker.thb_btc","data":{"last":1042475.25}}`, asset: "BTC", price: 1042475.25, ok: true},
+		{name: "string price", message: `{"stream":"market.ticker.thb_eth","data":{"last":"65012.5"}}`, asset: "ETH", price: 65012.5, ok: true},
+		{name: "subscription ack", message: `{"result":"subscribed"}`},
+		{name: "other symbol", message: `{"stream":"market.trade.thb_btc","data":{"last":1}}`},
+		{name: "missing price", message: `{"stream":"market.ticker.thb_btc","data":{}}`, err: true},
+		{name: "unparsable price", message: `{"stream":"market.ticker.thb_btc","data":{"last":"n/a"}}`, err: true},
+		{name: "non-positive price", message: `{"stream":"market.ticker.thb_btc","data":{"last":0}}`, err: true},
+		{name: "not json", message: `ping`, err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tick, ok, err := normalizer.normalize([]byte(tt.message), received)
+			if (err != nil) != tt.err {
+				t.Fatalf("err = %v, want error %t", err, tt.err)
+			}
+			if ok != tt.ok {
+				t.Fatalf("ok = %t, want %t", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if tick.Asset != tt.asset || tick.Price != tt.price || !tick.Timestamp.Equal(received) {
+				t.Errorf("tick = %+v, want %s %f at %s", *tick, tt.asset, tt.price, received)
+			}
+		})
+	}
+}
+
+func TestNewNormalizerNeedsAssetGroup(t *testing.T) {
+	if _, err := newNormalizer(testSymbolField, `^market\.ticker\.thb_\w+$`, testPriceField); err == nil {
+		t.Fatal("pattern without a group is accepted")
+	}
+}
+
+// ticks collects stored ticks.
+type ticks struct {
+	mu     sync.Mutex
+	stored []Tick
+}
+
+func (s *ticks) storePriceFn() StorePriceFn {
+	return func(tick Tick) error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.stored = append(s.stored, tick)
+		return nil
+	}
+}
+
+func (s *ticks) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.stored)
+}
+
+// newExchangeStub serves a websocket that reads the subscription, sends messages and then sends a subscription ack every
+// 20ms while answering pings, like an exchange whose ticker stopped but whose connection is alive.
+func newExchangeStub(t *testing.T, subscribed chan<- string, messages ...string) string {
+	t.Helper()
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("cannot upgrade: %v", err)
+			return
+		}
+		defer conn.Close()
+		_, subscribe, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		subscribed <- string(subscribe)
+		for _, message := range messages {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(message)); err != nil {
+				return
+			}
+		}
+		// the default ping handler answers pings while the connection is read.
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+		ack := time.NewTicker(20 * time.Millisecond)
+		defer ack.Stop()
+		for {
+			select {
+			case <-closed:
+				return
+			case <-ack.C:
+				if err := conn.WriteMessage(websocket.TextMessage, []byte(`{"result":"subscribed"}`)); err != nil {
+					return
+				}
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func TestSessionDropsConnectionWithoutTicks(t *testing.T) {
+	heartbeat := 300 * time.Millisecond
+	viper.Set("ingester.heartbeat-timeout", heartbeat)
+	defer viper.Set("ingester.heartbeat-timeout", nil)
+
+	subscribed := make(chan string, 1)
+	url := newExchangeStub(t, subscribed,
+		`{"stream":"market.ticker.thb_btc","data":{"last":1042475.25}}`,
+		`{"stream":"market.ticker.thb_eth","data":{"last":"65012.5"}}`,
+	)
+	var stored ticks
+	ingester := NewIngester(url, `{"subscribe":"market.ticker.thb_btc"}`, newTestNormalizer(t), stored.storePriceFn(), zap.NewNop())
+
+	start := time.Now()
+	n, err := ingester.session(context.Background())
+	elapsed := time.Since(start)
+	if err == nil {
+		t.Fatal("session ended without error")
+	}
+	if n != 2 || stored.len() != 2 {
+		t.Errorf("session stored %d ticks (%d in store), want 2", n, stored.len())
+	}
+	if got := <-subscribed; got != `{"subscribe":"market.ticker.thb_btc"}` {
+		t.Errorf("subscription = %s", got)
+	}
+	// acks and pongs keep arriving, yet the connection is dropped one heartbeat after the last tick.
+	if elapsed < heartbeat || elapsed > 3*heartbeat {
+		t.Errorf("session lasted %s, want about %s", elapsed, heartbeat)
+	}
+}
+
+func TestRunBacksOff(t *testing.T) {
+	viper.Set("ingester.heartbeat-timeout", time.Second)
+	viper.Set("ingester.backoff.min", 40*time.Millisecond)
+	viper.Set("ingester.backoff.max", 160*time.Millisecond)
+	defer viper.Set("ingester.heartbeat-timeout", nil)
+	defer viper.Set("ingester.backoff.min", nil)
+	defer viper.Set("ingester.backoff.max", nil)
+
+	var mu sync.Mutex
+	var attempts []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		attempts = append(attempts, time.Now())
+		mu.Unlock()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	var stored ticks
+	ingester := NewIngester("ws"+strings.TrimPrefix(server.URL, "http"), "", newTestNormalizer(t), stored.storePriceFn(), zap.NewNop())
+	ctx, cancel := context.WithTimeout(context.Background(), 700*time.Millisecond)
+	defer cancel()
+	ingester.Run(ctx)
+
+	mu.Lock()
+	defer mu.Unlock()
+	// waits are 40, 80, 160 and then stay at 160ms.
+	want := []time.Duration{40 * time.Millisecond, 80 * time.Millisecond, 160 * time.Millisecond, 160 * time.Millisecond}
+	if len(attempts) < len(want)+1 {
+		t.Fatalf("%d connection attempts, want at least %d", len(attempts), len(want)+1)
+	}
+	for i, backoff := range want {
+		if gap := attempts[i+1].Sub(attempts[i]); gap < backoff || gap > backoff+100*time.Millisecond {
+			t.Errorf("attempt %d came %s after the previous one, want %s", i+2, gap, backoff)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package ingester
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+// Tick is one normalized exchange price in THB.
+type Tick struct {
+	Asset     string
+	Price     float64
+	Timestamp time.Time
+}
+
+// normalizer turns an exchange ticker message into a Tick. The symbol is read from symbolField and matched by symbolPattern,
+// whose first group is the asset; the price is read from priceField. Fields are dot-separated paths.
+type normalizer struct {
+	symbolField   string
+	symbolPattern *regexp.Regexp
+	priceField    string
+}
+
+func newNormalizer(symbolField string, symbolPattern string, priceField string) (*normalizer, error) {
+	pattern, err := regexp.Compile(symbolPattern)
+	if err != nil {
+		return nil, errors.Wrap(err, "ingester.symbol-pattern")
+	}
+	if pattern.NumSubexp() < 1 {
+		return nil, errors.New("ingester.symbol-pattern must capture the asset")
+	}
+	return &normalizer{
+		symbolField:   symbolField,
+		symbolPattern: pattern,
+		priceField:    priceField,
+	}, nil
+}
+
+func newNormalizerFromConfig() (*normalizer, error) {
+	return newNormalizer(viper.GetString("ingester.symbol-field"), viper.GetString("ingester.symbol-pattern"), viper.GetString("ingester.price-field"))
+}
+
+// normalize returns false for messages that aren't tickers of a matching symbol, such as subscription acks.
+func (n *normalizer) normalize(message []byte, received time.Time) (*Tick, bool, error) {
+	var value interface{}
+	if err := json.Unmarshal(message, &value); err != nil {
+		return nil, false, err
+	}
+	symbol, ok := lookup(value, n.symbolField).(string)
+	if !ok {
+		return nil, false, nil
+	}
+	match := n.symbolPattern.FindStringSubmatch(symbol)
+	if match == nil {
+		return nil, false, nil
+	}
+	var price float64
+	switch v := lookup(value, n.priceField).(type) {
+	case float64:
+		price = v
+	case string:
+		p, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, false, errors.Wrapf(err, "price of %s", symbol)
+		}
+		price = p
+	default:
+		return nil, false, errors.Errorf("%s has no price at '%s'", symbol, n.priceField)
+	}
+	if price <= 0 {
+		return nil, false, errors.Errorf("price of %s must be positive", symbol)
+	}
+	return &Tick{
+		Asset:     strings.ToUpper(match[1]),
+		Price:     price,
+		Timestamp: received,
+	}, true, nil
+}
+
+func lookup(value interface{}, path string) interface{} {
+	for _, field := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = object[field]
+	}
+	return value
+}
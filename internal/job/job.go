@@ -0,0 +1,32 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type Func func(ctx context.Context) error
+
+// Start runs fn every interval in background until ctx is done. Errors are logged and don't stop the schedule.
+func Start(ctx context.Context, logger *zap.Logger, name string, interval time.Duration, fn Func) {
+	logger = logger.With(zap.String("job", name))
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				logger.Info(fmt.Sprintf("%s stopped", name))
+				return
+			case <-ticker.C:
+				if err := fn(ctx); err != nil {
+					logger.Error(err.Error())
+				}
+			}
+		}
+	}()
+	logger.Info(fmt.Sprintf("%s started every %s", name, interval))
+}
@@ -0,0 +1,92 @@
+package merkle
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Tree is a keccak256 binary merkle tree. A leaf is keccak256(0x00 || data) and a node keccak256(0x01 || left || right),
+// so a node can't be passed off as a leaf. The last node of an odd level is carried up as is.
+type Tree struct {
+	levels [][][]byte
+}
+
+type ProofStep struct {
+	Hash string `json:"hash" example:"0x3ad2c1b9a5ed6e1e0a0e6a1b0c6a4cfd1c4e7b8e0c5a9b7d2c8e1f0a6b4d3c2e"`
+	Left bool   `json:"left" example:"true"`
+}
+
+const (
+	leafPrefix byte = 0x00
+	nodePrefix byte = 0x01
+)
+
+func Leaf(data []byte) []byte {
+	return crypto.Keccak256([]byte{leafPrefix}, data)
+}
+
+func node(left []byte, right []byte) []byte {
+	return crypto.Keccak256([]byte{nodePrefix}, left, right)
+}
+
+func New(leaves [][]byte) *Tree {
+	tree := Tree{
+		levels: [][][]byte{leaves},
+	}
+	for level := leaves; len(level) > 1; {
+		next := make([][]byte, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			next = append(next, node(level[i], level[i+1]))
+		}
+		tree.levels = append(tree.levels, next)
+		level = next
+	}
+	return &tree
+}
+
+// Root is empty for a tree without leaves.
+func (t *Tree) Root() []byte {
+	top := t.levels[len(t.levels)-1]
+	if len(top) == 0 {
+		return nil
+	}
+	return top[0]
+}
+
+// Proof returns siblings from leaf index up to the root. Left tells that the sibling goes on the left side.
+func (t *Tree) Proof(index int) []ProofStep {
+	proof := make([]ProofStep, 0, len(t.levels))
+	for _, level := range t.levels[:len(t.levels)-1] {
+		sibling := index ^ 1
+		if sibling < len(level) {
+			proof = append(proof, ProofStep{
+				Hash: hexutil.Encode(level[sibling]),
+				Left: sibling < index,
+			})
+		}
+		index /= 2
+	}
+	return proof
+}
+
+func Verify(leaf []byte, proof []ProofStep, root []byte) bool {
+	hash := leaf
+	for _, step := range proof {
+		sibling, err := hexutil.Decode(step.Hash)
+		if err != nil {
+			return false
+		}
+		if step.Left {
+			hash = node(sibling, hash)
+		} else {
+			hash = node(hash, sibling)
+		}
+	}
+	return bytes.Equal(hash, root)
+}
@@ -0,0 +1,94 @@
+package merkle
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func testLeaves(n int) [][]byte {
+	leaves := make([][]byte, 0, n)
+	for i := 0; i < n; i++ {
+		leaves = append(leaves, Leaf([]byte(fmt.Sprintf("leaf %d", i))))
+	}
+	return leaves
+}
+
+func TestRoot(t *testing.T) {
+	a, b, c := Leaf([]byte("a")), Leaf([]byte("b")), Leaf([]byte("c"))
+	tests := []struct {
+		name   string
+		leaves [][]byte
+		root   []byte
+	}{
+		{name: "no leaves", leaves: [][]byte{}, root: nil},
+		{name: "single leaf", leaves: [][]byte{a}, root: a},
+		{name: "two leaves", leaves: [][]byte{a, b}, root: node(a, b)},
+		// the odd leaf is carried up, not hashed with itself.
+		{name: "three leaves", leaves: [][]byte{a, b, c}, root: node(node(a, b), c)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if root := New(tt.leaves).Root(); !bytes.Equal(root, tt.root) {
+				t.Errorf("root = %x, want %x", root, tt.root)
+			}
+		})
+	}
+}
+
+func TestLeafAndNodeAreDomainSeparated(t *testing.T) {
+	a, b := Leaf([]byte("a")), Leaf([]byte("b"))
+	if bytes.Equal(Leaf([]byte("a")), crypto.Keccak256([]byte("a"))) {
+		t.Error("leaf is the plain keccak256 of its data")
+	}
+	// an inner node presented as the data of a leaf doesn't hash to the node.
+	if bytes.Equal(Leaf(append(append([]byte{}, a...), b...)), node(a, b)) {
+		t.Error("node(a, b) is the leaf of a || b")
+	}
+	cd := node(Leaf([]byte("c")), Leaf([]byte("d")))
+	root := New([][]byte{a, b, Leaf([]byte("c")), Leaf([]byte("d"))}).Root()
+	if !Verify(node(a, b), []ProofStep{{Hash: hexutil.Encode(cd)}}, root) {
+		t.Fatal("inner node doesn't verify against its own level")
+	}
+	if Verify(Leaf(append(append([]byte{}, a...), b...)), []ProofStep{{Hash: hexutil.Encode(cd)}}, root) {
+		t.Error("a leaf made of an inner node verifies against the root")
+	}
+}
+
+func TestProofVerify(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 5, 7, 8} {
+		t.Run(fmt.Sprintf("%d leaves", n), func(t *testing.T) {
+			leaves := testLeaves(n)
+			tree := New(leaves)
+			root := tree.Root()
+			for i, leaf := range leaves {
+				proof := tree.Proof(i)
+				if !Verify(leaf, proof, root) {
+					t.Fatalf("proof of leaf %d doesn't verify", i)
+				}
+				if Verify(Leaf([]byte("other")), proof, root) {
+					t.Errorf("proof of leaf %d verifies another leaf", i)
+				}
+			}
+			if n == 1 && len(tree.Proof(0)) != 0 {
+				t.Errorf("single leaf proof = %v, want empty", tree.Proof(0))
+			}
+		})
+	}
+}
+
+func TestVerifyRejectsTamperedProof(t *testing.T) {
+	leaves := testLeaves(5)
+	tree := New(leaves)
+	proof := tree.Proof(2)
+	proof[0].Left = !proof[0].Left
+	if Verify(leaves[2], proof, tree.Root()) {
+		t.Error("proof with a flipped side verifies")
+	}
+	if Verify(leaves[2], []ProofStep{{Hash: "not hex"}}, tree.Root()) {
+		t.Error("proof with an undecodable hash verifies")
+	}
+}
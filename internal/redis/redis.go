@@ -1,7 +1,9 @@
 package redis
 
 import (
+	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/gomodule/redigo/redis"
 )
@@ -194,3 +196,79 @@ func NewDeleteDataRedisFn(pool *redis.Pool) DeleteDataRedisFn {
 		return nil
 	}
 }
+
+type PublishRedisFn func(channel string, value interface{}) error
+
+func NewPublishRedisFn(pool *redis.Pool) PublishRedisFn {
+	return func(channel string, value interface{}) error {
+		conn := pool.Get()
+		defer conn.Close()
+
+		_, err := conn.Do("PUBLISH", channel, value)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+}
+
+// Message is a message received on a subscribed channel.
+type Message struct {
+	Channel string
+	Data    []byte
+}
+
+type SubscribeRedisFn func(ctx context.Context, channels ...string) (<-chan Message, error)
+
+// NewSubscribeRedisFn subscribes a dedicated connection to channels. The returned channel is closed when ctx is done
+// or the connection fails, which includes a ping every heartbeat going unanswered.
+func NewSubscribeRedisFn(pool *redis.Pool, heartbeat time.Duration) SubscribeRedisFn {
+	return func(ctx context.Context, channels ...string) (<-chan Message, error) {
+		conn := pool.Get()
+		psc := redis.PubSubConn{Conn: conn}
+		if err := psc.Subscribe(redis.Args{}.AddFlat(channels)...); err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		done := make(chan struct{})
+		go func() {
+			ticker := time.NewTicker(heartbeat)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					conn.Close()
+					return
+				case <-done:
+					return
+				case <-ticker.C:
+					if err := psc.Ping(""); err != nil {
+						conn.Close()
+						return
+					}
+				}
+			}
+		}()
+
+		messages := make(chan Message)
+		go func() {
+			defer close(messages)
+			defer close(done)
+			defer conn.Close()
+			for {
+				switch v := psc.ReceiveWithTimeout(2 * heartbeat).(type) {
+				case redis.Message:
+					select {
+					case messages <- Message{Channel: v.Channel, Data: v.Data}:
+					case <-ctx.Done():
+						return
+					}
+				case error:
+					return
+				}
+			}
+		}()
+		return messages, nil
+	}
+}
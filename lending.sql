@@ -86,6 +86,11 @@ CREATE TABLE lending.public.wallet_transaction (
 	status varchar(30) NOT NULL DEFAULT 'PENDING'::character varying,
 	created_datetime timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
 	updated_datetime timestamp NULL,
+	block_number int8 NULL,
+	block_hash varchar(100) NULL,
+	parent_id int4 NULL,
+	sweep_id int4 NULL,
+	to_address varchar(100) NULL,
 	CONSTRAINT wallet_transaction_pkey PRIMARY KEY (id)
 );
 
@@ -96,4 +101,106 @@ CREATE TABLE lending.public.user_subscription (
 	email varchar(100) NOT NULL,
 	created_datetime timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
 	CONSTRAINT user_subscription_pkey PRIMARY KEY (email)
-);
\ No newline at end of file
+);
+CREATE TABLE lending.public.deposit_address (
+	account_id int4 NOT NULL,
+	address varchar(100) NOT NULL,
+	derivation_path varchar(50) NOT NULL,
+	created_datetime timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT deposit_address_pkey PRIMARY KEY (account_id),
+	CONSTRAINT deposit_address_address_key UNIQUE (address)
+);
+
+CREATE TABLE lending.public.deposit_scan (
+	chain_id int4 NOT NULL,
+	block_number int8 NOT NULL,
+	updated_datetime timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT deposit_scan_pkey PRIMARY KEY (chain_id)
+);
+
+CREATE TABLE lending.public.withdraw_address (
+	id serial NOT NULL,
+	account_id int4 NOT NULL,
+	address varchar(100) NOT NULL,
+	label varchar(100) NULL,
+	verified bool NOT NULL DEFAULT false,
+	active_datetime timestamp NOT NULL,
+	created_datetime timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT withdraw_address_pkey PRIMARY KEY (id),
+	CONSTRAINT withdraw_address_account_address_key UNIQUE (account_id, address)
+);
+
+CREATE TABLE lending.public.reserve_report (
+	id serial NOT NULL,
+	root varchar(100) NOT NULL,
+	report text NOT NULL,
+	signature varchar(200) NOT NULL,
+	signer varchar(100) NOT NULL,
+	created_datetime timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT reserve_report_pkey PRIMARY KEY (id)
+);
+
+CREATE TABLE lending.public.reserve_liability (
+	root varchar(100) NOT NULL,
+	account_id int4 NOT NULL,
+	btc_volume numeric NOT NULL,
+	eth_volume numeric NOT NULL,
+	salt varchar(64) NOT NULL,
+	leaf_index int4 NOT NULL,
+	CONSTRAINT reserve_liability_pkey PRIMARY KEY (root, account_id)
+);
+
+CREATE TABLE lending.public.sweep (
+	id serial NOT NULL,
+	chain_id int4 NOT NULL,
+	account_id int4 NOT NULL,
+	address varchar(100) NOT NULL,
+	collateral_type varchar(10) NOT NULL,
+	volume numeric NOT NULL,
+	txn_hash varchar(100) NULL,
+	gas_txn_hash varchar(100) NULL,
+	status varchar(30) NOT NULL,
+	created_datetime timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_datetime timestamp NULL,
+	CONSTRAINT sweep_pkey PRIMARY KEY (id)
+);
+
+CREATE TABLE lending.public.price_tick (
+	id bigserial NOT NULL,
+	asset varchar(10) NOT NULL,
+	price numeric NOT NULL,
+	sources varchar(200) NOT NULL,
+	stale bool NOT NULL DEFAULT false,
+	price_datetime timestamp NOT NULL,
+	created_datetime timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT price_tick_pkey PRIMARY KEY (id)
+);
+CREATE INDEX price_tick_asset_created_idx ON lending.public.price_tick (asset, created_datetime);
+
+CREATE TABLE lending.public.price_candle (
+	asset varchar(10) NOT NULL,
+	candle_interval varchar(5) NOT NULL,
+	open_datetime timestamp NOT NULL,
+	open_price numeric NOT NULL,
+	high_price numeric NOT NULL,
+	low_price numeric NOT NULL,
+	close_price numeric NOT NULL,
+	ticks int4 NOT NULL,
+	updated_datetime timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT price_candle_pkey PRIMARY KEY (asset, candle_interval, open_datetime)
+);
+
+CREATE TABLE lending.public.haircut_proposal (
+	id serial NOT NULL,
+	asset varchar(10) NOT NULL,
+	volatility numeric NOT NULL,
+	samples int4 NOT NULL,
+	current_haircut numeric NOT NULL,
+	proposed_haircut numeric NOT NULL,
+	current_margin_call_ltv numeric NOT NULL,
+	proposed_margin_call_ltv numeric NOT NULL,
+	status varchar(30) NOT NULL,
+	created_datetime timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_datetime timestamp NULL,
+	CONSTRAINT haircut_proposal_pkey PRIMARY KEY (id)
+);
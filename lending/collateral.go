@@ -0,0 +1,53 @@
+package lending
+
+import (
+	"context"
+	"fmt"
+	"lending-engine/common"
+	"time"
+)
+
+// addCollateral adds volume (negative to deduct) of collateral type to the account's wallet and returns the new balances.
+// Jobs that change a transaction status with the balance write both in one repository transaction instead.
+func addCollateral(ctx context.Context, lendingRepository LendingRepository, accountId int, collateralType string, volume float64) (float64, float64, error) {
+	wallet, err := lendingRepository.QueryWalletRepo(ctx, accountId)
+	if err != nil {
+		return 0, 0, err
+	}
+	if wallet == nil {
+		return 0, 0, fmt.Errorf("wallet of account %d doesn't exist", accountId)
+	}
+	btc, eth, err := collateralVolumes(*wallet, collateralType, volume)
+	if err != nil {
+		return btc, eth, err
+	}
+	rows, err := lendingRepository.UpdateWalletRepo(ctx, accountId, btc, eth, wallet.MarginCallDate, time.Now().Format(common.DateYYYYMMDDHHMMSSFormat))
+	if err != nil {
+		return 0, 0, err
+	}
+	if rows != 1 {
+		return 0, 0, fmt.Errorf("expected to affect 1 row, affected %d", rows)
+	}
+	return btc, eth, nil
+}
+
+// collateralVolumes returns btc and eth volumes of wallet after adding volume of collateral type, deducting to 0 at most.
+func collateralVolumes(wallet Wallet, collateralType string, volume float64) (float64, float64, error) {
+	btc := *wallet.BTCVolume
+	eth := *wallet.ETHVolume
+	switch collateralType {
+	case "BTC":
+		btc += volume
+		if btc < 0 {
+			btc = 0
+		}
+	case "ETH":
+		eth += volume
+		if eth < 0 {
+			eth = 0
+		}
+	default:
+		return btc, eth, fmt.Errorf("account %d can't update collateral volume (%s %f)", *wallet.AccountID, collateralType, volume)
+	}
+	return btc, eth, nil
+}
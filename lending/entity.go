@@ -17,6 +17,11 @@ type WalletTransaction struct {
 	Status          *string    `db:"status" json:"status" example:"PENDING"`
 	CreatedDatetime *time.Time `db:"created_datetime" json:"createdDatetime" example:"2021-01-02 12:13:14"`
 	UpdatedDatetime *time.Time `db:"updated_datetime" json:"updatedDatetime" example:"2021-02-03 12:13:14"`
+	BlockNumber     *int64     `db:"block_number" json:"blockNumber" example:"12870267"`
+	BlockHash       *string    `db:"block_hash" json:"blockHash" example:"0x3ad2c1b9a5ed6e1e0a0e6a1b0c6a4cfd1c4e7b8e0c5a9b7d2c8e1f0a6b4d3c2e"`
+	ParentID        *int       `db:"parent_id" json:"parentId" example:"1"`
+	SweepID         *int       `db:"sweep_id" json:"sweepId" example:"1"`
+	ToAddress       *string    `db:"to_address" json:"toAddress" example:"0x9858EfFD232B4033E47d90003D41EC34EcaEda94"`
 }
 
 type Wallet struct {
@@ -66,15 +71,73 @@ type Liquidation struct {
 	Status          *string  `db:"status" json:"status" example:"CLOSED"`
 }
 
+type DepositAddress struct {
+	AccountID       *int       `db:"account_id" json:"accountId" example:"1"`
+	Address         *string    `db:"address" json:"address" example:"0x9858EfFD232B4033E47d90003D41EC34EcaEda94"`
+	DerivationPath  *string    `db:"derivation_path" json:"derivationPath" example:"m/44'/60'/0'/0/1"`
+	CreatedDatetime *time.Time `db:"created_datetime" json:"createdDatetime" example:"2021-01-02 12:13:14"`
+}
+
+type WithdrawAddress struct {
+	ID              *int       `db:"id" json:"id" example:"1"`
+	AccountID       *int       `db:"account_id" json:"accountId" example:"1"`
+	Address         *string    `db:"address" json:"address" example:"0xC26880A0AF2EA0c7E8130e6EC47Af756465452E8"`
+	Label           *string    `db:"label" json:"label" example:"My Ledger"`
+	Verified        *bool      `db:"verified" json:"verified" example:"true"`
+	ActiveDatetime  *time.Time `db:"active_datetime" json:"activeDatetime" example:"2021-01-03 12:13:14"`
+	CreatedDatetime *time.Time `db:"created_datetime" json:"createdDatetime" example:"2021-01-02 12:13:14"`
+}
+
+type ReserveReport struct {
+	ID              *int       `db:"id" json:"id" example:"1"`
+	Root            *string    `db:"root" json:"root" example:"0x3ad2c1b9a5ed6e1e0a0e6a1b0c6a4cfd1c4e7b8e0c5a9b7d2c8e1f0a6b4d3c2e"`
+	Report          *string    `db:"report" json:"report" example:"{...}"`
+	Signature       *string    `db:"signature" json:"signature" example:"0x..."`
+	Signer          *string    `db:"signer" json:"signer" example:"0xa9B6D99bA92D7d691c6EF4f49A1DC909822Cee46"`
+	CreatedDatetime *time.Time `db:"created_datetime" json:"createdDatetime" example:"2021-01-02 12:13:14"`
+}
+
+type Sweep struct {
+	ID              *int       `db:"id" json:"id" example:"1"`
+	ChainID         *int       `db:"chain_id" json:"chainId" example:"56"`
+	AccountID       *int       `db:"account_id" json:"accountId" example:"1"`
+	Address         *string    `db:"address" json:"address" example:"0x9858EfFD232B4033E47d90003D41EC34EcaEda94"`
+	CollateralType  *string    `db:"collateral_type" json:"collateralType" example:"BTC"`
+	Volume          *float64   `db:"volume" json:"volume" example:"0.5"`
+	TxnHash         *string    `db:"txn_hash" json:"txnHash" example:"0xcbeafcd4c82144f7d1f9b94e4ed43e9ed1aa1434feb65a06fed97fee993ba075"`
+	GasTxnHash      *string    `db:"gas_txn_hash" json:"gasTxnHash" example:"0xf5a3aa87c40b05e6a308b61186eeded8996b654a9895401b8089a2966b54f618"`
+	Status          *string    `db:"status" json:"status" example:"BROADCAST"`
+	CreatedDatetime *time.Time `db:"created_datetime" json:"createdDatetime" example:"2021-01-02 12:13:14"`
+	UpdatedDatetime *time.Time `db:"updated_datetime" json:"updatedDatetime" example:"2021-02-03 12:13:14"`
+}
+
+type ReserveLiability struct {
+	Root      *string  `db:"root" json:"root" example:"0x3ad2c1b9a5ed6e1e0a0e6a1b0c6a4cfd1c4e7b8e0c5a9b7d2c8e1f0a6b4d3c2e"`
+	AccountID *int     `db:"account_id" json:"accountId" example:"1"`
+	BTCVolume *float64 `db:"btc_volume" json:"btcVolume" example:"0.1"`
+	ETHVolume *float64 `db:"eth_volume" json:"ethVolume" example:"0.1"`
+	Salt      *string  `db:"salt" json:"salt" example:"9f86d081884c7d659a2feaa0c55ad015"`
+	LeafIndex *int     `db:"leaf_index" json:"leafIndex" example:"0"`
+}
+
 type LendingRepository interface {
 	QueryWalletTransactionByIDRepo(context.Context, int) (*WalletTransaction, error)
 	QueryWalletTransactionRepo(context.Context, map[string]interface{}) (*[]WalletTransaction, error)
-	InsertDepositRepo(context.Context, int, string, int, string, string, float64, string, string) (int64, error)
+	InsertDepositRepo(context.Context, int, string, string, int, string, string, float64, string, string) (int64, error)
 	UpdateDepositRepo(context.Context, int, string, string) (int64, error)
+	UpdateDepositBlockRepo(context.Context, int64, int64, string) (int64, error)
+	QueryBlockTransactionRepo(context.Context, int, string, string, int64) (*[]WalletTransaction, error)
 	InsertWithdrawRepo(context.Context, int, string, int, string, float64, string, string) (int64, error)
+	InsertWithdrawFeeRepo(context.Context, int64, int, string, int, string, float64, string) (int64, error)
 	UpdateWithdrawRepo(context.Context, int, string, string, string) (int64, error)
+	UpdateWithdrawStatusRepo(context.Context, int, string, string, string) (int64, error)
+	FailWithdrawRepo(context.Context, int, int, float64, float64, string) (int64, error)
+	OrphanDepositRepo(context.Context, int, int, float64, float64, string) (int64, error)
+	CreditDepositRepo(context.Context, int, string, string, int, string, string, float64, int64, string, float64, float64, string) (int64, error)
 	QueryWalletRepo(context.Context, int) (*Wallet, error)
+	QueryWalletsRepo(context.Context) (*[]Wallet, error)
 	UpdateWalletRepo(context.Context, int, float64, float64, *string, string) (int64, error)
+	UpdateMarginCallRepo(context.Context, int, *string, string) (int64, error)
 	QueryContractByIDRepo(context.Context, int) (*Contract, error)
 	QueryContractRepo(context.Context, map[string]interface{}) (*[]Contract, error)
 	InsertContractRepo(context.Context, int, int, float64, int) (int64, error)
@@ -87,4 +150,22 @@ type LendingRepository interface {
 	InsertRepayTransactionRepo(context.Context, int, int, float64, string) (int64, error)
 	UpdateRepayTransactionRepo(context.Context, int, string, string) (int64, error)
 	LiquidationRepo(context.Context, int, int) (*Liquidation, error)
+	QueryDepositAddressRepo(context.Context, int) (*DepositAddress, error)
+	QueryDepositAddressesRepo(context.Context) (*[]DepositAddress, error)
+	InsertDepositAddressRepo(context.Context, int, string, string) (int64, error)
+	QueryDepositScanRepo(context.Context, int) (*int64, error)
+	UpsertDepositScanRepo(context.Context, int, int64, string) (int64, error)
+	QueryWithdrawAddressRepo(context.Context, int, string) (*WithdrawAddress, error)
+	QueryWithdrawAddressesRepo(context.Context, int) (*[]WithdrawAddress, error)
+	InsertWithdrawAddressRepo(context.Context, int, string, string, bool, string) (int64, error)
+	DeleteWithdrawAddressRepo(context.Context, int, int) (int64, error)
+	InsertReserveReportRepo(context.Context, string, string, string, string, []ReserveLiability) (int64, error)
+	QueryLatestReserveReportRepo(context.Context) (*ReserveReport, error)
+	QueryReserveLiabilitiesRepo(context.Context, string) (*[]ReserveLiability, error)
+	QuerySweepRepo(context.Context, map[string]interface{}) (*[]Sweep, error)
+	InsertSweepRepo(context.Context, int, int, string, string, float64, string, string, string) (int64, error)
+	UpdateSweepRepo(context.Context, int, string, float64, string, string) (int64, error)
+	UpdateSweepStatusRepo(context.Context, int, string, string, string) (int64, error)
+	UpdateDepositSweepRepo(context.Context, int64, int, string, int, string) (int64, error)
+	ClearDepositSweepRepo(context.Context, int) (int64, error)
 }
@@ -0,0 +1,96 @@
+package lending
+
+import (
+	"context"
+	"fmt"
+	"lending-engine/internal/redis"
+	"strconv"
+
+	"go.uber.org/zap"
+)
+
+// WalletChannel is the redis pub/sub channel of accounts whose wallet or contracts changed, every message is an account id.
+const WalletChannel = "WALLET"
+
+// publishingRepository is a LendingRepository that publishes the account id to WalletChannel after a collateral balance or
+// a contract of the account is written. Failing to publish is logged and doesn't fail the write.
+type publishingRepository struct {
+	LendingRepository
+	PublishRedisFn redis.PublishRedisFn
+	Logger         *zap.Logger
+}
+
+func NewPublishingRepository(lendingRepository LendingRepository, publishRedisFn redis.PublishRedisFn, logger *zap.Logger) LendingRepository {
+	return &publishingRepository{
+		LendingRepository: lendingRepository,
+		PublishRedisFn:    publishRedisFn,
+		Logger:            logger,
+	}
+}
+
+func (r *publishingRepository) UpdateWalletRepo(ctx context.Context, accountId int, btc float64, eth float64, margin *string, latest string) (int64, error) {
+	rows, err := r.LendingRepository.UpdateWalletRepo(ctx, accountId, btc, eth, margin, latest)
+	if err == nil && rows > 0 {
+		r.publish(accountId)
+	}
+	return rows, err
+}
+
+func (r *publishingRepository) UpdateMarginCallRepo(ctx context.Context, accountId int, margin *string, latest string) (int64, error) {
+	rows, err := r.LendingRepository.UpdateMarginCallRepo(ctx, accountId, margin, latest)
+	if err == nil && rows > 0 {
+		r.publish(accountId)
+	}
+	return rows, err
+}
+
+func (r *publishingRepository) FailWithdrawRepo(ctx context.Context, id int, accountId int, btc float64, eth float64, timestamp string) (int64, error) {
+	rows, err := r.LendingRepository.FailWithdrawRepo(ctx, id, accountId, btc, eth, timestamp)
+	if err == nil && rows > 0 {
+		r.publish(accountId)
+	}
+	return rows, err
+}
+
+func (r *publishingRepository) OrphanDepositRepo(ctx context.Context, id int, accountId int, btc float64, eth float64, timestamp string) (int64, error) {
+	rows, err := r.LendingRepository.OrphanDepositRepo(ctx, id, accountId, btc, eth, timestamp)
+	if err == nil && rows > 0 {
+		r.publish(accountId)
+	}
+	return rows, err
+}
+
+func (r *publishingRepository) CreditDepositRepo(ctx context.Context, accountId int, address string, toAddress string, chainId int, txnHash string, collateralType string, volume float64, blockNumber int64, blockHash string, btc float64, eth float64, timestamp string) (int64, error) {
+	depositId, err := r.LendingRepository.CreditDepositRepo(ctx, accountId, address, toAddress, chainId, txnHash, collateralType, volume, blockNumber, blockHash, btc, eth, timestamp)
+	if err == nil {
+		r.publish(accountId)
+	}
+	return depositId, err
+}
+
+func (r *publishingRepository) InsertContractRepo(ctx context.Context, accountId int, interestCode int, loan float64, term int) (int64, error) {
+	contractId, err := r.LendingRepository.InsertContractRepo(ctx, accountId, interestCode, loan, term)
+	if err == nil {
+		r.publish(accountId)
+	}
+	return contractId, err
+}
+
+func (r *publishingRepository) UpdateContractRepo(ctx context.Context, contractId int, status string, timestamp string) (int64, error) {
+	rows, err := r.LendingRepository.UpdateContractRepo(ctx, contractId, status, timestamp)
+	if err == nil && rows > 0 {
+		contract, err := r.LendingRepository.QueryContractByIDRepo(ctx, contractId)
+		if err != nil {
+			r.Logger.Error(fmt.Sprintf("ContractID: %d | %s", contractId, err.Error()))
+		} else if contract != nil {
+			r.publish(*contract.AccountID)
+		}
+	}
+	return rows, err
+}
+
+func (r *publishingRepository) publish(accountId int) {
+	if err := r.PublishRedisFn(WalletChannel, strconv.Itoa(accountId)); err != nil {
+		r.Logger.Error(fmt.Sprintf("AccountID: %d | %s", accountId, err.Error()))
+	}
+}
@@ -0,0 +1,95 @@
+package lending
+
+import (
+	"context"
+	"fmt"
+	"lending-engine/blockchain"
+	"lending-engine/common"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+const (
+	FeePolicyFixed     string = "fixed"
+	FeePolicyDynamic   string = "dynamic"
+	FeePolicySponsored string = "sponsored"
+)
+
+type WithdrawFee struct {
+	Policy  string              `json:"policy" example:"dynamic"`
+	Fee     float64             `json:"fee" example:"0.0002"`
+	GasCost *blockchain.GasCost `json:"gasCost,omitempty"`
+}
+
+// quoteWithdrawFee returns the fee charged in collateral type for one withdrawal by "withdraw.fee.policy".
+// fixed charges "withdraw.fee.fixed.<collateral>", dynamic converts the current gas cost from the native coin of the chain
+// to the collateral by their THB prices, and sponsored charges nothing. Dynamic falls back to fixed with a warning when
+// the oracle has no price of the native coin, e.g. BNB or MATIC with only BTC and ETH feeds.
+func (s *lendingHandler) quoteWithdrawFee(ctx context.Context, logger *zap.Logger, chainId int, collateralType string) (*WithdrawFee, error) {
+	withdrawFee := WithdrawFee{
+		Policy: viper.GetString("withdraw.fee.policy"),
+	}
+	switch withdrawFee.Policy {
+	case FeePolicySponsored:
+	case FeePolicyFixed:
+		withdrawFee.Fee = viper.GetFloat64(fmt.Sprintf("withdraw.fee.fixed.%s", strings.ToLower(collateralType)))
+	case FeePolicyDynamic:
+		gasCost, err := s.QueryGasCostClientFn(ctx, chainId)
+		if err != nil {
+			return nil, err
+		}
+		if gasCost.Native == "" {
+			return nil, fmt.Errorf("native coin of chain %d isn't configured", chainId)
+		}
+		nativePrice, err := s.GetPriceFn(ctx, gasCost.Native)
+		if err != nil {
+			logger.Warn(fmt.Sprintf("ChainID: %d | no price of native coin %s, withdraw fee falls back to %s policy | %s", chainId, gasCost.Native, FeePolicyFixed, err.Error()))
+			withdrawFee.Policy = FeePolicyFixed
+			withdrawFee.Fee = viper.GetFloat64(fmt.Sprintf("withdraw.fee.fixed.%s", strings.ToLower(collateralType)))
+			break
+		}
+		collateralPrice, err := s.GetPriceFn(ctx, collateralType)
+		if err != nil {
+			return nil, errors.Wrapf(err, "price of %s", collateralType)
+		}
+		withdrawFee.Fee = gasCost.Cost * nativePrice.Price / collateralPrice.Price
+		withdrawFee.GasCost = gasCost
+	default:
+		return nil, fmt.Errorf("unknown withdraw fee policy '%s'", withdrawFee.Policy)
+	}
+	return &withdrawFee, nil
+}
+
+// settleWithdrawFee moves the pending fee line of withdrawId to status and returns its volume, 0 when the withdrawal has no fee.
+func (s *lendingHandler) settleWithdrawFee(ctx context.Context, withdrawId int, txnHash string, status string) (float64, error) {
+	fees, err := s.LendingRepository.QueryWalletTransactionRepo(ctx, map[string]interface{}{
+		"parent_id": withdrawId,
+		"txn_type":  common.WithdrawFeeType,
+		"status":    common.PendingStatus,
+	})
+	if err != nil {
+		return 0, err
+	}
+	var total float64
+	for _, fee := range *fees {
+		if _, err := s.LendingRepository.UpdateWithdrawRepo(ctx, *fee.ID, txnHash, status, time.Now().Format(common.DateYYYYMMDDHHMMSSFormat)); err != nil {
+			return 0, err
+		}
+		total += *fee.Volume
+	}
+	return total, nil
+}
+
+// isColdWithdraw tells that volume is above "withdraw.cold-wallet.threshold.<collateral>" and must be signed by the cold wallet.
+// Every withdrawal can use the hot wallet when the cold wallet isn't configured.
+func isColdWithdraw(collateralType string, volume float64) bool {
+	if blockchain.ColdWalletAddress() == "" {
+		return false
+	}
+	key := fmt.Sprintf("withdraw.cold-wallet.threshold.%s", strings.ToLower(collateralType))
+	return viper.IsSet(key) && volume > viper.GetFloat64(key)
+}
@@ -0,0 +1,68 @@
+package lending
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+
+	"lending-engine/blockchain"
+	"lending-engine/oracle"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// oracleOf prices assets in THB from prices, like an oracle with only BTC and ETH feeds.
+func oracleOf(prices map[string]float64) oracle.GetPriceFn {
+	return func(ctx context.Context, asset string) (*oracle.Price, error) {
+		price, ok := prices[asset]
+		if !ok {
+			return nil, fmt.Errorf("no source has a quote of %s", asset)
+		}
+		return &oracle.Price{Asset: asset, Price: price}, nil
+	}
+}
+
+func TestQuoteWithdrawFee(t *testing.T) {
+	viper.Set("withdraw.fee.policy", FeePolicyDynamic)
+	viper.Set("withdraw.fee.fixed.btc", 0.0002)
+	defer viper.Set("withdraw.fee.policy", nil)
+	defer viper.Set("withdraw.fee.fixed.btc", nil)
+
+	gasCosts := map[int]*blockchain.GasCost{
+		1:  {Native: "ETH", GasLimit: 65000, Cost: 0.002},
+		56: {Native: "BNB", GasLimit: 65000, Cost: 0.0003},
+	}
+	s := &lendingHandler{
+		QueryGasCostClientFn: func(ctx context.Context, chainId int) (*blockchain.GasCost, error) {
+			return gasCosts[chainId], nil
+		},
+		GetPriceFn: oracleOf(map[string]float64{"BTC": 1000000, "ETH": 50000}),
+	}
+
+	tests := []struct {
+		name    string
+		chainId int
+		policy  string
+		fee     float64
+	}{
+		// 0.002 ETH * 50,000 THB / 1,000,000 THB.
+		{name: "priced native coin", chainId: 1, policy: FeePolicyDynamic, fee: 0.0001},
+		{name: "unpriced native coin", chainId: 56, policy: FeePolicyFixed, fee: 0.0002},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withdrawFee, err := s.quoteWithdrawFee(context.Background(), zap.NewNop(), tt.chainId, "BTC")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if withdrawFee.Policy != tt.policy {
+				t.Errorf("policy = %s, want %s", withdrawFee.Policy, tt.policy)
+			}
+			if math.Abs(withdrawFee.Fee-tt.fee) > 1e-12 {
+				t.Errorf("fee = %f, want %f", withdrawFee.Fee, tt.fee)
+			}
+		})
+	}
+}
@@ -1,32 +1,76 @@
 package lending
 
 import (
+	"encoding/json"
 	"fmt"
+	"lending-engine/bitcoin"
 	"lending-engine/blockchain"
 	"lending-engine/common"
 	"lending-engine/internal/handler"
-	"lending-engine/internal/redis"
+	"lending-engine/internal/merkle"
+	"lending-engine/oracle"
 	"lending-engine/response"
+	"lending-engine/risk"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt"
+	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 )
 
 type lendingHandler struct {
-	QueryTransactionClientFn   blockchain.QueryTransactionClientFn
-	LendingRepository          LendingRepository
-	GetFloatDataRedisFn        redis.GetFloatDataRedisFn
-	RequestLiquidationClientFn RequestLiquidationClientFn
+	GetChainFn                      blockchain.GetChainFn
+	QueryTransactionClientFn        blockchain.QueryTransactionClientFn
+	QueryReceiptClientFn            blockchain.QueryReceiptClientFn
+	TransferTokenClientFn           blockchain.TransferTokenClientFn
+	SpeedUpTransactionClientFn      blockchain.SpeedUpTransactionClientFn
+	QueryGasCostClientFn            blockchain.QueryGasCostClientFn
+	BuildUnsignedTransferClientFn   blockchain.BuildUnsignedTransferClientFn
+	BroadcastSignedTransferClientFn blockchain.BroadcastSignedTransferClientFn
+	BuildUnsignedSweepClientFn      blockchain.BuildUnsignedSweepClientFn
+	BroadcastSignedSweepClientFn    blockchain.BroadcastSignedSweepClientFn
+	QueryHotWalletBalanceClientFn   blockchain.QueryHotWalletBalanceClientFn
+	MultisendTokenClientFn          blockchain.MultisendTokenClientFn
+	DeriveAddressFn                 blockchain.DeriveAddressFn
+	QueryBitcoinTransactionClientFn bitcoin.QueryTransactionClientFn
+	LendingRepository               LendingRepository
+	GetPriceFn                      oracle.GetPriceFn
+	CheckBreakerFn                  oracle.CheckBreakerFn
+	GetFXRateFn                     oracle.GetPriceFn
+	GetRiskParametersFn             risk.GetParametersFn
+	RequestLiquidationClientFn      RequestLiquidationClientFn
 }
 
-func NewLendingHandler(lendingRepository LendingRepository, queryTransactionClientFn blockchain.QueryTransactionClientFn, getFloatDataRedisFn redis.GetFloatDataRedisFn, requestLiquidationClientFn RequestLiquidationClientFn) *lendingHandler {
+func NewLendingHandler(lendingRepository LendingRepository, getChainFn blockchain.GetChainFn, queryTransactionClientFn blockchain.QueryTransactionClientFn, queryReceiptClientFn blockchain.QueryReceiptClientFn, transferTokenClientFn blockchain.TransferTokenClientFn, speedUpTransactionClientFn blockchain.SpeedUpTransactionClientFn, queryGasCostClientFn blockchain.QueryGasCostClientFn, buildUnsignedTransferClientFn blockchain.BuildUnsignedTransferClientFn, broadcastSignedTransferClientFn blockchain.BroadcastSignedTransferClientFn, buildUnsignedSweepClientFn blockchain.BuildUnsignedSweepClientFn, broadcastSignedSweepClientFn blockchain.BroadcastSignedSweepClientFn, queryHotWalletBalanceClientFn blockchain.QueryHotWalletBalanceClientFn, multisendTokenClientFn blockchain.MultisendTokenClientFn, deriveAddressFn blockchain.DeriveAddressFn, queryBitcoinTransactionClientFn bitcoin.QueryTransactionClientFn, getPriceFn oracle.GetPriceFn, checkBreakerFn oracle.CheckBreakerFn, getFXRateFn oracle.GetPriceFn, getRiskParametersFn risk.GetParametersFn, requestLiquidationClientFn RequestLiquidationClientFn) *lendingHandler {
 	return &lendingHandler{
-		QueryTransactionClientFn:   queryTransactionClientFn,
-		LendingRepository:          lendingRepository,
-		GetFloatDataRedisFn:        getFloatDataRedisFn,
-		RequestLiquidationClientFn: requestLiquidationClientFn,
+		GetChainFn:                      getChainFn,
+		QueryTransactionClientFn:        queryTransactionClientFn,
+		QueryReceiptClientFn:            queryReceiptClientFn,
+		TransferTokenClientFn:           transferTokenClientFn,
+		SpeedUpTransactionClientFn:      speedUpTransactionClientFn,
+		QueryGasCostClientFn:            queryGasCostClientFn,
+		BuildUnsignedTransferClientFn:   buildUnsignedTransferClientFn,
+		BroadcastSignedTransferClientFn: broadcastSignedTransferClientFn,
+		BuildUnsignedSweepClientFn:      buildUnsignedSweepClientFn,
+		BroadcastSignedSweepClientFn:    broadcastSignedSweepClientFn,
+		QueryHotWalletBalanceClientFn:   queryHotWalletBalanceClientFn,
+		MultisendTokenClientFn:          multisendTokenClientFn,
+		DeriveAddressFn:                 deriveAddressFn,
+		QueryBitcoinTransactionClientFn: queryBitcoinTransactionClientFn,
+		LendingRepository:               lendingRepository,
+		GetPriceFn:                      getPriceFn,
+		CheckBreakerFn:                  checkBreakerFn,
+		GetFXRateFn:                     getFXRateFn,
+		GetRiskParametersFn:             getRiskParametersFn,
+		RequestLiquidationClientFn:      requestLiquidationClientFn,
 	}
 }
 
@@ -36,29 +80,54 @@ func NewLendingHandler(lendingRepository LendingRepository, queryTransactionClie
 // @Tags Lending
 // @Accept json
 // @Produce json
+// @Param currency query string false "Quote currency, THB (default) or USD"
 // @Success 200 {object} response.Response{data=lending.GetTokenPriceResponse} "Success"
 // @Failure 400 {object} response.ErrResponse "Bad Request"
 // @Failure 500 {object} response.ErrResponse "Internal Server Error"
 // @Router /price [get]
 func (s *lendingHandler) GetTokenPrice(c *handler.Ctx) error {
-	thbbtc, err := s.GetFloatDataRedisFn(common.THBBTCRedis)
+	var req GetTokenPriceRequest
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).GetTokenPriceRequest, err.Error()))
+	}
+	if err := req.validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).GetTokenPriceRequest, err.Error()))
+	}
+
+	thbbtc, err := s.GetPriceFn(c.Context(), "BTC")
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalRedis, err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalPrice, err.Error()))
+	}
+	thbeth, err := s.GetPriceFn(c.Context(), "ETH")
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalPrice, err.Error()))
 	}
-	thbeth, err := s.GetFloatDataRedisFn(common.THBETHRedis)
+	fxRate, err := s.fxRate(c.Context(), req.Currency)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalPrice, err.Error()))
+	}
+	btcRisk, ethRisk, err := riskParameters(s.GetRiskParametersFn)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalRedis, err.Error()))
 	}
 	getTokenPriceResponse := GetTokenPriceResponse{
 		BTC: TokenPrice{
-			Price:   thbbtc,
-			Haircut: viper.GetFloat64("loan.haircut.btc"),
+			Price:     convert(thbbtc.Price, fxRate),
+			Haircut:   btcRisk.Haircut,
+			Timestamp: thbbtc.Timestamp,
+			Stale:     thbbtc.Stale,
+			Sources:   thbbtc.Sources,
 		},
 		ETH: TokenPrice{
-			Price:   thbeth,
-			Haircut: viper.GetFloat64("loan.haircut.eth"),
+			Price:     convert(thbeth.Price, fxRate),
+			Haircut:   ethRisk.Haircut,
+			Timestamp: thbeth.Timestamp,
+			Stale:     thbeth.Stale,
+			Sources:   thbeth.Sources,
 		},
 		InterestRate: viper.GetFloat64("loan.interest"),
+		Currency:     req.Currency,
+		FXRate:       fxRate,
 	}
 	return c.Status(fiber.StatusOK).JSON(response.NewResponse(response.ResponseContextLocale(c.Context()).GetTokenPriceSuccess, &getTokenPriceResponse))
 }
@@ -72,17 +141,27 @@ func (s *lendingHandler) PreCalculationLoan(c *handler.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).PreCalculationLoanRequest, err.Error()))
 	}
 
-	thbbtc, err := s.GetFloatDataRedisFn(common.THBBTCRedis)
+	thbbtc, err := s.GetPriceFn(c.Context(), "BTC")
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalRedis, err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalPrice, err.Error()))
+	}
+	thbeth, err := s.GetPriceFn(c.Context(), "ETH")
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalPrice, err.Error()))
+	}
+
+	fxRate, err := s.fxRate(c.Context(), req.Currency)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalPrice, err.Error()))
 	}
-	thbeth, err := s.GetFloatDataRedisFn(common.THBETHRedis)
+
+	btcRisk, ethRisk, err := riskParameters(s.GetRiskParametersFn)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalRedis, err.Error()))
 	}
 
-	btcLoan := req.BTCAmount * thbbtc * viper.GetFloat64("loan.haircut.btc")
-	ethLoan := req.ETHAmount * thbeth * viper.GetFloat64("loan.haircut.eth")
+	btcLoan := convert(req.BTCAmount*thbbtc.Price*btcRisk.Haircut, fxRate)
+	ethLoan := convert(req.ETHAmount*thbeth.Price*ethRisk.Haircut, fxRate)
 
 	totalLoanAmount := btcLoan + ethLoan
 	monthlyInterest := totalLoanAmount * viper.GetFloat64("loan.interest") / 12
@@ -91,12 +170,12 @@ func (s *lendingHandler) PreCalculationLoan(c *handler.Ctx) error {
 	preCalculationLoanResponse := PreCalculationLoanResponse{
 		BTC: TokenPriceRate{
 			Volume:     req.BTCAmount,
-			Haircut:    viper.GetFloat64("loan.haircut.btc"),
+			Haircut:    btcRisk.Haircut,
 			LoanAmount: btcLoan,
 		},
 		ETH: TokenPriceRate{
 			Volume:     req.ETHAmount,
-			Haircut:    viper.GetFloat64("loan.haircut.btc"),
+			Haircut:    ethRisk.Haircut,
 			LoanAmount: ethLoan,
 		},
 		Summary: SummaryLoan{
@@ -106,6 +185,8 @@ func (s *lendingHandler) PreCalculationLoan(c *handler.Ctx) error {
 			Period:          req.Period,
 			TotalInterest:   totalInterest,
 		},
+		Currency: req.Currency,
+		FXRate:   fxRate,
 	}
 	return c.Status(fiber.StatusOK).JSON(response.NewResponse(response.ResponseContextLocale(c.Context()).PreCalculationLoanSuccess, &preCalculationLoanResponse))
 }
@@ -135,7 +216,7 @@ func (s *lendingHandler) GetWalletTransaction(c *handler.Ctx) error {
 
 // SubmitDeposit
 // @Summary Submit Deposit
-// @Description submit deposit transaction
+// @Description submit deposit transaction. Native bitcoin deposit uses chainId of "bitcoin.chain-id" and waits for admin confirmation.
 // @Tags Lending
 // @Accept json
 // @Produce json
@@ -159,19 +240,74 @@ func (s *lendingHandler) SubmitDeposit(c *handler.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).SubmitDepositRequest, err.Error()))
 	}
 
+	// native bitcoin has no evm chain, its deposit is verified with bitcoin rpc instead.
+	isBitcoin := req.ChainID == bitcoin.ChainID()
+	if isBitcoin && req.CollateralType != "BTC" {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).SubmitDepositRequest, fmt.Sprintf("Chain id %d only accepts BTC.", req.ChainID)))
+	}
+	var chain *blockchain.Chain
+	if !isBitcoin {
+		var err error
+		chain, err = s.GetChainFn(req.ChainID)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).SubmitDepositRequest, err.Error()))
+		}
+	}
+
+	txns, err := s.LendingRepository.QueryWalletTransactionRepo(c.Context(), map[string]interface{}{
+		"txn_hash": req.TxnHash,
+		"txn_type": common.DepositStatus,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+	}
+	for _, txn := range *txns {
+		if *txn.Status != common.RejectStatus && *txn.Status != common.OrphanedStatus {
+			return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).SubmitDepositRequest, "This txnHash has already been submitted."))
+		}
+	}
+
+	depositAddress, err := s.LendingRepository.QueryDepositAddressRepo(c.Context(), accountId)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+	}
+
 	status := common.PendingStatus
+	var toAddress string
+	var block int64
+	var blockHash string
 
-	if viper.GetBool("toggle.query-txn") {
-		result, isPending, err := s.QueryTransactionClientFn(c.Context(), req.ChainID, req.TxnHash)
+	if viper.GetBool("toggle.query-txn") && isBitcoin {
+		result, isPending, err := s.QueryBitcoinTransactionClientFn(c.Context(), req.TxnHash)
 		if err != nil {
 			c.Log().Error(err.Error())
 		}
 		if isPending {
 			c.Log().Info(fmt.Sprintf("Txn Hash: %s | Txn Status: %t", req.TxnHash, isPending))
 		}
+		// every account pays the same address and inputs don't name a sender, so the transaction can't tell who paid it.
+		// the deposit stays pending for admin confirmation, the lookup is only logged for the admin.
 		if result != nil {
-			if result.TokenTransfer.From == req.Address && result.TokenTransfer.To == viper.GetString("blockchain.address") && result.TokenTransfer.Amount == req.Volume {
+			amount := result.AmountTo(bitcoin.Address())
+			c.Log().Info(fmt.Sprintf("To: %s | Amount: %f | Confirmations: %d | Matched: %t", bitcoin.Address(), amount, result.Confirmations, bitcoin.Satoshi(amount) == bitcoin.Satoshi(req.Volume)))
+		}
+	} else if viper.GetBool("toggle.query-txn") {
+		var result *blockchain.TransactionInfo
+		var isPending bool
+		result, isPending, err = s.QueryTransactionClientFn(c.Context(), req.ChainID, req.TxnHash)
+		if err != nil {
+			c.Log().Error(err.Error())
+		}
+		if isPending {
+			c.Log().Info(fmt.Sprintf("Txn Hash: %s | Txn Status: %t", req.TxnHash, isPending))
+		}
+		if result != nil {
+			isOurAddress := result.TokenTransfer.To == chain.Address || (depositAddress != nil && strings.EqualFold(result.TokenTransfer.To, *depositAddress.Address))
+			if result.TokenTransfer.From == req.Address && isOurAddress && result.TokenTransfer.Amount == req.Volume {
 				status = common.ConfirmStatus
+				toAddress = result.TokenTransfer.To
+				block = result.Block
+				blockHash = result.BlockHash
 			}
 			c.Log().Info(fmt.Sprintf("From: %s | Interacted With(To): %s | To: %s | Amount: %f", result.From, result.InteractedWith, result.TokenTransfer.To, result.TokenTransfer.Amount))
 		}
@@ -179,12 +315,17 @@ func (s *lendingHandler) SubmitDeposit(c *handler.Ctx) error {
 
 	c.Log().Info(fmt.Sprintf("Deposit Status: %s", status))
 
-	depositId, err := s.LendingRepository.InsertDepositRepo(c.Context(), accountId, req.Address, req.ChainID, req.TxnHash, req.CollateralType, req.Volume, common.DepositStatus, status)
+	depositId, err := s.LendingRepository.InsertDepositRepo(c.Context(), accountId, req.Address, toAddress, req.ChainID, req.TxnHash, req.CollateralType, req.Volume, common.DepositStatus, status)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
 	}
 
 	if status == common.ConfirmStatus {
+		// block hash is kept to detect reorg of the credited deposit.
+		if _, err := s.LendingRepository.UpdateDepositBlockRepo(c.Context(), depositId, block, blockHash); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+		}
+
 		wallet, err := s.LendingRepository.QueryWalletRepo(c.Context(), accountId)
 		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
@@ -218,6 +359,52 @@ func (s *lendingHandler) SubmitDeposit(c *handler.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(response.NewResponse(response.ResponseContextLocale(c.Context()).SubmitDepositSuccess, &submitDepositResponse))
 }
 
+// GetDepositAddress
+// @Summary Get Deposit Address
+// @Description get user's own deposit address. It's derived from the platform xpub on first request.
+// @Tags Lending
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=lending.GetDepositAddressResponse} "Success"
+// @Failure 400 {object} response.ErrResponse "Bad Request"
+// @Failure 500 {object} response.ErrResponse "Internal Server Error"
+// @Security ApiKeyAuth
+// @Router /deposit/address [get]
+func (s *lendingHandler) GetDepositAddress(c *handler.Ctx) error {
+	bearer := c.Locals(common.JWTClaimsKey).(*jwt.Token)
+	claims := bearer.Claims.(jwt.MapClaims)
+	id := claims["accountId"].(float64)
+	accountId := int(id)
+
+	depositAddress, err := s.LendingRepository.QueryDepositAddressRepo(c.Context(), accountId)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+	}
+	if depositAddress == nil {
+		address, err := s.DeriveAddressFn(uint32(accountId))
+		if err != nil {
+			if errors.Is(err, blockchain.ErrHDWalletDisabled) {
+				return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).GetDepositAddressRequest, "Deposit address is disabled."))
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalOperation, err.Error()))
+		}
+		derivationPath := blockchain.DerivationPath(uint32(accountId))
+		if _, err := s.LendingRepository.InsertDepositAddressRepo(c.Context(), accountId, address, derivationPath); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+		}
+		c.Log().Info(fmt.Sprintf("AccountID: %d | Deposit Address: %s (%s)", accountId, address, derivationPath))
+		depositAddress = &DepositAddress{
+			Address:        &address,
+			DerivationPath: &derivationPath,
+		}
+	}
+	getDepositAddressResponse := GetDepositAddressResponse{
+		Address:        *depositAddress.Address,
+		DerivationPath: *depositAddress.DerivationPath,
+	}
+	return c.Status(fiber.StatusOK).JSON(response.NewResponse(response.ResponseContextLocale(c.Context()).GetDepositAddressSuccess, &getDepositAddressResponse))
+}
+
 // SubmitWithdraw
 // @Summary Submit Withdraw
 // @Description submit withdraw transaction
@@ -230,6 +417,7 @@ func (s *lendingHandler) SubmitDeposit(c *handler.Ctx) error {
 // @Success 200 {object} response.Response{data=lending.SubmitWithdrawResponse} "Success"
 // @Failure 400 {object} response.ErrResponse "Bad Request"
 // @Failure 500 {object} response.ErrResponse "Internal Server Error"
+// @Failure 503 {object} response.ErrResponse "Service Unavailable"
 // @Security ApiKeyAuth
 // @Router /withdraw [post]
 func (s *lendingHandler) SubmitWithdraw(c *handler.Ctx) error {
@@ -245,17 +433,215 @@ func (s *lendingHandler) SubmitWithdraw(c *handler.Ctx) error {
 	if err := req.validate(); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).SubmitWithdrawRequest, err.Error()))
 	}
+	if _, err := s.GetChainFn(req.ChainID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).SubmitWithdrawRequest, err.Error()))
+	}
+
+	withdrawAddress, err := s.LendingRepository.QueryWithdrawAddressRepo(c.Context(), accountId, gethcommon.HexToAddress(req.Address).Hex())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+	}
+	if withdrawAddress == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).SubmitWithdrawRequest, "This address isn't in withdraw address book."))
+	}
+	if activeDatetime := wallClock(*withdrawAddress.ActiveDatetime); time.Now().Before(activeDatetime) {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).SubmitWithdrawRequest, fmt.Sprintf("This address can be used after %s.", activeDatetime.Format(common.DateYYYYMMDDHHMMSSFormat))))
+	}
+
+	if _, err := s.tradablePrice(c.Context(), req.CollateralType); err != nil {
+		return priceErrResponse(c, err)
+	}
+
+	withdrawFee, err := s.quoteWithdrawFee(c.Context(), c.Log(), req.ChainID, req.CollateralType)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).GetWithdrawFeeBlockErr, err.Error()))
+	}
+	if withdrawFee.Fee >= req.Volume {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).SubmitWithdrawRequest, fmt.Sprintf("'volume' must be more than withdraw fee %f %s.", withdrawFee.Fee, req.CollateralType)))
+	}
+	receiveVolume := req.Volume - withdrawFee.Fee
 
-	withdrawId, err := s.LendingRepository.InsertWithdrawRepo(c.Context(), accountId, req.Address, req.ChainID, req.CollateralType, req.Volume, common.WithdrawStatus, common.PendingStatus)
+	withdrawId, err := s.LendingRepository.InsertWithdrawRepo(c.Context(), accountId, req.Address, req.ChainID, req.CollateralType, receiveVolume, common.WithdrawStatus, common.PendingStatus)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
 	}
+	if withdrawFee.Fee > 0 {
+		if _, err := s.LendingRepository.InsertWithdrawFeeRepo(c.Context(), withdrawId, accountId, req.Address, req.ChainID, req.CollateralType, withdrawFee.Fee, common.PendingStatus); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+		}
+	}
 	submitWithdrawResponse := SubmitWithdrawResponse{
-		WithdrawID: withdrawId,
+		WithdrawID:    withdrawId,
+		Fee:           withdrawFee.Fee,
+		ReceiveVolume: receiveVolume,
 	}
 	return c.Status(fiber.StatusOK).JSON(response.NewResponse(response.ResponseContextLocale(c.Context()).SubmitWithdrawSuccess, &submitWithdrawResponse))
 }
 
+// GetWithdrawFee
+// @Summary Get Withdraw Fee
+// @Description quote network fee deducted from withdraw volume
+// @Tags Lending
+// @Accept json
+// @Produce json
+// @Param chainId query int true "Chain ID"
+// @Param collateralType query string true "Collateral Type"
+// @Param volume query number true "Withdraw Volume"
+// @Success 200 {object} response.Response{data=lending.GetWithdrawFeeResponse} "Success"
+// @Failure 400 {object} response.ErrResponse "Bad Request"
+// @Failure 500 {object} response.ErrResponse "Internal Server Error"
+// @Security ApiKeyAuth
+// @Router /withdraw/fee [get]
+func (s *lendingHandler) GetWithdrawFee(c *handler.Ctx) error {
+	var req GetWithdrawFeeRequest
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).GetWithdrawFeeRequest, err.Error()))
+	}
+	if err := req.validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).GetWithdrawFeeRequest, err.Error()))
+	}
+	if _, err := s.GetChainFn(req.ChainID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).GetWithdrawFeeRequest, err.Error()))
+	}
+
+	withdrawFee, err := s.quoteWithdrawFee(c.Context(), c.Log(), req.ChainID, req.CollateralType)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).GetWithdrawFeeBlockErr, err.Error()))
+	}
+	receiveVolume := req.Volume - withdrawFee.Fee
+	if receiveVolume < 0 {
+		receiveVolume = 0
+	}
+	getWithdrawFeeResponse := GetWithdrawFeeResponse{
+		WithdrawFee:    *withdrawFee,
+		CollateralType: strings.ToUpper(req.CollateralType),
+		Volume:         req.Volume,
+		ReceiveVolume:  receiveVolume,
+	}
+	return c.Status(fiber.StatusOK).JSON(response.NewResponse(response.ResponseContextLocale(c.Context()).GetWithdrawFeeSuccess, &getWithdrawFeeResponse))
+}
+
+// GetWithdrawAddress
+// @Summary Get Withdraw Address
+// @Description get user's withdraw address book
+// @Tags Lending
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=[]lending.WithdrawAddress} "Success"
+// @Failure 400 {object} response.ErrResponse "Bad Request"
+// @Failure 500 {object} response.ErrResponse "Internal Server Error"
+// @Security ApiKeyAuth
+// @Router /withdraw/address [get]
+func (s *lendingHandler) GetWithdrawAddress(c *handler.Ctx) error {
+	bearer := c.Locals(common.JWTClaimsKey).(*jwt.Token)
+	claims := bearer.Claims.(jwt.MapClaims)
+	id := claims["accountId"].(float64)
+	accountId := int(id)
+
+	withdrawAddresses, err := s.LendingRepository.QueryWithdrawAddressesRepo(c.Context(), accountId)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+	}
+	return c.Status(fiber.StatusOK).JSON(response.NewResponse(response.ResponseContextLocale(c.Context()).GetWithdrawAddressSuccess, withdrawAddresses))
+}
+
+// AddWithdrawAddress
+// @Summary Add Withdraw Address
+// @Description add address to user's withdraw address book. The address must be EIP-55 checksum. It can be used after cooling-off period.
+// @Description Optional signature is EIP-191 personal_sign of "I own {address} and allow ICFIN account {accountId} to withdraw to it." by the address.
+// @Tags Lending
+// @Accept json
+// @Produce json
+// @Param ReferenceNo header string true "reference number."
+// @Param OTP header string true "one time password."
+// @Param AddWithdrawAddress body lending.AddWithdrawAddressRequest true "request body to add withdraw address"
+// @Success 200 {object} response.Response{data=lending.AddWithdrawAddressResponse} "Success"
+// @Failure 400 {object} response.ErrResponse "Bad Request"
+// @Failure 500 {object} response.ErrResponse "Internal Server Error"
+// @Security ApiKeyAuth
+// @Router /withdraw/address [post]
+func (s *lendingHandler) AddWithdrawAddress(c *handler.Ctx) error {
+	bearer := c.Locals(common.JWTClaimsKey).(*jwt.Token)
+	claims := bearer.Claims.(jwt.MapClaims)
+	id := claims["accountId"].(float64)
+	accountId := int(id)
+
+	var req AddWithdrawAddressRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).AddWithdrawAddressRequest, err.Error()))
+	}
+	if err := req.validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).AddWithdrawAddressRequest, err.Error()))
+	}
+
+	withdrawAddress, err := s.LendingRepository.QueryWithdrawAddressRepo(c.Context(), accountId, req.Address)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+	}
+	if withdrawAddress != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).AddWithdrawAddressRequest, "This address has already been added."))
+	}
+
+	verified := false
+	if utf8.RuneCountInString(req.Signature) != 0 {
+		verified, err = blockchain.VerifyPersonalSign(req.Address, WithdrawAddressMessage(accountId, req.Address), req.Signature)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).AddWithdrawAddressRequest, err.Error()))
+		}
+		if !verified {
+			return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).AddWithdrawAddressRequest, "Signature isn't signed by this address."))
+		}
+	} else if viper.GetBool("withdraw.address.require-signature") {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).AddWithdrawAddressRequest, "'signature' must be REQUIRED field."))
+	}
+
+	activeDatetime := time.Now().Add(viper.GetDuration("withdraw.address.cooling-off")).Format(common.DateYYYYMMDDHHMMSSFormat)
+	withdrawAddressId, err := s.LendingRepository.InsertWithdrawAddressRepo(c.Context(), accountId, req.Address, req.Label, verified, activeDatetime)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+	}
+	c.Log().Info(fmt.Sprintf("AccountID: %d | Withdraw Address: %s - Verified: %t - Active: %s", accountId, req.Address, verified, activeDatetime))
+	addWithdrawAddressResponse := AddWithdrawAddressResponse{
+		ID:             withdrawAddressId,
+		Verified:       verified,
+		ActiveDatetime: activeDatetime,
+	}
+	return c.Status(fiber.StatusOK).JSON(response.NewResponse(response.ResponseContextLocale(c.Context()).AddWithdrawAddressSuccess, &addWithdrawAddressResponse))
+}
+
+// RemoveWithdrawAddress
+// @Summary Remove Withdraw Address
+// @Description remove address from user's withdraw address book
+// @Tags Lending
+// @Accept json
+// @Produce json
+// @Param id path int true "Withdraw Address ID"
+// @Success 200 {object} response.Response "Success"
+// @Failure 400 {object} response.ErrResponse "Bad Request"
+// @Failure 500 {object} response.ErrResponse "Internal Server Error"
+// @Security ApiKeyAuth
+// @Router /withdraw/address/{id} [delete]
+func (s *lendingHandler) RemoveWithdrawAddress(c *handler.Ctx) error {
+	bearer := c.Locals(common.JWTClaimsKey).(*jwt.Token)
+	claims := bearer.Claims.(jwt.MapClaims)
+	id := claims["accountId"].(float64)
+	accountId := int(id)
+
+	withdrawAddressId, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).RemoveWithdrawAddressRequest, fmt.Sprintf("'id' must be number but the input is '%v'.", c.Params("id"))))
+	}
+	rows, err := s.LendingRepository.DeleteWithdrawAddressRepo(c.Context(), withdrawAddressId, accountId)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+	}
+	if rows != 1 {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).RemoveWithdrawAddressRequest, "ID doesn't exist."))
+	}
+	c.Log().Info(fmt.Sprintf("AccountID: %d | Remove Withdraw Address ID: %d", accountId, withdrawAddressId))
+	return c.Status(fiber.StatusOK).JSON(response.NewResponse(response.ResponseContextLocale(c.Context()).RemoveWithdrawAddressSuccess, nil))
+}
+
 // GetWalletTransactionAdmin
 // @Summary Get Wallet Transaction Admin
 // @Description get wallet transaction by id, account id, address or txn type
@@ -363,9 +749,32 @@ func (s *lendingHandler) ConfirmDepositAdmin(c *handler.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalOperation, fmt.Sprintf("expected to affect 1 row, affected %d", walletRows)))
 	}
 	c.Log().Info(fmt.Sprintf("TxnID: %d - Status: %s | AccountID: %d - BTC: %f - ETH: %f", req.ID, common.ConfirmStatus, *txn.AccountID, btc, eth))
+
+	if viper.GetBool("toggle.query-txn") && *txn.ChainID != bitcoin.ChainID() {
+		s.recordDepositBlock(c, txn)
+	}
 	return c.Status(fiber.StatusOK).JSON(response.NewResponse(response.ResponseContextLocale(c.Context()).ConfirmDepositAdminSuccess, nil))
 }
 
+// recordDepositBlock keeps the block hash of a deposit confirmed by admin, so the reorg watcher checks it like deposits
+// confirmed on submission. The deposit stays confirmed when its block can't be read, it just isn't watched.
+func (s *lendingHandler) recordDepositBlock(c *handler.Ctx, txn *WalletTransaction) {
+	receipt, err := s.QueryReceiptClientFn(c.Context(), *txn.ChainID, *txn.TxnHash)
+	if err != nil {
+		c.Log().Error(fmt.Sprintf("TxnID: %d | cannot read block of Txn Hash: %s, reorg isn't watched | %s", *txn.ID, *txn.TxnHash, err.Error()))
+		return
+	}
+	if !receipt.Found || receipt.Pending {
+		c.Log().Info(fmt.Sprintf("TxnID: %d | Txn Hash: %s isn't mined, reorg isn't watched", *txn.ID, *txn.TxnHash))
+		return
+	}
+	if _, err := s.LendingRepository.UpdateDepositBlockRepo(c.Context(), int64(*txn.ID), receipt.Block, receipt.BlockHash); err != nil {
+		c.Log().Error(fmt.Sprintf("TxnID: %d | cannot record block %d (%s) | %s", *txn.ID, receipt.Block, receipt.BlockHash, err.Error()))
+		return
+	}
+	c.Log().Info(fmt.Sprintf("TxnID: %d | Block: %d (%s) | Receipt Status: %d", *txn.ID, receipt.Block, receipt.BlockHash, receipt.Status))
+}
+
 // RejectDepositAdmin
 // @Summary Reject Deposit Admin
 // @Description reject deposit transaction by account id
@@ -413,14 +822,15 @@ func (s *lendingHandler) RejectDepositAdmin(c *handler.Ctx) error {
 
 // ConfirmWithdrawAdmin
 // @Summary Confirm Withdraw Admin
-// @Description confirm withdraw transaction by account id
+// @Description confirm withdraw transaction by account id. hot wallet sends the transfer when txnHash is empty.
 // @Tags Admin
 // @Accept json
 // @Produce json
 // @Param ConfirmWithdrawAdmin body lending.ConfirmWithdrawAdminRequest true "request body to confirm withdraw"
-// @Success 200 {object} response.Response "Success"
+// @Success 200 {object} response.Response{data=lending.ConfirmWithdrawAdminResponse} "Success"
 // @Failure 400 {object} response.ErrResponse "Bad Request"
 // @Failure 500 {object} response.ErrResponse "Internal Server Error"
+// @Security AdminAuth
 // @Router /admin/withdraw/confirm [post]
 func (s *lendingHandler) ConfirmWithdrawAdmin(c *handler.Ctx) error {
 	var req ConfirmWithdrawAdminRequest
@@ -445,12 +855,35 @@ func (s *lendingHandler) ConfirmWithdrawAdmin(c *handler.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).ConfirmWithdrawAdminRequest, "This id isn't withdraw method."))
 	}
 
-	withdrawRows, err := s.LendingRepository.UpdateWithdrawRepo(c.Context(), req.ID, req.TxnHash, common.ConfirmStatus, time.Now().Format(common.DateYYYYMMDDHHMMSSFormat))
+	txnHash := req.TxnHash
+	if utf8.RuneCountInString(txnHash) == 0 && isColdWithdraw(*txn.CollateralType, *txn.Volume) {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).ConfirmWithdrawAdminRequest, "Volume is above hot wallet limit, sign it with cold wallet from /admin/withdraw/unsigned."))
+	}
+	claimed, err := s.claimWithdraw(c, req.ID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
 	}
+	if !claimed {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).ConfirmWithdrawAdminRequest, "This id has already confirmed or cancelled."))
+	}
+	if utf8.RuneCountInString(txnHash) == 0 {
+		txnHash, err = s.TransferTokenClientFn(c.Context(), *txn.ChainID, *txn.CollateralType, *txn.Address, *txn.Volume)
+		if err != nil {
+			s.releaseWithdraw(c, req.ID)
+			if errors.Is(err, blockchain.ErrHotWalletDisabled) {
+				return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).ConfirmWithdrawAdminRequest, "'txnHash' must be REQUIRED field when hot wallet is disabled."))
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).ConfirmWithdrawAdminBlockErr, err.Error()))
+		}
+		c.Log().Info(fmt.Sprintf("TxnID: %d | Broadcast Txn Hash: %s", req.ID, txnHash))
+	}
+
+	withdrawRows, err := s.LendingRepository.UpdateWithdrawRepo(c.Context(), req.ID, txnHash, common.BroadcastStatus, time.Now().Format(common.DateYYYYMMDDHHMMSSFormat))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, unrecordedWithdraw(req.ID, txnHash, err.Error())))
+	}
 	if withdrawRows != 1 {
-		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalOperation, fmt.Sprintf("expected to affect 1 row, affected %d", withdrawRows)))
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalOperation, unrecordedWithdraw(req.ID, txnHash, fmt.Sprintf("expected to affect 1 row, affected %d", withdrawRows))))
 	}
 
 	wallet, err := s.LendingRepository.QueryWalletRepo(c.Context(), *txn.AccountID)
@@ -461,16 +894,21 @@ func (s *lendingHandler) ConfirmWithdrawAdmin(c *handler.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalOperation, "Wallet doesn't exist."))
 	}
 
+	fee, err := s.settleWithdrawFee(c.Context(), req.ID, txnHash, common.ConfirmStatus)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+	}
+
 	btc := *wallet.BTCVolume
 	eth := *wallet.ETHVolume
 	switch *txn.CollateralType {
 	case "BTC":
-		btc -= *txn.Volume
+		btc -= *txn.Volume + fee
 		if btc < 0 {
 			btc = 0
 		}
 	case "ETH":
-		eth -= *txn.Volume
+		eth -= *txn.Volume + fee
 		if eth < 0 {
 			eth = 0
 		}
@@ -484,8 +922,33 @@ func (s *lendingHandler) ConfirmWithdrawAdmin(c *handler.Ctx) error {
 	if walletRows != 1 {
 		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalOperation, fmt.Sprintf("expected to affect 1 row, affected %d", walletRows)))
 	}
-	c.Log().Info(fmt.Sprintf("TxnID: %d - Status: %s | AccountID: %d - BTC: %f - ETH: %f", req.ID, common.ConfirmStatus, *txn.AccountID, btc, eth))
-	return c.Status(fiber.StatusOK).JSON(response.NewResponse(response.ResponseContextLocale(c.Context()).ConfirmWithdrawAdminSuccess, nil))
+	c.Log().Info(fmt.Sprintf("TxnID: %d - Status: %s | AccountID: %d - Fee: %f - BTC: %f - ETH: %f", req.ID, common.BroadcastStatus, *txn.AccountID, fee, btc, eth))
+	confirmWithdrawAdminResponse := ConfirmWithdrawAdminResponse{
+		TxnHash: txnHash,
+	}
+	return c.Status(fiber.StatusOK).JSON(response.NewResponse(response.ResponseContextLocale(c.Context()).ConfirmWithdrawAdminSuccess, &confirmWithdrawAdminResponse))
+}
+
+// claimWithdraw moves a pending withdrawal to BROADCASTING before anything is sent for it, so of concurrent or retried
+// confirmations only the one that claims it sends funds.
+func (s *lendingHandler) claimWithdraw(c *handler.Ctx, id int) (bool, error) {
+	rows, err := s.LendingRepository.UpdateWithdrawStatusRepo(c.Context(), id, common.PendingStatus, common.BroadcastingStatus, time.Now().Format(common.DateYYYYMMDDHHMMSSFormat))
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+// releaseWithdraw puts a claimed withdrawal back to PENDING when sending it failed before the node took the transaction.
+func (s *lendingHandler) releaseWithdraw(c *handler.Ctx, id int) {
+	if _, err := s.LendingRepository.UpdateWithdrawStatusRepo(c.Context(), id, common.BroadcastingStatus, common.PendingStatus, time.Now().Format(common.DateYYYYMMDDHHMMSSFormat)); err != nil {
+		c.Log().Error(fmt.Sprintf("TxnID: %d | cannot release withdraw: %s", id, err.Error()))
+	}
+}
+
+// unrecordedWithdraw describes a withdrawal that is sent but couldn't be recorded, it stays BROADCASTING so it is never sent again.
+func unrecordedWithdraw(id int, txnHash string, reason string) string {
+	return fmt.Sprintf("withdraw %d is sent in %s but stays %s: %s", id, txnHash, common.BroadcastingStatus, reason)
 }
 
 // RejectWithdrawAdmin
@@ -529,16 +992,501 @@ func (s *lendingHandler) RejectWithdrawAdmin(c *handler.Ctx) error {
 	if withdrawRows != 1 {
 		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalOperation, fmt.Sprintf("expected to affect 1 row, affected %d", withdrawRows)))
 	}
+	if _, err := s.settleWithdrawFee(c.Context(), req.ID, "-", common.RejectStatus); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+	}
 	c.Log().Info(fmt.Sprintf("TxnID: %d - Status: %s", req.ID, common.RejectStatus))
 	return c.Status(fiber.StatusOK).JSON(response.NewResponse(response.ResponseContextLocale(c.Context()).RejectWithdrawAdminSuccess, nil))
 }
 
+// SpeedUpWithdrawAdmin
+// @Summary Speed Up Withdraw Admin
+// @Description re-send broadcast withdraw transaction with the same nonce and higher fee
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param SpeedUpWithdrawAdmin body lending.SpeedUpWithdrawAdminRequest true "request body to speed up withdraw"
+// @Success 200 {object} response.Response{data=lending.SpeedUpWithdrawAdminResponse} "Success"
+// @Failure 400 {object} response.ErrResponse "Bad Request"
+// @Failure 500 {object} response.ErrResponse "Internal Server Error"
+// @Security AdminAuth
+// @Router /admin/withdraw/speedup [post]
+func (s *lendingHandler) SpeedUpWithdrawAdmin(c *handler.Ctx) error {
+	var req SpeedUpWithdrawAdminRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).SpeedUpWithdrawAdminRequest, err.Error()))
+	}
+	if err := req.validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).SpeedUpWithdrawAdminRequest, err.Error()))
+	}
+
+	txn, err := s.LendingRepository.QueryWalletTransactionByIDRepo(c.Context(), req.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+	}
+	if txn == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).SpeedUpWithdrawAdminRequest, "ID doesn't exist."))
+	}
+	if *txn.TxnType != common.WithdrawStatus {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).SpeedUpWithdrawAdminRequest, "This id isn't withdraw method."))
+	}
+	if *txn.Status != common.BroadcastStatus {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).SpeedUpWithdrawAdminRequest, "This id isn't waiting to be mined."))
+	}
+
+	txnHash, err := s.SpeedUpTransactionClientFn(c.Context(), *txn.ChainID, *txn.TxnHash)
+	if err != nil {
+		if errors.Is(err, blockchain.ErrHotWalletDisabled) {
+			return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).SpeedUpWithdrawAdminRequest, "Hot wallet is disabled."))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).SpeedUpWithdrawAdminBlockErr, err.Error()))
+	}
+
+	// a batched withdrawal shares its transaction, every withdrawal of the batch moves to the replacement.
+	txns, err := s.LendingRepository.QueryWalletTransactionRepo(c.Context(), map[string]interface{}{
+		"txn_hash": *txn.TxnHash,
+		"txn_type": common.WithdrawStatus,
+		"status":   common.BroadcastStatus,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+	}
+	for _, batchTxn := range *txns {
+		withdrawRows, err := s.LendingRepository.UpdateWithdrawRepo(c.Context(), *batchTxn.ID, txnHash, common.BroadcastStatus, time.Now().Format(common.DateYYYYMMDDHHMMSSFormat))
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+		}
+		if withdrawRows != 1 {
+			return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalOperation, fmt.Sprintf("expected to affect 1 row, affected %d", withdrawRows)))
+		}
+		c.Log().Info(fmt.Sprintf("TxnID: %d | Replace Txn Hash: %s -> %s", *batchTxn.ID, *txn.TxnHash, txnHash))
+	}
+	speedUpWithdrawAdminResponse := SpeedUpWithdrawAdminResponse{
+		TxnHash: txnHash,
+	}
+	return c.Status(fiber.StatusOK).JSON(response.NewResponse(response.ResponseContextLocale(c.Context()).SpeedUpWithdrawAdminSuccess, &speedUpWithdrawAdminResponse))
+}
+
+// BatchWithdrawAdmin
+// @Summary Batch Withdraw Admin
+// @Description send pending withdrawals of the same chain and collateral type from hot wallet in one multisend transaction. withdrawals that can't be batched are skipped and stay pending.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param BatchWithdrawAdmin body lending.BatchWithdrawAdminRequest true "request body to batch withdraw"
+// @Success 200 {object} response.Response{data=lending.BatchWithdrawAdminResponse} "Success"
+// @Failure 400 {object} response.ErrResponse "Bad Request"
+// @Failure 500 {object} response.ErrResponse "Internal Server Error"
+// @Security AdminAuth
+// @Router /admin/withdraw/batch [post]
+func (s *lendingHandler) BatchWithdrawAdmin(c *handler.Ctx) error {
+	var req BatchWithdrawAdminRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).BatchWithdrawAdminRequest, err.Error()))
+	}
+	if err := req.validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).BatchWithdrawAdminRequest, err.Error()))
+	}
+	if maxSize := viper.GetInt("withdraw.batch.max-size"); len(req.IDs) > maxSize {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).BatchWithdrawAdminRequest, fmt.Sprintf("Batch can have at most %d withdrawals.", maxSize)))
+	}
+
+	// the first withdrawal that can be batched sets chain and collateral type of the batch.
+	results := make([]BatchWithdrawResult, 0, len(req.IDs))
+	batch := make([]WalletTransaction, 0, len(req.IDs))
+	var chainId int
+	var collateralType string
+	for _, id := range req.IDs {
+		txn, err := s.LendingRepository.QueryWalletTransactionByIDRepo(c.Context(), id)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+		}
+		result := BatchWithdrawResult{
+			ID:     id,
+			Status: SkippedBatchStatus,
+		}
+		switch {
+		case txn == nil:
+			result.Reason = "ID doesn't exist."
+		case *txn.TxnType != common.WithdrawStatus:
+			result.Reason = "This id isn't withdraw method."
+		case *txn.Status != common.PendingStatus:
+			result.Reason = "This id has already confirmed or cancelled."
+		case isColdWithdraw(*txn.CollateralType, *txn.Volume):
+			result.Reason = "Volume is above hot wallet limit."
+		case len(batch) > 0 && (*txn.ChainID != chainId || *txn.CollateralType != collateralType):
+			result.Reason = fmt.Sprintf("Batch is %s on chain %d.", collateralType, chainId)
+		default:
+			if len(batch) == 0 {
+				chainId = *txn.ChainID
+				collateralType = *txn.CollateralType
+			}
+			result.Status = common.PendingStatus
+			batch = append(batch, *txn)
+		}
+		if txn != nil {
+			result.Volume = *txn.Volume
+		}
+		results = append(results, result)
+	}
+	if len(batch) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).BatchWithdrawAdminRequest, "No withdrawal can be batched."))
+	}
+
+	// withdrawals beyond hot wallet balance are skipped in request order, so the rest of the batch can still go.
+	balance, err := s.QueryHotWalletBalanceClientFn(c.Context(), chainId, collateralType)
+	if err != nil {
+		if errors.Is(err, blockchain.ErrHotWalletDisabled) {
+			return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).BatchWithdrawAdminRequest, "Hot wallet is disabled."))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).BatchWithdrawAdminBlockErr, err.Error()))
+	}
+	transfers := make([]blockchain.Transfer, 0, len(batch))
+	sent := make([]WalletTransaction, 0, len(batch))
+	var total float64
+	for _, txn := range batch {
+		if total+*txn.Volume > balance {
+			setBatchResult(results, *txn.ID, SkippedBatchStatus, 0, fmt.Sprintf("Hot wallet %s balance %f is insufficient.", collateralType, balance))
+			continue
+		}
+		claimed, err := s.claimWithdraw(c, *txn.ID)
+		if err != nil {
+			for _, claimedTxn := range sent {
+				s.releaseWithdraw(c, *claimedTxn.ID)
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+		}
+		if !claimed {
+			setBatchResult(results, *txn.ID, SkippedBatchStatus, 0, "This id has already confirmed or cancelled.")
+			continue
+		}
+		total += *txn.Volume
+		transfers = append(transfers, blockchain.Transfer{
+			To:     *txn.Address,
+			Volume: *txn.Volume,
+		})
+		sent = append(sent, txn)
+	}
+	if len(sent) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).BatchWithdrawAdminRequest, fmt.Sprintf("No withdrawal can be sent, hot wallet %s balance is %f.", collateralType, balance)))
+	}
+
+	txnHash, err := s.MultisendTokenClientFn(c.Context(), chainId, collateralType, transfers)
+	if err != nil {
+		for _, txn := range sent {
+			s.releaseWithdraw(c, *txn.ID)
+		}
+		if errors.Is(err, blockchain.ErrMultisendDisabled) {
+			return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).BatchWithdrawAdminRequest, fmt.Sprintf("Multisend isn't configured on chain %d.", chainId)))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).BatchWithdrawAdminBlockErr, err.Error()))
+	}
+	c.Log().Info(fmt.Sprintf("ChainID: %d | %s - Withdrawals: %d - Total: %f | Broadcast Txn Hash: %s", chainId, collateralType, len(sent), total, txnHash))
+
+	// the transaction is already out, so a withdrawal that can't be recorded is reported instead of failing the whole request.
+	for _, txn := range sent {
+		fee, err := s.recordBatchWithdraw(c, txn, txnHash)
+		if err != nil {
+			c.Log().Error(fmt.Sprintf("TxnID: %d | %s", *txn.ID, err.Error()))
+			setBatchResult(results, *txn.ID, ErrorBatchStatus, fee, err.Error())
+			continue
+		}
+		setBatchResult(results, *txn.ID, common.BroadcastStatus, fee, "")
+	}
+	batchWithdrawAdminResponse := BatchWithdrawAdminResponse{
+		ChainID:        chainId,
+		CollateralType: collateralType,
+		TxnHash:        txnHash,
+		Withdrawals:    results,
+	}
+	return c.Status(fiber.StatusOK).JSON(response.NewResponse(response.ResponseContextLocale(c.Context()).BatchWithdrawAdminSuccess, &batchWithdrawAdminResponse))
+}
+
+// recordBatchWithdraw moves one withdrawal of a sent batch to BROADCAST, settles its fee and debits volume and fee from the wallet.
+func (s *lendingHandler) recordBatchWithdraw(c *handler.Ctx, txn WalletTransaction, txnHash string) (float64, error) {
+	withdrawRows, err := s.LendingRepository.UpdateWithdrawRepo(c.Context(), *txn.ID, txnHash, common.BroadcastStatus, time.Now().Format(common.DateYYYYMMDDHHMMSSFormat))
+	if err != nil {
+		return 0, errors.New(unrecordedWithdraw(*txn.ID, txnHash, err.Error()))
+	}
+	if withdrawRows != 1 {
+		return 0, errors.New(unrecordedWithdraw(*txn.ID, txnHash, fmt.Sprintf("expected to affect 1 row, affected %d", withdrawRows)))
+	}
+	fee, err := s.settleWithdrawFee(c.Context(), *txn.ID, txnHash, common.ConfirmStatus)
+	if err != nil {
+		return 0, err
+	}
+	btc, eth, err := addCollateral(c.Context(), s.LendingRepository, *txn.AccountID, *txn.CollateralType, -(*txn.Volume + fee))
+	if err != nil {
+		return fee, err
+	}
+	c.Log().Info(fmt.Sprintf("TxnID: %d - Status: %s | AccountID: %d - Fee: %f - BTC: %f - ETH: %f", *txn.ID, common.BroadcastStatus, *txn.AccountID, fee, btc, eth))
+	return fee, nil
+}
+
+func setBatchResult(results []BatchWithdrawResult, id int, status string, fee float64, reason string) {
+	for i := range results {
+		if results[i].ID == id {
+			results[i].Status = status
+			results[i].Fee = fee
+			results[i].Reason = reason
+		}
+	}
+}
+
+// GetUnsignedWithdrawAdmin
+// @Summary Get Unsigned Withdraw Admin
+// @Description get unsigned transfer of pending withdraw from cold wallet for offline signing
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id query int true "Withdraw ID"
+// @Success 200 {object} response.Response{data=lending.GetUnsignedWithdrawAdminResponse} "Success"
+// @Failure 400 {object} response.ErrResponse "Bad Request"
+// @Failure 500 {object} response.ErrResponse "Internal Server Error"
+// @Router /admin/withdraw/unsigned [get]
+func (s *lendingHandler) GetUnsignedWithdrawAdmin(c *handler.Ctx) error {
+	var req GetUnsignedWithdrawAdminRequest
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).GetUnsignedWithdrawAdminRequest, err.Error()))
+	}
+	if err := req.validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).GetUnsignedWithdrawAdminRequest, err.Error()))
+	}
+
+	txn, err := s.LendingRepository.QueryWalletTransactionByIDRepo(c.Context(), req.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+	}
+	if txn == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).GetUnsignedWithdrawAdminRequest, "ID doesn't exist."))
+	}
+	if *txn.Status != common.PendingStatus {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).GetUnsignedWithdrawAdminRequest, "This id has already confirmed or cancelled."))
+	}
+	if *txn.TxnType != common.WithdrawStatus {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).GetUnsignedWithdrawAdminRequest, "This id isn't withdraw method."))
+	}
+
+	unsignedTransaction, err := s.BuildUnsignedTransferClientFn(c.Context(), *txn.ChainID, *txn.CollateralType, *txn.Address, *txn.Volume)
+	if err != nil {
+		if errors.Is(err, blockchain.ErrColdWalletDisabled) {
+			return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).GetUnsignedWithdrawAdminRequest, err.Error()))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).GetUnsignedWithdrawAdminBlockErr, err.Error()))
+	}
+	getUnsignedWithdrawAdminResponse := GetUnsignedWithdrawAdminResponse{
+		ID:          req.ID,
+		Transaction: *unsignedTransaction,
+	}
+	return c.Status(fiber.StatusOK).JSON(response.NewResponse(response.ResponseContextLocale(c.Context()).GetUnsignedWithdrawAdminSuccess, &getUnsignedWithdrawAdminResponse))
+}
+
+// ImportSignedWithdrawAdmin
+// @Summary Import Signed Withdraw Admin
+// @Description verify raw transaction signed by cold wallet matches pending withdraw, broadcast it and debit the wallet
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param ImportSignedWithdrawAdmin body lending.ImportSignedWithdrawAdminRequest true "request body to import signed withdraw"
+// @Success 200 {object} response.Response{data=lending.ImportSignedWithdrawAdminResponse} "Success"
+// @Failure 400 {object} response.ErrResponse "Bad Request"
+// @Failure 500 {object} response.ErrResponse "Internal Server Error"
+// @Security AdminAuth
+// @Router /admin/withdraw/signed [post]
+func (s *lendingHandler) ImportSignedWithdrawAdmin(c *handler.Ctx) error {
+	var req ImportSignedWithdrawAdminRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).ImportSignedWithdrawAdminRequest, err.Error()))
+	}
+	if err := req.validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).ImportSignedWithdrawAdminRequest, err.Error()))
+	}
+
+	txn, err := s.LendingRepository.QueryWalletTransactionByIDRepo(c.Context(), req.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+	}
+	if txn == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).ImportSignedWithdrawAdminRequest, "ID doesn't exist."))
+	}
+	if *txn.Status != common.PendingStatus {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).ImportSignedWithdrawAdminRequest, "This id has already confirmed or cancelled."))
+	}
+	if *txn.TxnType != common.WithdrawStatus {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).ImportSignedWithdrawAdminRequest, "This id isn't withdraw method."))
+	}
+
+	claimed, err := s.claimWithdraw(c, req.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+	}
+	if !claimed {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).ImportSignedWithdrawAdminRequest, "This id has already confirmed or cancelled."))
+	}
+	txnHash, err := s.BroadcastSignedTransferClientFn(c.Context(), *txn.ChainID, req.RawTransaction, *txn.CollateralType, *txn.Address, *txn.Volume)
+	if err != nil {
+		s.releaseWithdraw(c, req.ID)
+		if errors.Is(err, blockchain.ErrSignedTxMismatch) || errors.Is(err, blockchain.ErrColdWalletDisabled) {
+			return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).ImportSignedWithdrawAdminRequest, err.Error()))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).ImportSignedWithdrawAdminBlockErr, err.Error()))
+	}
+	c.Log().Info(fmt.Sprintf("TxnID: %d | Broadcast Signed Txn Hash: %s", req.ID, txnHash))
+
+	withdrawRows, err := s.LendingRepository.UpdateWithdrawRepo(c.Context(), req.ID, txnHash, common.BroadcastStatus, time.Now().Format(common.DateYYYYMMDDHHMMSSFormat))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, unrecordedWithdraw(req.ID, txnHash, err.Error())))
+	}
+	if withdrawRows != 1 {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalOperation, unrecordedWithdraw(req.ID, txnHash, fmt.Sprintf("expected to affect 1 row, affected %d", withdrawRows))))
+	}
+	fee, err := s.settleWithdrawFee(c.Context(), req.ID, txnHash, common.ConfirmStatus)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+	}
+	btc, eth, err := addCollateral(c.Context(), s.LendingRepository, *txn.AccountID, *txn.CollateralType, -(*txn.Volume + fee))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalOperation, err.Error()))
+	}
+	c.Log().Info(fmt.Sprintf("TxnID: %d - Status: %s | AccountID: %d - Fee: %f - BTC: %f - ETH: %f", req.ID, common.BroadcastStatus, *txn.AccountID, fee, btc, eth))
+	importSignedWithdrawAdminResponse := ImportSignedWithdrawAdminResponse{
+		TxnHash: txnHash,
+	}
+	return c.Status(fiber.StatusOK).JSON(response.NewResponse(response.ResponseContextLocale(c.Context()).ImportSignedWithdrawAdminSuccess, &importSignedWithdrawAdminResponse))
+}
+
+// GetUnsignedSweepAdmin
+// @Summary Get Unsigned Sweep Admin
+// @Description get unsigned transfer of the whole deposit address balance to the chain's address for offline signing with the key of derivation path
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id query int true "Sweep ID"
+// @Success 200 {object} response.Response{data=lending.GetUnsignedSweepAdminResponse} "Success"
+// @Failure 400 {object} response.ErrResponse "Bad Request"
+// @Failure 500 {object} response.ErrResponse "Internal Server Error"
+// @Router /admin/sweep/unsigned [get]
+func (s *lendingHandler) GetUnsignedSweepAdmin(c *handler.Ctx) error {
+	var req GetUnsignedSweepAdminRequest
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).GetUnsignedSweepAdminRequest, err.Error()))
+	}
+	if err := req.validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).GetUnsignedSweepAdminRequest, err.Error()))
+	}
+
+	sweep, err := s.querySweep(c, req.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+	}
+	if sweep == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).GetUnsignedSweepAdminRequest, "ID doesn't exist."))
+	}
+	if *sweep.Status != common.UnsignedStatus {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).GetUnsignedSweepAdminRequest, fmt.Sprintf("This id is %s, not %s.", *sweep.Status, common.UnsignedStatus)))
+	}
+
+	unsignedTransaction, volume, err := s.BuildUnsignedSweepClientFn(c.Context(), *sweep.ChainID, *sweep.CollateralType, *sweep.Address)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).GetUnsignedSweepAdminBlockErr, err.Error()))
+	}
+	if unsignedTransaction == nil {
+		// balance left the address since the sweep was recorded.
+		if _, err := s.LendingRepository.UpdateSweepStatusRepo(c.Context(), req.ID, common.UnsignedStatus, common.FailedStatus, time.Now().Format(common.DateYYYYMMDDHHMMSSFormat)); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).GetUnsignedSweepAdminRequest, "Nothing is left to sweep, the sweep is failed."))
+	}
+	getUnsignedSweepAdminResponse := GetUnsignedSweepAdminResponse{
+		ID:             req.ID,
+		DerivationPath: blockchain.DerivationPath(uint32(*sweep.AccountID)),
+		Volume:         volume,
+		Transaction:    *unsignedTransaction,
+	}
+	return c.Status(fiber.StatusOK).JSON(response.NewResponse(response.ResponseContextLocale(c.Context()).GetUnsignedSweepAdminSuccess, &getUnsignedSweepAdminResponse))
+}
+
+// ImportSignedSweepAdmin
+// @Summary Import Signed Sweep Admin
+// @Description verify raw transaction signed by deposit address sends its token to the chain's address, broadcast it and link the swept deposits
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param ImportSignedSweepAdmin body lending.ImportSignedSweepAdminRequest true "request body to import signed sweep"
+// @Success 200 {object} response.Response{data=lending.ImportSignedSweepAdminResponse} "Success"
+// @Failure 400 {object} response.ErrResponse "Bad Request"
+// @Failure 500 {object} response.ErrResponse "Internal Server Error"
+// @Security AdminAuth
+// @Router /admin/sweep/signed [post]
+func (s *lendingHandler) ImportSignedSweepAdmin(c *handler.Ctx) error {
+	var req ImportSignedSweepAdminRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).ImportSignedSweepAdminRequest, err.Error()))
+	}
+	if err := req.validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).ImportSignedSweepAdminRequest, err.Error()))
+	}
+
+	sweep, err := s.querySweep(c, req.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+	}
+	if sweep == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).ImportSignedSweepAdminRequest, "ID doesn't exist."))
+	}
+
+	// claim the sweep, so two signed transactions of the same sweep can't both be broadcast and recorded.
+	claimed, err := s.LendingRepository.UpdateSweepStatusRepo(c.Context(), req.ID, common.UnsignedStatus, common.BroadcastingStatus, time.Now().Format(common.DateYYYYMMDDHHMMSSFormat))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+	}
+	if claimed != 1 {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).ImportSignedSweepAdminRequest, fmt.Sprintf("This id is %s, not %s.", *sweep.Status, common.UnsignedStatus)))
+	}
+	txnHash, volume, err := s.BroadcastSignedSweepClientFn(c.Context(), *sweep.ChainID, req.RawTransaction, *sweep.CollateralType, *sweep.Address)
+	if err != nil {
+		if _, releaseErr := s.LendingRepository.UpdateSweepStatusRepo(c.Context(), req.ID, common.BroadcastingStatus, common.UnsignedStatus, time.Now().Format(common.DateYYYYMMDDHHMMSSFormat)); releaseErr != nil {
+			c.Log().Error(fmt.Sprintf("SweepID: %d | cannot release sweep: %s", req.ID, releaseErr.Error()))
+		}
+		if errors.Is(err, blockchain.ErrSignedSweepMismatch) {
+			return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).ImportSignedSweepAdminRequest, err.Error()))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).ImportSignedSweepAdminBlockErr, err.Error()))
+	}
+	c.Log().Info(fmt.Sprintf("SweepID: %d | Broadcast Signed Txn Hash: %s", req.ID, txnHash))
+
+	if _, err := s.LendingRepository.UpdateSweepRepo(c.Context(), req.ID, txnHash, volume, common.BroadcastStatus, time.Now().Format(common.DateYYYYMMDDHHMMSSFormat)); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, fmt.Sprintf("sweep %d is sent in %s but stays %s: %s", req.ID, txnHash, common.BroadcastingStatus, err.Error())))
+	}
+	deposits, err := s.LendingRepository.UpdateDepositSweepRepo(c.Context(), int64(req.ID), *sweep.AccountID, *sweep.Address, *sweep.ChainID, *sweep.CollateralType)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+	}
+	c.Log().Info(fmt.Sprintf("SweepID: %d - Status: %s | ChainID: %d - Address: %s | %s: %f - Deposits: %d | Txn Hash: %s", req.ID, common.BroadcastStatus, *sweep.ChainID, *sweep.Address, *sweep.CollateralType, volume, deposits, txnHash))
+	importSignedSweepAdminResponse := ImportSignedSweepAdminResponse{
+		TxnHash: txnHash,
+		Volume:  volume,
+	}
+	return c.Status(fiber.StatusOK).JSON(response.NewResponse(response.ResponseContextLocale(c.Context()).ImportSignedSweepAdminSuccess, &importSignedSweepAdminResponse))
+}
+
+func (s *lendingHandler) querySweep(c *handler.Ctx, id int) (*Sweep, error) {
+	sweeps, err := s.LendingRepository.QuerySweepRepo(c.Context(), map[string]interface{}{
+		"id": id,
+	})
+	if err != nil || len(*sweeps) == 0 {
+		return nil, err
+	}
+	return &(*sweeps)[0], nil
+}
+
 // GetCreditAvailable
 // @Summary Get Credit Available
 // @Description get user's credit available by accountId
 // @Tags Lending
 // @Accept json
 // @Produce json
+// @Param currency query string false "Quote currency, THB (default) or USD"
 // @Success 200 {object} response.Response{data=lending.GetCreditAvailableResponse} "Success"
 // @Failure 400 {object} response.ErrResponse "Bad Request"
 // @Failure 500 {object} response.ErrResponse "Internal Server Error"
@@ -550,6 +1498,14 @@ func (s *lendingHandler) GetCreditAvailable(c *handler.Ctx) error {
 	id := claims["accountId"].(float64)
 	accountId := int(id)
 
+	var req GetCreditAvailableRequest
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).GetCreditAvailableRequest, err.Error()))
+	}
+	if err := req.validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).GetCreditAvailableRequest, err.Error()))
+	}
+
 	wallet, err := s.LendingRepository.QueryWalletRepo(c.Context(), accountId)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
@@ -558,17 +1514,22 @@ func (s *lendingHandler) GetCreditAvailable(c *handler.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalOperation, "Wallet doesn't exist."))
 	}
 
-	thbbtc, err := s.GetFloatDataRedisFn(common.THBBTCRedis)
+	thbbtc, err := s.GetPriceFn(c.Context(), "BTC")
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalRedis, err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalPrice, err.Error()))
+	}
+	thbeth, err := s.GetPriceFn(c.Context(), "ETH")
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalPrice, err.Error()))
 	}
-	thbeth, err := s.GetFloatDataRedisFn(common.THBETHRedis)
+
+	btcRisk, ethRisk, err := riskParameters(s.GetRiskParametersFn)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalRedis, err.Error()))
 	}
 
-	btcLoan := *wallet.BTCVolume * thbbtc * viper.GetFloat64("loan.haircut.btc")
-	ethLoan := *wallet.ETHVolume * thbeth * viper.GetFloat64("loan.haircut.eth")
+	btcLoan := *wallet.BTCVolume * thbbtc.Price * btcRisk.Haircut
+	ethLoan := *wallet.ETHVolume * thbeth.Price * ethRisk.Haircut
 
 	totalCollateralValue := btcLoan + ethLoan
 
@@ -583,12 +1544,22 @@ func (s *lendingHandler) GetCreditAvailable(c *handler.Ctx) error {
 		}
 	}
 
+	fxRate, err := s.fxRate(c.Context(), req.Currency)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalPrice, err.Error()))
+	}
+	if fxRate != nil {
+		c.Log().Info(fmt.Sprintf("AccountID: %d | %s/%s: %f - Timestamp: %s", accountId, BaseCurrency, req.Currency, fxRate.Price, fxRate.Timestamp.Format(common.DateYYYYMMDDHHMMSSFormat)))
+	}
+
 	getCreditAvailableResponse := GetCreditAvailableResponse{
 		BTCVolume:       *wallet.BTCVolume,
 		ETHVolume:       *wallet.ETHVolume,
-		CollateralValue: totalCollateralValue,
-		LoanOutstanding: totalOutstanding,
-		CreditAvailable: totalCollateralValue - totalOutstanding,
+		CollateralValue: convert(totalCollateralValue, fxRate),
+		LoanOutstanding: convert(totalOutstanding, fxRate),
+		CreditAvailable: convert(totalCollateralValue-totalOutstanding, fxRate),
+		Currency:        req.Currency,
+		FXRate:          fxRate,
 	}
 	return c.Status(fiber.StatusOK).JSON(response.NewResponse(response.ResponseContextLocale(c.Context()).GetCreditAvailableSuccess, &getCreditAvailableResponse))
 }
@@ -628,6 +1599,7 @@ func (s *lendingHandler) GetLoan(c *handler.Ctx) error {
 // @Success 200 {object} response.Response{data=lending.BorrowLoanResponse} "Success"
 // @Failure 400 {object} response.ErrResponse "Bad Request"
 // @Failure 500 {object} response.ErrResponse "Internal Server Error"
+// @Failure 503 {object} response.ErrResponse "Service Unavailable"
 // @Security ApiKeyAuth
 // @Router /borrow [post]
 func (s *lendingHandler) BorrowLoan(c *handler.Ctx) error {
@@ -643,6 +1615,11 @@ func (s *lendingHandler) BorrowLoan(c *handler.Ctx) error {
 	if err := req.validate(); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).BorrowLoanRequest, err.Error()))
 	}
+	for _, asset := range []string{"BTC", "ETH"} {
+		if _, err := s.tradablePrice(c.Context(), asset); err != nil {
+			return priceErrResponse(c, err)
+		}
+	}
 
 	contractId, err := s.LendingRepository.InsertContractRepo(c.Context(), accountId, req.InterestCode, req.Loan, req.Term)
 	if err != nil {
@@ -1006,6 +1983,7 @@ func (s *lendingHandler) RejectRepayAdmin(c *handler.Ctx) error {
 // @Success 200 {object} response.Response "Success"
 // @Failure 400 {object} response.ErrResponse "Bad Request"
 // @Failure 500 {object} response.ErrResponse "Internal Server Error"
+// @Failure 503 {object} response.ErrResponse "Service Unavailable"
 // @Router /admin/liquidation [post]
 func (s *lendingHandler) LiquidateFundAdmin(c *handler.Ctx) error {
 	var req LiquidateFundRequest
@@ -1037,6 +2015,13 @@ func (s *lendingHandler) LiquidateFundAdmin(c *handler.Ctx) error {
 	if *liq.Status != common.OngoingStatus {
 		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).LiquidateFundAdminRequest, "ContractID is inactive."))
 	}
+	for _, asset := range []string{"BTC", "ETH"} {
+		price, err := s.tradablePrice(c.Context(), asset)
+		if err != nil {
+			return priceErrResponse(c, err)
+		}
+		c.Log().Info(fmt.Sprintf("Asset: %s | Price: %f - Timestamp: %s", asset, price.Price, price.Timestamp.Format(common.DateYYYYMMDDHHMMSSFormat)))
+	}
 
 	rows, err := s.LendingRepository.UpdateWalletRepo(c.Context(), req.AccountID, 0.0, 0.0, nil, time.Now().Format(common.DateYYYYMMDDHHMMSSFormat))
 	if err != nil {
@@ -1076,3 +2061,175 @@ func (s *lendingHandler) LiquidateFundAdmin(c *handler.Ctx) error {
 	}
 	return c.Status(fiber.StatusOK).JSON(response.NewResponse(response.ResponseContextLocale(c.Context()).LiquidateFundAdminSuccess, nil))
 }
+
+// StressTestAdmin
+// @Summary Stress Test Admin
+// @Description re-evaluate every wallet and open contract in memory at oracle prices moved by shocks in percent, nothing is written.
+// @Description positions list accounts whose shocked loan would be over their margin call limit (CALLED) or whose shocked LTV would reach "stress.liquidation-ltv" (LIQUIDATABLE), highest shortfall first.
+// @Description currentStatus is the margin call state of the account at the live prices.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param StressTestAdmin body lending.StressTestAdminRequest true "request body to stress test positions"
+// @Success 200 {object} response.Response{data=lending.StressTestAdminResponse} "Success"
+// @Failure 400 {object} response.ErrResponse "Bad Request"
+// @Failure 500 {object} response.ErrResponse "Internal Server Error"
+// @Router /admin/stress-test [post]
+func (s *lendingHandler) StressTestAdmin(c *handler.Ctx) error {
+	var req StressTestAdminRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).StressTestAdminRequest, err.Error()))
+	}
+	if err := req.validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).StressTestAdminRequest, err.Error()))
+	}
+
+	prices := make(map[string]oracle.Price)
+	shocked := make(map[string]oracle.Price)
+	for _, asset := range []string{"BTC", "ETH"} {
+		price, err := s.GetPriceFn(c.Context(), asset)
+		if err != nil {
+			return priceErrResponse(c, err)
+		}
+		prices[asset] = *price
+		shocked[asset] = shockPrice(*price, req.Shocks[asset])
+	}
+	btcRisk, ethRisk, err := riskParameters(s.GetRiskParametersFn)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalRedis, err.Error()))
+	}
+
+	accountWallets, accountContracts, err := openAccounts(c.Context(), s.LendingRepository)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+	}
+
+	stressTestAdminResponse := StressTestAdminResponse{
+		Shocks:    req.Shocks,
+		BTCPrice:  StressPrice{Price: prices["BTC"].Price, ShockedPrice: shocked["BTC"].Price, Timestamp: prices["BTC"].Timestamp},
+		ETHPrice:  StressPrice{Price: prices["ETH"].Price, ShockedPrice: shocked["ETH"].Price, Timestamp: prices["ETH"].Timestamp},
+		Accounts:  len(accountWallets),
+		Positions: make([]StressPosition, 0),
+	}
+	for accountId, wallet := range accountWallets {
+		contracts := accountContracts[accountId]
+		position := newPosition(accountId, wallet, &contracts, shocked, btcRisk, ethRisk)
+		position.MarginCall.Status = stressStatus(position)
+		stressTestAdminResponse.LoanOutstanding += position.LoanOutstanding
+		if position.MarginCall.Status == common.MarginNoneStatus {
+			continue
+		}
+		stressPosition := StressPosition{
+			Position:      position,
+			CurrentStatus: newPosition(accountId, wallet, &contracts, prices, btcRisk, ethRisk).MarginCall.Status,
+			Shortfall:     math.Max(position.LoanOutstanding-position.MarginCallLimit, 0),
+			Uncovered:     math.Max(position.LoanOutstanding-position.CollateralValue, 0),
+		}
+		if position.MarginCall.Status == common.MarginLiquidatableStatus {
+			stressTestAdminResponse.LiquidationAccounts++
+		} else {
+			stressTestAdminResponse.MarginCallAccounts++
+		}
+		stressTestAdminResponse.TotalShortfall += stressPosition.Shortfall
+		stressTestAdminResponse.TotalUncovered += stressPosition.Uncovered
+		stressTestAdminResponse.Positions = append(stressTestAdminResponse.Positions, stressPosition)
+	}
+	sort.Slice(stressTestAdminResponse.Positions, func(i, j int) bool {
+		return stressTestAdminResponse.Positions[i].Shortfall > stressTestAdminResponse.Positions[j].Shortfall
+	})
+	c.Log().Info(fmt.Sprintf("Shocks: %v | Accounts: %d - Margin Call: %d - Liquidation: %d - Shortfall: %f", req.Shocks, stressTestAdminResponse.Accounts, stressTestAdminResponse.MarginCallAccounts, stressTestAdminResponse.LiquidationAccounts, stressTestAdminResponse.TotalShortfall))
+	return c.Status(fiber.StatusOK).JSON(response.NewResponse(response.ResponseContextLocale(c.Context()).StressTestAdminSuccess, &stressTestAdminResponse))
+}
+
+// GetReserves
+// @Summary Get Reserves
+// @Description get latest signed proof-of-reserves report. signature is an EIP-191 personal_sign of report by signer.
+// @Tags Lending
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=lending.GetReservesResponse} "Success"
+// @Failure 400 {object} response.ErrResponse "Bad Request"
+// @Failure 500 {object} response.ErrResponse "Internal Server Error"
+// @Router /reserves [get]
+func (s *lendingHandler) GetReserves(c *handler.Ctx) error {
+	reserveReport, err := s.LendingRepository.QueryLatestReserveReportRepo(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+	}
+	if reserveReport == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).GetReservesRequest, "Reserve report hasn't been published yet."))
+	}
+	var report ReservesReport
+	if err := json.Unmarshal([]byte(*reserveReport.Report), &report); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalOperation, err.Error()))
+	}
+	getReservesResponse := GetReservesResponse{
+		ID:        *reserveReport.ID,
+		Report:    report,
+		Message:   *reserveReport.Report,
+		Signature: *reserveReport.Signature,
+		Signer:    *reserveReport.Signer,
+	}
+	return c.Status(fiber.StatusOK).JSON(response.NewResponse(response.ResponseContextLocale(c.Context()).GetReservesSuccess, &getReservesResponse))
+}
+
+// GetReserveProof
+// @Summary Get Reserve Proof
+// @Description get merkle inclusion proof of user's balances in latest proof-of-reserves report
+// @Tags Lending
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=lending.GetReserveProofResponse} "Success"
+// @Failure 400 {object} response.ErrResponse "Bad Request"
+// @Failure 500 {object} response.ErrResponse "Internal Server Error"
+// @Security ApiKeyAuth
+// @Router /reserves/proof [get]
+func (s *lendingHandler) GetReserveProof(c *handler.Ctx) error {
+	bearer := c.Locals(common.JWTClaimsKey).(*jwt.Token)
+	claims := bearer.Claims.(jwt.MapClaims)
+	id := claims["accountId"].(float64)
+	accountId := int(id)
+
+	reserveReport, err := s.LendingRepository.QueryLatestReserveReportRepo(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+	}
+	if reserveReport == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).GetReserveProofRequest, "Reserve report hasn't been published yet."))
+	}
+	liabilities, err := s.LendingRepository.QueryReserveLiabilitiesRepo(c.Context(), *reserveReport.Root)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+	}
+
+	index := -1
+	leaves := make([][]byte, 0, len(*liabilities))
+	for i, liability := range *liabilities {
+		if *liability.AccountID == accountId {
+			index = i
+		}
+		leaves = append(leaves, reserveLeaf(*liability.AccountID, *liability.BTCVolume, *liability.ETHVolume, *liability.Salt))
+	}
+	if index < 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).GetReserveProofRequest, "This account isn't in latest reserve report."))
+	}
+	tree := merkle.New(leaves)
+	if root := hexutil.Encode(tree.Root()); root != *reserveReport.Root {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalOperation, fmt.Sprintf("rebuilt root %s doesn't match report root %s", root, *reserveReport.Root)))
+	}
+
+	liability := (*liabilities)[index]
+	getReserveProofResponse := GetReserveProofResponse{
+		ReportID:  *reserveReport.ID,
+		Root:      *reserveReport.Root,
+		AccountID: accountId,
+		BTCVolume: *liability.BTCVolume,
+		ETHVolume: *liability.ETHVolume,
+		Salt:      *liability.Salt,
+		LeafData:  ReserveLeafData(accountId, *liability.BTCVolume, *liability.ETHVolume, *liability.Salt),
+		Leaf:      hexutil.Encode(leaves[index]),
+		LeafIndex: index,
+		Proof:     tree.Proof(index),
+	}
+	return c.Status(fiber.StatusOK).JSON(response.NewResponse(response.ResponseContextLocale(c.Context()).GetReserveProofSuccess, &getReserveProofResponse))
+}
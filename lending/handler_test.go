@@ -0,0 +1,268 @@
+package lending
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"lending-engine/blockchain"
+	"lending-engine/blockchain/chaintest"
+	"lending-engine/common"
+	"lending-engine/internal/handler"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// newTestApp routes deposit, withdraw, sweep and reserve proof endpoints of a handler backed by repository and chain, with the caller
+// logged in as accountId.
+func newTestApp(repository LendingRepository, chain *testChain, accountId int) *fiber.App {
+	lendingHandler := NewLendingHandler(
+		repository,
+		blockchain.NewGetChainFn(chain.registry),
+		blockchain.NewQueryTransactionClientFn(chain.registry),
+		blockchain.NewQueryReceiptClientFn(chain.registry),
+		blockchain.NewTransferTokenClientFn(chain.registry, blockchain.NewExecutor(chain.hotWallet)),
+		blockchain.NewSpeedUpTransactionClientFn(chain.registry, blockchain.NewExecutor(chain.hotWallet)),
+		blockchain.NewQueryGasCostClientFn(chain.registry),
+		blockchain.NewBuildUnsignedTransferClientFn(chain.registry),
+		blockchain.NewBroadcastSignedTransferClientFn(chain.registry),
+		blockchain.NewBuildUnsignedSweepClientFn(chain.registry),
+		blockchain.NewBroadcastSignedSweepClientFn(chain.registry),
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	logger := zap.NewNop()
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Context().SetUserValue(common.LocaleKey, "en")
+		c.Locals(common.JWTClaimsKey, &jwt.Token{Claims: jwt.MapClaims{"accountId": float64(accountId)}})
+		return c.Next()
+	})
+	app.Post("/deposit", handler.Helper(lendingHandler.SubmitDeposit, logger))
+	app.Post("/admin/deposit/confirm", handler.Helper(lendingHandler.ConfirmDepositAdmin, logger))
+	app.Post("/admin/withdraw/confirm", handler.Helper(lendingHandler.ConfirmWithdrawAdmin, logger))
+	app.Get("/admin/sweep/unsigned", handler.Helper(lendingHandler.GetUnsignedSweepAdmin, logger))
+	app.Post("/admin/sweep/signed", handler.Helper(lendingHandler.ImportSignedSweepAdmin, logger))
+	app.Get("/reserves/proof", handler.Helper(lendingHandler.GetReserveProof, logger))
+	return app
+}
+
+// post sends body as json and decodes the data of a successful response into data.
+func post(t *testing.T, app *fiber.App, path string, body interface{}, data interface{}) {
+	t.Helper()
+	resp := send(t, app, path, body)
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("POST %s = %d: %s", path, resp.StatusCode, respBody)
+	}
+	if data == nil {
+		return
+	}
+	if err := json.Unmarshal(respBody, &struct {
+		Data interface{} `json:"data"`
+	}{Data: data}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// get decodes the data of a successful response of path into data.
+func get(t *testing.T, app *fiber.App, path string, data interface{}) {
+	t.Helper()
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s = %d: %s", path, resp.StatusCode, respBody)
+	}
+	if err := json.Unmarshal(respBody, &struct {
+		Data interface{} `json:"data"`
+	}{Data: data}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// postStatus sends body as json and returns the status code.
+func postStatus(t *testing.T, app *fiber.App, path string, body interface{}) int {
+	t.Helper()
+	return send(t, app, path, body).StatusCode
+}
+
+func send(t *testing.T, app *fiber.App, path string, body interface{}) *http.Response {
+	t.Helper()
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestSubmitDepositEndToEnd(t *testing.T) {
+	viper.Set("toggle.query-txn", true)
+	defer viper.Set("toggle.query-txn", nil)
+
+	chain := newTestChain(t)
+	repository := newMemRepository()
+	repository.addWallet(1, 0, 0)
+	app := newTestApp(repository, chain, 1)
+
+	tx := chain.deposit(t, chain.chain.Address, 2)
+	var submitted SubmitDepositResponse
+	post(t, app, "/deposit", SubmitDepositRequest{
+		Address:        chain.depositor.From.Hex(),
+		ChainID:        testChainId,
+		TxnHash:        tx.Hash().Hex(),
+		CollateralType: "BTC",
+		Volume:         2,
+	}, &submitted)
+
+	deposit := repository.transaction(t, int(submitted.DepositID))
+	if *deposit.Status != common.ConfirmStatus {
+		t.Fatalf("deposit status = %s, want %s", *deposit.Status, common.ConfirmStatus)
+	}
+	block, blockHash := chain.blockOf(t, tx)
+	if deposit.BlockNumber == nil || *deposit.BlockNumber != block || *deposit.BlockHash != blockHash {
+		t.Errorf("deposit block isn't recorded as %d (%s)", block, blockHash)
+	}
+	if stringValue(deposit.ToAddress) != chain.chain.Address {
+		t.Errorf("deposit destination = %s, want %s", stringValue(deposit.ToAddress), chain.chain.Address)
+	}
+	if got := repository.wallet(t, 1); *got.BTCVolume != 2 {
+		t.Errorf("wallet btc = %f, want 2", *got.BTCVolume)
+	}
+}
+
+func TestSubmitDepositLeavesMismatchPending(t *testing.T) {
+	viper.Set("toggle.query-txn", true)
+	defer viper.Set("toggle.query-txn", nil)
+
+	chain := newTestChain(t)
+	repository := newMemRepository()
+	repository.addWallet(1, 0, 0)
+	app := newTestApp(repository, chain, 1)
+
+	tx := chain.deposit(t, chain.chain.Address, 2)
+	var submitted SubmitDepositResponse
+	post(t, app, "/deposit", SubmitDepositRequest{
+		Address:        chain.depositor.From.Hex(),
+		ChainID:        testChainId,
+		TxnHash:        tx.Hash().Hex(),
+		CollateralType: "BTC",
+		Volume:         3,
+	}, &submitted)
+
+	if deposit := repository.transaction(t, int(submitted.DepositID)); *deposit.Status != common.PendingStatus {
+		t.Errorf("deposit status = %s, want %s", *deposit.Status, common.PendingStatus)
+	}
+	if got := repository.wallet(t, 1); *got.BTCVolume != 0 {
+		t.Errorf("wallet btc = %f, want 0", *got.BTCVolume)
+	}
+}
+
+func TestConfirmDepositAdminRecordsBlock(t *testing.T) {
+	viper.Set("toggle.query-txn", true)
+	defer viper.Set("toggle.query-txn", nil)
+
+	chain := newTestChain(t)
+	repository := newMemRepository()
+	repository.addWallet(1, 0, 0)
+	app := newTestApp(repository, chain, 1)
+
+	tx := chain.deposit(t, chain.chain.Address, 2)
+	id := repository.addTransaction(WalletTransaction{
+		AccountID:      intPtr(1),
+		Address:        stringPtr(chain.depositor.From.Hex()),
+		ChainID:        intPtr(testChainId),
+		TxnHash:        stringPtr(tx.Hash().Hex()),
+		CollateralType: stringPtr("BTC"),
+		Volume:         float64Ptr(2),
+		TxnType:        stringPtr(common.DepositStatus),
+		Status:         stringPtr(common.PendingStatus),
+	})
+	post(t, app, "/admin/deposit/confirm", ConfirmDepositAdminRequest{ID: id}, nil)
+
+	deposit := repository.transaction(t, id)
+	if *deposit.Status != common.ConfirmStatus {
+		t.Fatalf("deposit status = %s, want %s", *deposit.Status, common.ConfirmStatus)
+	}
+	block, blockHash := chain.blockOf(t, tx)
+	if deposit.BlockNumber == nil || *deposit.BlockNumber != block || *deposit.BlockHash != blockHash {
+		t.Errorf("deposit block isn't recorded as %d (%s)", block, blockHash)
+	}
+	if got := repository.wallet(t, 1); *got.BTCVolume != 2 {
+		t.Errorf("wallet btc = %f, want 2", *got.BTCVolume)
+	}
+}
+
+func TestWithdrawEndToEnd(t *testing.T) {
+	chain := newTestChain(t)
+	repository := newMemRepository()
+	repository.addWallet(1, 3, 0)
+	app := newTestApp(repository, chain, 1)
+	chain.deposit(t, chain.hotWallet.Address.Hex(), 10)
+
+	recipient := "0x00000000000000000000000000000000000000B1"
+	id := repository.addTransaction(WalletTransaction{
+		AccountID:      intPtr(1),
+		Address:        stringPtr(recipient),
+		ChainID:        intPtr(testChainId),
+		CollateralType: stringPtr("BTC"),
+		Volume:         float64Ptr(1),
+		TxnType:        stringPtr(common.WithdrawStatus),
+		Status:         stringPtr(common.PendingStatus),
+	})
+
+	var confirmed ConfirmWithdrawAdminResponse
+	post(t, app, "/admin/withdraw/confirm", ConfirmWithdrawAdminRequest{ID: id}, &confirmed)
+
+	withdraw := repository.transaction(t, id)
+	if *withdraw.Status != common.BroadcastStatus || *withdraw.TxnHash != confirmed.TxnHash {
+		t.Fatalf("withdraw = %s %s, want %s %s", *withdraw.Status, stringValue(withdraw.TxnHash), common.BroadcastStatus, confirmed.TxnHash)
+	}
+	if got := repository.wallet(t, 1); *got.BTCVolume != 2 {
+		t.Errorf("wallet btc = %f, want 2", *got.BTCVolume)
+	}
+
+	// a second confirmation of the same withdrawal doesn't send it again.
+	if status := postStatus(t, app, "/admin/withdraw/confirm", ConfirmWithdrawAdminRequest{ID: id}); status != http.StatusBadRequest {
+		t.Errorf("second confirmation = %d, want %d", status, http.StatusBadRequest)
+	}
+
+	tracker := NewWithdrawTracker(repository, blockchain.NewQueryReceiptClientFn(chain.registry), (&alerts{}).alertOpsFn(), zap.NewNop())
+	if err := tracker.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if withdraw := repository.transaction(t, id); *withdraw.Status != common.MinedStatus {
+		t.Errorf("withdraw status = %s, want %s", *withdraw.Status, common.MinedStatus)
+	}
+	if got := chain.balanceOf(t, recipient); got.Cmp(chaintest.Wei(1)) != 0 {
+		t.Errorf("recipient balance = %s, want %s", got, chaintest.Wei(1))
+	}
+}
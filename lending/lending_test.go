@@ -0,0 +1,754 @@
+package lending
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"lending-engine/blockchain"
+	"lending-engine/blockchain/chaintest"
+	"lending-engine/common"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// memRepository keeps wallet transactions, wallets and contracts in memory. Methods a test doesn't use fall through to
+// the nil LendingRepository and panic, so a test can't silently depend on one.
+type memRepository struct {
+	LendingRepository
+	mu               sync.Mutex
+	transactions     map[int]*WalletTransaction
+	wallets          map[int]*Wallet
+	contracts        map[int]*Contract
+	depositAddresses map[int]*DepositAddress
+	sweeps           map[int]*Sweep
+	scans            map[int]int64
+	reserveReports   []ReserveReport
+	reserveLiability map[string][]ReserveLiability
+	nextId           int
+	// walletErr fails wallet writes made in a database transaction.
+	walletErr error
+}
+
+func newMemRepository() *memRepository {
+	return &memRepository{
+		transactions:     make(map[int]*WalletTransaction),
+		wallets:          make(map[int]*Wallet),
+		contracts:        make(map[int]*Contract),
+		depositAddresses: make(map[int]*DepositAddress),
+		sweeps:           make(map[int]*Sweep),
+		scans:            make(map[int]int64),
+		reserveLiability: make(map[string][]ReserveLiability),
+	}
+}
+
+func (r *memRepository) addWallet(accountId int, btc float64, eth float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.wallets[accountId] = &Wallet{
+		AccountID: intPtr(accountId),
+		BTCVolume: float64Ptr(btc),
+		ETHVolume: float64Ptr(eth),
+	}
+}
+
+func (r *memRepository) addContract(accountId int, loan float64, status string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextId++
+	r.contracts[r.nextId] = &Contract{
+		ContractID:      intPtr(r.nextId),
+		AccountID:       intPtr(accountId),
+		LoanOutstanding: float64Ptr(loan),
+		Status:          stringPtr(status),
+	}
+}
+
+func (r *memRepository) addTransaction(txn WalletTransaction) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextId++
+	txn.ID = intPtr(r.nextId)
+	createdDatetime := time.Now()
+	txn.CreatedDatetime = &createdDatetime
+	r.transactions[r.nextId] = &txn
+	return r.nextId
+}
+
+func (r *memRepository) transaction(t *testing.T, id int) WalletTransaction {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	txn, ok := r.transactions[id]
+	if !ok {
+		t.Fatalf("transaction %d doesn't exist", id)
+	}
+	return *txn
+}
+
+func (r *memRepository) wallet(t *testing.T, accountId int) Wallet {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	wallet, ok := r.wallets[accountId]
+	if !ok {
+		t.Fatalf("wallet of account %d doesn't exist", accountId)
+	}
+	return *wallet
+}
+
+func (r *memRepository) addDepositAddress(accountId int, address string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.depositAddresses[accountId] = &DepositAddress{
+		AccountID: intPtr(accountId),
+		Address:   stringPtr(address),
+	}
+}
+
+func (r *memRepository) sweep(t *testing.T, id int) Sweep {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sweep, ok := r.sweeps[id]
+	if !ok {
+		t.Fatalf("sweep %d doesn't exist", id)
+	}
+	return *sweep
+}
+
+func (r *memRepository) QueryWalletTransactionByIDRepo(ctx context.Context, id int) (*WalletTransaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	txn, ok := r.transactions[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *txn
+	return &copied, nil
+}
+
+func (r *memRepository) QueryWalletTransactionRepo(ctx context.Context, request map[string]interface{}) (*[]WalletTransaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	walletTransactions := make([]WalletTransaction, 0)
+	for _, id := range r.sortedIds() {
+		txn := r.transactions[id]
+		matched := true
+		for key, value := range request {
+			var field interface{}
+			switch key {
+			case "account_id":
+				field = float64(*txn.AccountID)
+				value = toFloat64(value)
+			case "txn_hash":
+				field = stringValue(txn.TxnHash)
+			case "txn_type":
+				field = stringValue(txn.TxnType)
+			case "status":
+				field = stringValue(txn.Status)
+			case "chain_id":
+				field = float64(*txn.ChainID)
+				value = toFloat64(value)
+			case "parent_id":
+				if txn.ParentID == nil {
+					matched = false
+					continue
+				}
+				field = float64(*txn.ParentID)
+				value = toFloat64(value)
+			default:
+				return nil, fmt.Errorf("memRepository can't filter by %s", key)
+			}
+			if field != value {
+				matched = false
+			}
+		}
+		if matched {
+			walletTransactions = append(walletTransactions, *txn)
+		}
+	}
+	return &walletTransactions, nil
+}
+
+func (r *memRepository) InsertDepositRepo(ctx context.Context, accountId int, address string, toAddress string, chainId int, txnHash string, collateralType string, volume float64, txnType string, status string) (int64, error) {
+	id := r.addTransaction(WalletTransaction{
+		AccountID:      intPtr(accountId),
+		Address:        stringPtr(address),
+		ToAddress:      nullString(toAddress),
+		ChainID:        intPtr(chainId),
+		TxnHash:        stringPtr(txnHash),
+		CollateralType: stringPtr(collateralType),
+		Volume:         float64Ptr(volume),
+		TxnType:        stringPtr(txnType),
+		Status:         stringPtr(status),
+	})
+	return int64(id), nil
+}
+
+func (r *memRepository) CreditDepositRepo(ctx context.Context, accountId int, address string, toAddress string, chainId int, txnHash string, collateralType string, volume float64, blockNumber int64, blockHash string, btc float64, eth float64, timestamp string) (int64, error) {
+	r.mu.Lock()
+	if r.walletErr != nil {
+		r.mu.Unlock()
+		return 0, r.walletErr
+	}
+	wallet, ok := r.wallets[accountId]
+	if !ok {
+		r.mu.Unlock()
+		return 0, fmt.Errorf("expected to update 1 wallet, affected 0")
+	}
+	wallet.BTCVolume = float64Ptr(btc)
+	wallet.ETHVolume = float64Ptr(eth)
+	r.mu.Unlock()
+	id := r.addTransaction(WalletTransaction{
+		AccountID:      intPtr(accountId),
+		Address:        stringPtr(address),
+		ToAddress:      nullString(toAddress),
+		ChainID:        intPtr(chainId),
+		TxnHash:        stringPtr(txnHash),
+		CollateralType: stringPtr(collateralType),
+		Volume:         float64Ptr(volume),
+		TxnType:        stringPtr(common.DepositStatus),
+		Status:         stringPtr(common.ConfirmStatus),
+		BlockNumber:    int64Ptr(blockNumber),
+		BlockHash:      stringPtr(blockHash),
+	})
+	return int64(id), nil
+}
+
+func (r *memRepository) QueryDepositScanRepo(ctx context.Context, chainId int) (*int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	block, ok := r.scans[chainId]
+	if !ok {
+		return nil, nil
+	}
+	return &block, nil
+}
+
+func (r *memRepository) UpsertDepositScanRepo(ctx context.Context, chainId int, block int64, timestamp string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.scans[chainId] = block
+	return 1, nil
+}
+
+func (r *memRepository) UpdateDepositRepo(ctx context.Context, id int, status string, timestamp string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	txn, ok := r.transactions[id]
+	if !ok {
+		return 0, nil
+	}
+	txn.Status = stringPtr(status)
+	return 1, nil
+}
+
+func (r *memRepository) UpdateDepositBlockRepo(ctx context.Context, id int64, blockNumber int64, blockHash string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	txn, ok := r.transactions[int(id)]
+	if !ok {
+		return 0, nil
+	}
+	txn.BlockNumber = int64Ptr(blockNumber)
+	txn.BlockHash = stringPtr(blockHash)
+	return 1, nil
+}
+
+func (r *memRepository) QueryBlockTransactionRepo(ctx context.Context, chainId int, txnType string, status string, fromBlock int64) (*[]WalletTransaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	walletTransactions := make([]WalletTransaction, 0)
+	for _, id := range r.sortedIds() {
+		txn := r.transactions[id]
+		if *txn.ChainID == chainId && *txn.TxnType == txnType && *txn.Status == status && txn.BlockHash != nil && *txn.BlockNumber >= fromBlock {
+			walletTransactions = append(walletTransactions, *txn)
+		}
+	}
+	return &walletTransactions, nil
+}
+
+func (r *memRepository) InsertWithdrawRepo(ctx context.Context, accountId int, address string, chainId int, collateralType string, volume float64, txnType string, status string) (int64, error) {
+	id := r.addTransaction(WalletTransaction{
+		AccountID:      intPtr(accountId),
+		Address:        stringPtr(address),
+		ChainID:        intPtr(chainId),
+		CollateralType: stringPtr(collateralType),
+		Volume:         float64Ptr(volume),
+		TxnType:        stringPtr(txnType),
+		Status:         stringPtr(status),
+	})
+	return int64(id), nil
+}
+
+func (r *memRepository) InsertWithdrawFeeRepo(ctx context.Context, parentId int64, accountId int, address string, chainId int, collateralType string, volume float64, status string) (int64, error) {
+	id := r.addTransaction(WalletTransaction{
+		AccountID:      intPtr(accountId),
+		Address:        stringPtr(address),
+		ChainID:        intPtr(chainId),
+		CollateralType: stringPtr(collateralType),
+		Volume:         float64Ptr(volume),
+		TxnType:        stringPtr(common.WithdrawFeeType),
+		Status:         stringPtr(status),
+		ParentID:       intPtr(int(parentId)),
+	})
+	return int64(id), nil
+}
+
+func (r *memRepository) UpdateWithdrawRepo(ctx context.Context, id int, txnHash string, status string, timestamp string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	txn, ok := r.transactions[id]
+	if !ok {
+		return 0, nil
+	}
+	txn.TxnHash = stringPtr(txnHash)
+	txn.Status = stringPtr(status)
+	return 1, nil
+}
+
+func (r *memRepository) UpdateWithdrawStatusRepo(ctx context.Context, id int, from string, to string, timestamp string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	txn, ok := r.transactions[id]
+	if !ok || *txn.Status != from {
+		return 0, nil
+	}
+	txn.Status = stringPtr(to)
+	return 1, nil
+}
+
+func (r *memRepository) FailWithdrawRepo(ctx context.Context, id int, accountId int, btc float64, eth float64, timestamp string) (int64, error) {
+	return r.updateStatusAndWallet(id, common.BroadcastStatus, common.FailedStatus, accountId, btc, eth)
+}
+
+func (r *memRepository) OrphanDepositRepo(ctx context.Context, id int, accountId int, btc float64, eth float64, timestamp string) (int64, error) {
+	return r.updateStatusAndWallet(id, common.ConfirmStatus, common.OrphanedStatus, accountId, btc, eth)
+}
+
+// updateStatusAndWallet writes nothing when the wallet can't be updated, like the rolled back database transaction.
+func (r *memRepository) updateStatusAndWallet(id int, from string, to string, accountId int, btc float64, eth float64) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	txn, ok := r.transactions[id]
+	if !ok || *txn.Status != from {
+		return 0, nil
+	}
+	if r.walletErr != nil {
+		return 0, r.walletErr
+	}
+	wallet, ok := r.wallets[accountId]
+	if !ok {
+		return 0, fmt.Errorf("expected to update 1 wallet, affected 0")
+	}
+	txn.Status = stringPtr(to)
+	wallet.BTCVolume = float64Ptr(btc)
+	wallet.ETHVolume = float64Ptr(eth)
+	return 1, nil
+}
+
+func (r *memRepository) QueryWalletRepo(ctx context.Context, accountId int) (*Wallet, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	wallet, ok := r.wallets[accountId]
+	if !ok {
+		return nil, nil
+	}
+	copied := *wallet
+	return &copied, nil
+}
+
+func (r *memRepository) QueryWalletsRepo(ctx context.Context) (*[]Wallet, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	accountIds := make([]int, 0, len(r.wallets))
+	for accountId := range r.wallets {
+		accountIds = append(accountIds, accountId)
+	}
+	sort.Ints(accountIds)
+	wallets := make([]Wallet, 0, len(accountIds))
+	for _, accountId := range accountIds {
+		wallets = append(wallets, *r.wallets[accountId])
+	}
+	return &wallets, nil
+}
+
+func (r *memRepository) UpdateWalletRepo(ctx context.Context, accountId int, btc float64, eth float64, margin *string, latest string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	wallet, ok := r.wallets[accountId]
+	if !ok {
+		return 0, nil
+	}
+	wallet.BTCVolume = float64Ptr(btc)
+	wallet.ETHVolume = float64Ptr(eth)
+	wallet.MarginCallDate = margin
+	return 1, nil
+}
+
+func (r *memRepository) UpdateMarginCallRepo(ctx context.Context, accountId int, margin *string, latest string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	wallet, ok := r.wallets[accountId]
+	if !ok || (margin != nil && wallet.MarginCallDate != nil) {
+		return 0, nil
+	}
+	wallet.MarginCallDate = margin
+	return 1, nil
+}
+
+func (r *memRepository) QueryContractRepo(ctx context.Context, request map[string]interface{}) (*[]Contract, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	contracts := make([]Contract, 0)
+	for _, contract := range r.contracts {
+		if accountId, ok := request["account_id"]; ok && float64(*contract.AccountID) != toFloat64(accountId) {
+			continue
+		}
+		if status, ok := request["status"]; ok && *contract.Status != status {
+			continue
+		}
+		contracts = append(contracts, *contract)
+	}
+	sort.Slice(contracts, func(i, j int) bool { return *contracts[i].ContractID < *contracts[j].ContractID })
+	return &contracts, nil
+}
+
+func (r *memRepository) QueryDepositAddressRepo(ctx context.Context, accountId int) (*DepositAddress, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	depositAddress, ok := r.depositAddresses[accountId]
+	if !ok {
+		return nil, nil
+	}
+	copied := *depositAddress
+	return &copied, nil
+}
+
+func (r *memRepository) QueryDepositAddressesRepo(ctx context.Context) (*[]DepositAddress, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	accountIds := make([]int, 0, len(r.depositAddresses))
+	for accountId := range r.depositAddresses {
+		accountIds = append(accountIds, accountId)
+	}
+	sort.Ints(accountIds)
+	depositAddresses := make([]DepositAddress, 0, len(accountIds))
+	for _, accountId := range accountIds {
+		depositAddresses = append(depositAddresses, *r.depositAddresses[accountId])
+	}
+	return &depositAddresses, nil
+}
+
+func (r *memRepository) QuerySweepRepo(ctx context.Context, request map[string]interface{}) (*[]Sweep, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := make([]int, 0, len(r.sweeps))
+	for id := range r.sweeps {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	sweeps := make([]Sweep, 0)
+	for _, id := range ids {
+		sweep := r.sweeps[id]
+		matched := true
+		for key, value := range request {
+			var field interface{}
+			switch key {
+			case "id":
+				field = float64(*sweep.ID)
+				value = toFloat64(value)
+			case "chain_id":
+				field = float64(*sweep.ChainID)
+				value = toFloat64(value)
+			case "address":
+				field = stringValue(sweep.Address)
+			case "collateral_type":
+				field = stringValue(sweep.CollateralType)
+			case "status":
+				field = stringValue(sweep.Status)
+			default:
+				return nil, fmt.Errorf("memRepository can't filter sweeps by %s", key)
+			}
+			if field != value {
+				matched = false
+			}
+		}
+		if matched {
+			sweeps = append(sweeps, *sweep)
+		}
+	}
+	return &sweeps, nil
+}
+
+func (r *memRepository) InsertSweepRepo(ctx context.Context, chainId int, accountId int, address string, collateralType string, volume float64, txnHash string, gasTxnHash string, status string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := len(r.sweeps) + 1
+	r.sweeps[id] = &Sweep{
+		ID:             intPtr(id),
+		ChainID:        intPtr(chainId),
+		AccountID:      intPtr(accountId),
+		Address:        stringPtr(address),
+		CollateralType: stringPtr(collateralType),
+		Volume:         float64Ptr(volume),
+		TxnHash:        nullString(txnHash),
+		GasTxnHash:     nullString(gasTxnHash),
+		Status:         stringPtr(status),
+	}
+	return int64(id), nil
+}
+
+func (r *memRepository) UpdateSweepRepo(ctx context.Context, id int, txnHash string, volume float64, status string, timestamp string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sweep, ok := r.sweeps[id]
+	if !ok {
+		return 0, nil
+	}
+	sweep.TxnHash = nullString(txnHash)
+	sweep.Volume = float64Ptr(volume)
+	sweep.Status = stringPtr(status)
+	return 1, nil
+}
+
+func (r *memRepository) UpdateSweepStatusRepo(ctx context.Context, id int, from string, to string, timestamp string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sweep, ok := r.sweeps[id]
+	if !ok || *sweep.Status != from {
+		return 0, nil
+	}
+	sweep.Status = stringPtr(to)
+	return 1, nil
+}
+
+func (r *memRepository) UpdateDepositSweepRepo(ctx context.Context, sweepId int64, accountId int, toAddress string, chainId int, collateralType string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var rows int64
+	for _, txn := range r.transactions {
+		if *txn.AccountID == accountId && stringValue(txn.ToAddress) == toAddress && *txn.ChainID == chainId && *txn.CollateralType == collateralType &&
+			*txn.TxnType == common.DepositStatus && *txn.Status == common.ConfirmStatus && txn.SweepID == nil {
+			txn.SweepID = intPtr(int(sweepId))
+			rows++
+		}
+	}
+	return rows, nil
+}
+
+func (r *memRepository) InsertReserveReportRepo(ctx context.Context, root string, report string, signature string, signer string, liabilities []ReserveLiability) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := len(r.reserveReports) + 1
+	createdDatetime := time.Now()
+	r.reserveReports = append(r.reserveReports, ReserveReport{
+		ID:              intPtr(id),
+		Root:            stringPtr(root),
+		Report:          stringPtr(report),
+		Signature:       stringPtr(signature),
+		Signer:          stringPtr(signer),
+		CreatedDatetime: &createdDatetime,
+	})
+	r.reserveLiability[root] = append([]ReserveLiability{}, liabilities...)
+	return int64(id), nil
+}
+
+func (r *memRepository) QueryLatestReserveReportRepo(ctx context.Context) (*ReserveReport, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.reserveReports) == 0 {
+		return nil, nil
+	}
+	report := r.reserveReports[len(r.reserveReports)-1]
+	return &report, nil
+}
+
+func (r *memRepository) QueryReserveLiabilitiesRepo(ctx context.Context, root string) (*[]ReserveLiability, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	liabilities := append([]ReserveLiability{}, r.reserveLiability[root]...)
+	sort.Slice(liabilities, func(i, j int) bool { return *liabilities[i].LeafIndex < *liabilities[j].LeafIndex })
+	return &liabilities, nil
+}
+
+func (r *memRepository) sortedIds() []int {
+	ids := make([]int, 0, len(r.transactions))
+	for id := range r.transactions {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func toFloat64(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	}
+	return value
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func int64Ptr(value int64) *int64 {
+	return &value
+}
+
+func float64Ptr(value float64) *float64 {
+	return &value
+}
+
+func stringPtr(value string) *string {
+	return &value
+}
+
+// nullString is nil for empty value, the way the repository stores empty hashes as NULL.
+func nullString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+const testChainId = 1337
+
+// testChain is an in-process chain registered as chain 1337 with the test token as BTC. The depositor holds tokens
+// and ether, the hot wallet holds ether only.
+type testChain struct {
+	*blockchain.SimulatedChain
+	registry  *blockchain.ChainRegistry
+	chain     *blockchain.Chain
+	depositor *bind.TransactOpts
+	hotWallet *keystore.Key
+	token     *bind.BoundContract
+	tokenAddr ethcommon.Address
+}
+
+func newTestChain(t *testing.T) *testChain {
+	t.Helper()
+	depositorKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hotWalletKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hotWallet := &keystore.Key{
+		Address:    crypto.PubkeyToAddress(hotWalletKey.PublicKey),
+		PrivateKey: hotWalletKey,
+	}
+	depositor, err := bind.NewKeyedTransactorWithChainID(depositorKey, big.NewInt(testChainId))
+	if err != nil {
+		t.Fatal(err)
+	}
+	simulated := blockchain.NewSimulatedChain(backends.NewSimulatedBackend(core.GenesisAlloc{
+		depositor.From:    {Balance: chaintest.Wei(1000)},
+		hotWallet.Address: {Balance: chaintest.Wei(1000)},
+	}, 30000000))
+	t.Cleanup(simulated.Close)
+
+	tokenAddr, token, err := chaintest.DeployToken(depositor, simulated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := token.Transact(depositor, "mint", depositor.From, chaintest.Wei(100)); err != nil {
+		t.Fatal(err)
+	}
+
+	chain := &blockchain.Chain{
+		ChainID:       testChainId,
+		Name:          "simulated",
+		Confirmations: 1,
+		Address:       ethcommon.HexToAddress("0x00000000000000000000000000000000000000c0").Hex(),
+		Native:        "ETH",
+		TransferGas:   65000,
+		Tokens: map[string]blockchain.Token{
+			"BTC": {Address: tokenAddr, Decimals: chaintest.TokenDecimals},
+		},
+		Client: simulated,
+	}
+	return &testChain{
+		SimulatedChain: simulated,
+		registry:       blockchain.NewChainRegistryFromChains(chain),
+		chain:          chain,
+		depositor:      depositor,
+		hotWallet:      hotWallet,
+		token:          token,
+		tokenAddr:      tokenAddr,
+	}
+}
+
+// deposit transfers volume tokens from the depositor to address and returns the mined transaction.
+func (c *testChain) deposit(t *testing.T, address string, volume int64) *types.Transaction {
+	t.Helper()
+	tx, err := c.token.Transact(c.depositor, "transfer", ethcommon.HexToAddress(address), chaintest.Wei(volume))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func (c *testChain) balanceOf(t *testing.T, address string) *big.Int {
+	t.Helper()
+	var out []interface{}
+	if err := c.token.Call(nil, &out, "balanceOf", ethcommon.HexToAddress(address)); err != nil {
+		t.Fatal(err)
+	}
+	return out[0].(*big.Int)
+}
+
+// blockOf returns number and hash of the block tx is mined in.
+func (c *testChain) blockOf(t *testing.T, tx *types.Transaction) (int64, string) {
+	t.Helper()
+	receipt, err := c.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return receipt.BlockNumber.Int64(), receipt.BlockHash.Hex()
+}
+
+// orphan forks the chain off the parent of tx's block and mines past the old head, so the block of tx is orphaned.
+func (c *testChain) orphan(t *testing.T, tx *types.Transaction) {
+	t.Helper()
+	ctx := context.Background()
+	receipt, err := c.TransactionReceipt(ctx, tx.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, err := c.HeaderByNumber(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := c.BlockByNumber(ctx, receipt.BlockNumber)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Fork(ctx, block.ParentHash()); err != nil {
+		t.Fatal(err)
+	}
+	c.Mine(int(head.Number.Int64()-receipt.BlockNumber.Int64()) + 2)
+}
@@ -0,0 +1,125 @@
+package lending
+
+import (
+	"context"
+	"fmt"
+	"lending-engine/common"
+	"lending-engine/mail"
+	"lending-engine/oracle"
+	"lending-engine/risk"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// marginCaller sets and clears margin_call_date from the live margin call LTVs: an account whose loan outstanding is over
+// its margin call limit at oracle prices is margin called today, and a called account back within its limit is cleared.
+// The date it sets is what LiquidateFundAdmin counts "loan.liquidate-limit" days from.
+type marginCaller struct {
+	LendingRepository   LendingRepository
+	GetPriceFn          oracle.GetPriceFn
+	GetRiskParametersFn risk.GetParametersFn
+	AlertOpsFn          mail.AlertOpsFn
+	Logger              *zap.Logger
+	mu                  sync.Mutex
+}
+
+func NewMarginCaller(lendingRepository LendingRepository, getPriceFn oracle.GetPriceFn, getRiskParametersFn risk.GetParametersFn, alertOpsFn mail.AlertOpsFn, logger *zap.Logger) *marginCaller {
+	return &marginCaller{
+		LendingRepository:   lendingRepository,
+		GetPriceFn:          getPriceFn,
+		GetRiskParametersFn: getRiskParametersFn,
+		AlertOpsFn:          alertOpsFn,
+		Logger:              logger,
+	}
+}
+
+// Run re-evaluates every account with a wallet or an open contract once. It's meant to be scheduled by job.Start.
+func (m *marginCaller) Run(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	prices := make(map[string]oracle.Price)
+	for _, asset := range []string{"BTC", "ETH"} {
+		price, err := m.GetPriceFn(ctx, asset)
+		if err != nil {
+			return err
+		}
+		prices[asset] = *price
+	}
+	btcRisk, ethRisk, err := riskParameters(m.GetRiskParametersFn)
+	if err != nil {
+		return err
+	}
+	wallets, contracts, err := openAccounts(ctx, m.LendingRepository)
+	if err != nil {
+		return err
+	}
+
+	today := time.Now().Format(common.DateYYYYMMDDFormat)
+	called := make([]string, 0)
+	for accountId, wallet := range wallets {
+		accountContracts := contracts[accountId]
+		position := newPosition(accountId, wallet, &accountContracts, prices, btcRisk, ethRisk)
+		overLimit := position.LoanOutstanding > position.MarginCallLimit
+		var margin *string
+		switch {
+		case wallet.MarginCallDate == nil && overLimit:
+			margin = &today
+		case wallet.MarginCallDate != nil && !overLimit:
+			// back within the limit, the date is cleared.
+		default:
+			continue
+		}
+		rows, err := m.LendingRepository.UpdateMarginCallRepo(ctx, accountId, margin, time.Now().Format(common.DateYYYYMMDDHHMMSSFormat))
+		if err != nil {
+			m.Logger.Error(fmt.Sprintf("AccountID: %d | %s", accountId, err.Error()))
+			continue
+		}
+		if rows != 1 {
+			continue
+		}
+		m.Logger.Info(fmt.Sprintf("AccountID: %d - Margin Call: %t | Loan: %f - Margin Call Limit: %f - LTV: %.4f", accountId, margin != nil, position.LoanOutstanding, position.MarginCallLimit, position.LTV))
+		if margin != nil {
+			called = append(called, fmt.Sprintf("account %d (loan %.2f over limit %.2f)", accountId, position.LoanOutstanding, position.MarginCallLimit))
+		}
+	}
+	if len(called) > 0 {
+		message := fmt.Sprintf("Margin called at BTC %.2f and ETH %.2f with margin call LTVs %.4f and %.4f: %s.", prices["BTC"].Price, prices["ETH"].Price, btcRisk.MarginCallLTV, ethRisk.MarginCallLTV, strings.Join(called, ", "))
+		if err := m.AlertOpsFn(m.Logger, "Margin call", message); err != nil {
+			m.Logger.Error(err.Error())
+		}
+	}
+	return nil
+}
+
+// openAccounts returns the wallet and the open contracts of every account that has a wallet or an open contract.
+// An account with contracts but no wallet gets an empty one.
+func openAccounts(ctx context.Context, lendingRepository LendingRepository) (map[int]*Wallet, map[int][]Contract, error) {
+	wallets, err := lendingRepository.QueryWalletsRepo(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	contracts, err := lendingRepository.QueryContractRepo(ctx, map[string]interface{}{})
+	if err != nil {
+		return nil, nil, err
+	}
+	accountWallets := make(map[int]*Wallet)
+	for i := range *wallets {
+		accountWallets[*(*wallets)[i].AccountID] = &(*wallets)[i]
+	}
+	accountContracts := make(map[int][]Contract)
+	for _, contract := range *contracts {
+		if *contract.Status == common.ClosedStatus {
+			continue
+		}
+		accountContracts[*contract.AccountID] = append(accountContracts[*contract.AccountID], contract)
+		if _, ok := accountWallets[*contract.AccountID]; !ok {
+			zero := 0.0
+			accountWallets[*contract.AccountID] = &Wallet{AccountID: contract.AccountID, BTCVolume: &zero, ETHVolume: &zero}
+		}
+	}
+	return accountWallets, accountContracts, nil
+}
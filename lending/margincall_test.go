@@ -0,0 +1,74 @@
+package lending
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lending-engine/common"
+	"lending-engine/risk"
+
+	"go.uber.org/zap"
+)
+
+func TestMarginCallerFollowsMarginCallLTV(t *testing.T) {
+	repository := newMemRepository()
+	// 1 BTC at 1,000,000 with a 400,000 loan is within a 0.5 margin call LTV but over 0.3.
+	repository.addWallet(1, 1, 0)
+	repository.addContract(1, 400000, common.OngoingStatus)
+	// called before and repaid down to 100,000.
+	repository.addWallet(2, 1, 0)
+	repository.addContract(2, 100000, common.OngoingStatus)
+	// called before and still over the limit.
+	repository.addWallet(3, 1, 0)
+	repository.addContract(3, 900000, common.OngoingStatus)
+	// over the limit only through a closed contract.
+	repository.addWallet(4, 0, 0)
+	repository.addContract(4, 900000, common.ClosedStatus)
+	calledBefore := "2021-01-02"
+	for _, accountId := range []int{2, 3} {
+		if _, err := repository.UpdateMarginCallRepo(context.Background(), accountId, &calledBefore, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	marginCallLTV := 0.5
+	getRiskParametersFn := func(asset string) (*risk.Parameters, error) {
+		return &risk.Parameters{Asset: asset, Haircut: 0.5, MarginCallLTV: marginCallLTV}, nil
+	}
+	var alerted alerts
+	marginCaller := NewMarginCaller(repository, oracleOf(map[string]float64{"BTC": 1000000, "ETH": 50000}), getRiskParametersFn, alerted.alertOpsFn(), zap.NewNop())
+	ctx := context.Background()
+
+	if err := marginCaller.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if wallet := repository.wallet(t, 1); wallet.MarginCallDate != nil {
+		t.Errorf("account 1 margin called on %s within its limit", *wallet.MarginCallDate)
+	}
+	if wallet := repository.wallet(t, 2); wallet.MarginCallDate != nil {
+		t.Errorf("account 2 still margin called on %s back within its limit", *wallet.MarginCallDate)
+	}
+	if wallet := repository.wallet(t, 3); wallet.MarginCallDate == nil || *wallet.MarginCallDate != calledBefore {
+		t.Errorf("account 3 margin call date = %v, want %s kept", stringValue(wallet.MarginCallDate), calledBefore)
+	}
+	if wallet := repository.wallet(t, 4); wallet.MarginCallDate != nil {
+		t.Errorf("account 4 margin called on %s for a closed contract", *wallet.MarginCallDate)
+	}
+	if len(alerted) != 0 {
+		t.Errorf("alerts = %v, want none", alerted)
+	}
+
+	// an approved margin call LTV of 0.3 puts account 1 over its limit on the next run.
+	marginCallLTV = 0.3
+	if err := marginCaller.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	today := time.Now().Format(common.DateYYYYMMDDFormat)
+	if wallet := repository.wallet(t, 1); wallet.MarginCallDate == nil || *wallet.MarginCallDate != today {
+		t.Errorf("account 1 margin call date = %v, want %s", stringValue(wallet.MarginCallDate), today)
+	}
+	if len(alerted) != 1 {
+		t.Errorf("alerts = %v, want one", alerted)
+	}
+}
@@ -2,7 +2,12 @@ package lending
 
 import (
 	"fmt"
+	"lending-engine/blockchain"
+	"lending-engine/internal/merkle"
+	"lending-engine/oracle"
 	"lending-engine/response"
+	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/pkg/errors"
@@ -40,6 +45,41 @@ type SubmitDepositResponse struct {
 	DepositID int64 `json:"depositId" example:"1"`
 }
 
+// withdraw address
+// Signature is optional EIP-191 personal_sign of WithdrawAddressMessage by the address.
+type AddWithdrawAddressRequest struct {
+	Address   string `json:"address" example:"0xC26880A0AF2EA0c7E8130e6EC47Af756465452E8"`
+	Label     string `json:"label" example:"My Ledger"`
+	Signature string `json:"signature" example:"0x5f6c1f1b9e..."`
+}
+
+func (req *AddWithdrawAddressRequest) validate() error {
+	if utf8.RuneCountInString(req.Address) == 0 {
+		return errors.Wrapf(errors.New(fmt.Sprintf("'address' must be REQUIRED field but the input is '%v'.", req.Address)), response.ValidateFieldError)
+	}
+	if !blockchain.IsChecksumAddress(req.Address) {
+		return errors.Wrapf(errors.New(fmt.Sprintf("'address' must be EIP-55 checksum address but the input is '%v'.", req.Address)), response.ValidateFieldError)
+	}
+	return nil
+}
+
+type AddWithdrawAddressResponse struct {
+	ID             int64  `json:"id" example:"1"`
+	Verified       bool   `json:"verified" example:"true"`
+	ActiveDatetime string `json:"activeDatetime" example:"2021-01-03 12:13:14"`
+}
+
+// WithdrawAddressMessage is the message user signs to prove ownership of address.
+func WithdrawAddressMessage(accountId int, address string) string {
+	return fmt.Sprintf("I own %s and allow ICFIN account %d to withdraw to it.", address, accountId)
+}
+
+// deposit address
+type GetDepositAddressResponse struct {
+	Address        string `json:"address" example:"0x9858EfFD232B4033E47d90003D41EC34EcaEda94"`
+	DerivationPath string `json:"derivationPath" example:"m/44'/60'/0'/0/1"`
+}
+
 // withdraw
 type SubmitWithdrawRequest struct {
 	Address        string  `json:"address" example:"0xc083EB69aa7215f4AFa7a22dcbfCC1a33999371C"`
@@ -52,6 +92,9 @@ func (req *SubmitWithdrawRequest) validate() error {
 	if utf8.RuneCountInString(req.Address) == 0 {
 		return errors.Wrapf(errors.New(fmt.Sprintf("'address' must be REQUIRED field but the input is '%v'.", req.Address)), response.ValidateFieldError)
 	}
+	if !blockchain.IsValidAddress(req.Address) {
+		return errors.Wrapf(errors.New(fmt.Sprintf("'address' must be hex address but the input is '%v'.", req.Address)), response.ValidateFieldError)
+	}
 	if req.ChainID == 0 {
 		return errors.Wrapf(errors.New(fmt.Sprintf("'chainId' must be REQUIRED field but the input is '%v'.", req.ChainID)), response.ValidateFieldError)
 	}
@@ -65,7 +108,36 @@ func (req *SubmitWithdrawRequest) validate() error {
 }
 
 type SubmitWithdrawResponse struct {
-	WithdrawID int64 `json:"withdrawId" example:"1"`
+	WithdrawID    int64   `json:"withdrawId" example:"1"`
+	Fee           float64 `json:"fee" example:"0.0002"`
+	ReceiveVolume float64 `json:"receiveVolume" example:"0.4998"`
+}
+
+// withdraw fee
+type GetWithdrawFeeRequest struct {
+	ChainID        int     `json:"chainId" example:"56"`
+	CollateralType string  `json:"collateralType" example:"BTC"`
+	Volume         float64 `json:"volume" example:"0.5"`
+}
+
+func (req *GetWithdrawFeeRequest) validate() error {
+	if req.ChainID == 0 {
+		return errors.Wrapf(errors.New(fmt.Sprintf("'chainId' must be REQUIRED field but the input is '%v'.", req.ChainID)), response.ValidateFieldError)
+	}
+	if utf8.RuneCountInString(req.CollateralType) == 0 {
+		return errors.Wrapf(errors.New(fmt.Sprintf("'collateralType' must be REQUIRED field but the input is '%v'.", req.CollateralType)), response.ValidateFieldError)
+	}
+	if req.Volume <= 0 {
+		return errors.Wrapf(errors.New(fmt.Sprintf("'volume' must be more than 0 but the input is '%v'.", req.Volume)), response.ValidateFieldError)
+	}
+	return nil
+}
+
+type GetWithdrawFeeResponse struct {
+	WithdrawFee
+	CollateralType string  `json:"collateralType" example:"BTC"`
+	Volume         float64 `json:"volume" example:"0.5"`
+	ReceiveVolume  float64 `json:"receiveVolume" example:"0.4998"`
 }
 
 // wallet transaction
@@ -101,6 +173,7 @@ func (req *RejectDepositAdminRequest) validate() error {
 }
 
 // confirm withdraw admin
+// TxnHash is optional. Hot wallet will send the transfer when it's empty.
 type ConfirmWithdrawAdminRequest struct {
 	ID      int    `json:"id" example:"1"`
 	TxnHash string `json:"txnHash" example:"0xC26880A0AF2EA0c7E8130e6EC47Af756465452E8"`
@@ -110,12 +183,87 @@ func (req *ConfirmWithdrawAdminRequest) validate() error {
 	if req.ID == 0 {
 		return errors.Wrapf(errors.New(fmt.Sprintf("'id' must be REQUIRED field but the input is '%v'.", req.ID)), response.ValidateFieldError)
 	}
-	if utf8.RuneCountInString(req.TxnHash) == 0 {
-		return errors.Wrapf(errors.New(fmt.Sprintf("'txnHash' must be REQUIRED field but the input is '%v'.", req.TxnHash)), response.ValidateFieldError)
+	return nil
+}
+
+type ConfirmWithdrawAdminResponse struct {
+	TxnHash string `json:"txnHash" example:"0xf5a3aa87c40b05e6a308b61186eeded8996b654a9895401b8089a2966b54f618"`
+}
+
+// cold wallet withdraw admin
+type GetUnsignedWithdrawAdminRequest struct {
+	ID int `json:"id" example:"1"`
+}
+
+func (req *GetUnsignedWithdrawAdminRequest) validate() error {
+	if req.ID == 0 {
+		return errors.Wrapf(errors.New(fmt.Sprintf("'id' must be REQUIRED field but the input is '%v'.", req.ID)), response.ValidateFieldError)
+	}
+	return nil
+}
+
+type GetUnsignedWithdrawAdminResponse struct {
+	ID          int                            `json:"id" example:"1"`
+	Transaction blockchain.UnsignedTransaction `json:"transaction"`
+}
+
+type ImportSignedWithdrawAdminRequest struct {
+	ID             int    `json:"id" example:"1"`
+	RawTransaction string `json:"rawTransaction" example:"0x02f8b1388085012a05f200..."`
+}
+
+func (req *ImportSignedWithdrawAdminRequest) validate() error {
+	if req.ID == 0 {
+		return errors.Wrapf(errors.New(fmt.Sprintf("'id' must be REQUIRED field but the input is '%v'.", req.ID)), response.ValidateFieldError)
+	}
+	if utf8.RuneCountInString(req.RawTransaction) == 0 {
+		return errors.Wrapf(errors.New(fmt.Sprintf("'rawTransaction' must be REQUIRED field but the input is '%v'.", req.RawTransaction)), response.ValidateFieldError)
 	}
 	return nil
 }
 
+type ImportSignedWithdrawAdminResponse struct {
+	TxnHash string `json:"txnHash" example:"0xf5a3aa87c40b05e6a308b61186eeded8996b654a9895401b8089a2966b54f618"`
+}
+
+type GetUnsignedSweepAdminRequest struct {
+	ID int `json:"id" example:"1"`
+}
+
+func (req *GetUnsignedSweepAdminRequest) validate() error {
+	if req.ID == 0 {
+		return errors.Wrapf(errors.New(fmt.Sprintf("'id' must be REQUIRED field but the input is '%v'.", req.ID)), response.ValidateFieldError)
+	}
+	return nil
+}
+
+type GetUnsignedSweepAdminResponse struct {
+	ID             int                            `json:"id" example:"1"`
+	DerivationPath string                         `json:"derivationPath" example:"m/44'/60'/0'/0/12"`
+	Volume         float64                        `json:"volume" example:"0.5"`
+	Transaction    blockchain.UnsignedTransaction `json:"transaction"`
+}
+
+type ImportSignedSweepAdminRequest struct {
+	ID             int    `json:"id" example:"1"`
+	RawTransaction string `json:"rawTransaction" example:"0x02f8b1388085012a05f200..."`
+}
+
+func (req *ImportSignedSweepAdminRequest) validate() error {
+	if req.ID == 0 {
+		return errors.Wrapf(errors.New(fmt.Sprintf("'id' must be REQUIRED field but the input is '%v'.", req.ID)), response.ValidateFieldError)
+	}
+	if utf8.RuneCountInString(req.RawTransaction) == 0 {
+		return errors.Wrapf(errors.New(fmt.Sprintf("'rawTransaction' must be REQUIRED field but the input is '%v'.", req.RawTransaction)), response.ValidateFieldError)
+	}
+	return nil
+}
+
+type ImportSignedSweepAdminResponse struct {
+	TxnHash string  `json:"txnHash" example:"0xf5a3aa87c40b05e6a308b61186eeded8996b654a9895401b8089a2966b54f618"`
+	Volume  float64 `json:"volume" example:"0.5"`
+}
+
 // reject withdraw admin
 type RejectWithdrawAdminRequest struct {
 	ID int `json:"id" example:"1"`
@@ -128,13 +276,108 @@ func (req *RejectWithdrawAdminRequest) validate() error {
 	return nil
 }
 
+// speed up withdraw admin
+type SpeedUpWithdrawAdminRequest struct {
+	ID int `json:"id" example:"1"`
+}
+
+func (req *SpeedUpWithdrawAdminRequest) validate() error {
+	if req.ID == 0 {
+		return errors.Wrapf(errors.New(fmt.Sprintf("'id' must be REQUIRED field but the input is '%v'.", req.ID)), response.ValidateFieldError)
+	}
+	return nil
+}
+
+type SpeedUpWithdrawAdminResponse struct {
+	TxnHash string `json:"txnHash" example:"0xf5a3aa87c40b05e6a308b61186eeded8996b654a9895401b8089a2966b54f618"`
+}
+
+// batch withdraw admin
+const (
+	SkippedBatchStatus string = "SKIPPED"
+	ErrorBatchStatus   string = "ERROR"
+)
+
+type BatchWithdrawAdminRequest struct {
+	IDs []int `json:"ids" example:"1,2,3"`
+}
+
+func (req *BatchWithdrawAdminRequest) validate() error {
+	if len(req.IDs) == 0 {
+		return errors.Wrapf(errors.New(fmt.Sprintf("'ids' must be REQUIRED field but the input is '%v'.", req.IDs)), response.ValidateFieldError)
+	}
+	seen := make(map[int]bool)
+	for _, id := range req.IDs {
+		if seen[id] {
+			return errors.Wrapf(errors.New(fmt.Sprintf("'ids' has duplicate id '%d'.", id)), response.ValidateFieldError)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// BatchWithdrawResult is the outcome of one withdrawal: BROADCAST in the batch transaction, SKIPPED with reason and left PENDING,
+// or ERROR when it was sent but couldn't be recorded.
+type BatchWithdrawResult struct {
+	ID     int     `json:"id" example:"1"`
+	Status string  `json:"status" example:"BROADCAST"`
+	Volume float64 `json:"volume" example:"0.5"`
+	Fee    float64 `json:"fee" example:"0.0002"`
+	Reason string  `json:"reason,omitempty" example:"Volume is above hot wallet limit."`
+}
+
+type BatchWithdrawAdminResponse struct {
+	ChainID        int                   `json:"chainId" example:"1"`
+	CollateralType string                `json:"collateralType" example:"BTC"`
+	TxnHash        string                `json:"txnHash" example:"0xf5a3aa87c40b05e6a308b61186eeded8996b654a9895401b8089a2966b54f618"`
+	Withdrawals    []BatchWithdrawResult `json:"withdrawals"`
+}
+
 // credit
+type GetCreditAvailableRequest struct {
+	Currency string `json:"currency" example:"USD"`
+}
+
+func (req *GetCreditAvailableRequest) validate() error {
+	return validateCurrency(&req.Currency)
+}
+
 type GetCreditAvailableResponse struct {
-	BTCVolume       float64 `json:"btcVolume" example:"0.1"`
-	ETHVolume       float64 `json:"ethVolume" example:"0.1"`
-	CollateralValue float64 `json:"collateralValue" example:"10000"`
-	LoanOutstanding float64 `json:"loanOutstanding" example:"0"`
-	CreditAvailable float64 `json:"creditAvailable" example:"10000"`
+	BTCVolume       float64       `json:"btcVolume" example:"0.1"`
+	ETHVolume       float64       `json:"ethVolume" example:"0.1"`
+	CollateralValue float64       `json:"collateralValue" example:"10000"`
+	LoanOutstanding float64       `json:"loanOutstanding" example:"0"`
+	CreditAvailable float64       `json:"creditAvailable" example:"10000"`
+	Currency        string        `json:"currency" example:"USD"`
+	FXRate          *oracle.Price `json:"fxRate,omitempty"`
+}
+
+// Position is an account's live collateral and loan in THB, sent by GET /stream. LTV is the loan outstanding over the market
+// value of the collateral, CreditLimit the collateral value after the haircut of each asset and MarginCallLimit after its
+// margin call LTV.
+type Position struct {
+	AccountID       int             `json:"accountId" example:"1"`
+	BTCVolume       float64         `json:"btcVolume" example:"0.1"`
+	ETHVolume       float64         `json:"ethVolume" example:"0.1"`
+	BTCPrice        float64         `json:"btcPrice" example:"1042475.25"`
+	ETHPrice        float64         `json:"ethPrice" example:"65321.5"`
+	CollateralValue float64         `json:"collateralValue" example:"110779.68"`
+	CreditLimit     float64         `json:"creditLimit" example:"55389.84"`
+	MarginCallLimit float64         `json:"marginCallLimit" example:"55389.84"`
+	LoanOutstanding float64         `json:"loanOutstanding" example:"20000"`
+	CreditAvailable float64         `json:"creditAvailable" example:"35389.84"`
+	LTV             float64         `json:"ltv" example:"0.1805"`
+	MarginCall      MarginCallState `json:"marginCall"`
+	Timestamp       time.Time       `json:"timestamp" example:"2021-01-02T12:13:14+07:00"`
+}
+
+// MarginCallState is AT_RISK when the loan outstanding is over the margin call limit, CALLED once a margin call date is set
+// and LIQUIDATABLE after more than "loan.liquidate-limit" days of margin call.
+type MarginCallState struct {
+	Status         string  `json:"status" example:"NONE"`
+	Date           *string `json:"date,omitempty" example:"2021-01-02"`
+	Days           int     `json:"days" example:"0"`
+	LiquidateLimit int     `json:"liquidateLimit" example:"3"`
 }
 
 // Borrow
@@ -267,34 +510,50 @@ func (req *RejectRepayAdminRequest) validate() error {
 }
 
 // Price
+type GetTokenPriceRequest struct {
+	Currency string `json:"currency" example:"USD"`
+}
+
+func (req *GetTokenPriceRequest) validate() error {
+	return validateCurrency(&req.Currency)
+}
+
 type GetTokenPriceResponse struct {
-	BTC          TokenPrice `json:"btc"`
-	ETH          TokenPrice `json:"eth"`
-	InterestRate float64    `json:"interestRate" example:"0.05"`
+	BTC          TokenPrice    `json:"btc"`
+	ETH          TokenPrice    `json:"eth"`
+	InterestRate float64       `json:"interestRate" example:"0.05"`
+	Currency     string        `json:"currency" example:"USD"`
+	FXRate       *oracle.Price `json:"fxRate,omitempty"`
 }
 
 type TokenPrice struct {
-	Price   float64 `json:"price" example:"1042475.25"`
-	Haircut float64 `json:"haircut" example:"0.5"`
+	Price     float64        `json:"price" example:"1042475.25"`
+	Haircut   float64        `json:"haircut" example:"0.5"`
+	Timestamp time.Time      `json:"timestamp" example:"2021-01-02T12:13:14+07:00"`
+	Stale     bool           `json:"stale" example:"false"`
+	Sources   []oracle.Quote `json:"sources"`
 }
 
 type PreCalculationLoanRequest struct {
 	BTCAmount float64 `json:"btcAmount" example:"0.5"`
 	ETHAmount float64 `json:"ethAmount" example:"0.5"`
 	Period    int     `json:"period" example:"12"`
+	Currency  string  `json:"currency" example:"USD"`
 }
 
 func (req *PreCalculationLoanRequest) validate() error {
 	if req.Period == 0 {
 		return errors.Wrapf(errors.New(fmt.Sprintf("'period' must be REQUIRED field but the input is '%v'.", req.Period)), response.ValidateFieldError)
 	}
-	return nil
+	return validateCurrency(&req.Currency)
 }
 
 type PreCalculationLoanResponse struct {
-	BTC     TokenPriceRate `json:"btc"`
-	ETH     TokenPriceRate `json:"eth"`
-	Summary SummaryLoan    `json:"summary"`
+	BTC      TokenPriceRate `json:"btc"`
+	ETH      TokenPriceRate `json:"eth"`
+	Summary  SummaryLoan    `json:"summary"`
+	Currency string         `json:"currency" example:"USD"`
+	FXRate   *oracle.Price  `json:"fxRate,omitempty"`
 }
 
 type TokenPriceRate struct {
@@ -327,6 +586,58 @@ func (req *LiquidateFundRequest) validate() error {
 	return nil
 }
 
+// stress test
+type StressTestAdminRequest struct {
+	Shocks map[string]float64 `json:"shocks" swaggertype:"object,number" example:"BTC:-30,ETH:-20"`
+}
+
+func (req *StressTestAdminRequest) validate() error {
+	if len(req.Shocks) == 0 {
+		return errors.Wrapf(errors.New(fmt.Sprintf("'shocks' must be REQUIRED field but the input is '%v'.", req.Shocks)), response.ValidateFieldError)
+	}
+	shocks := make(map[string]float64, len(req.Shocks))
+	for asset, shock := range req.Shocks {
+		asset = strings.ToUpper(asset)
+		if asset != "BTC" && asset != "ETH" {
+			return errors.Wrapf(errors.New(fmt.Sprintf("'shocks' must be keyed by BTC or ETH but the input is '%v'.", asset)), response.ValidateFieldError)
+		}
+		if shock < -100 {
+			return errors.Wrapf(errors.New(fmt.Sprintf("'shocks.%s' must be at least -100 but the input is '%v'.", asset, shock)), response.ValidateFieldError)
+		}
+		shocks[asset] = shock
+	}
+	req.Shocks = shocks
+	return nil
+}
+
+type StressTestAdminResponse struct {
+	Shocks              map[string]float64 `json:"shocks" swaggertype:"object,number" example:"BTC:-30,ETH:-20"`
+	BTCPrice            StressPrice        `json:"btcPrice"`
+	ETHPrice            StressPrice        `json:"ethPrice"`
+	Accounts            int                `json:"accounts" example:"120"`
+	MarginCallAccounts  int                `json:"marginCallAccounts" example:"7"`
+	LiquidationAccounts int                `json:"liquidationAccounts" example:"1"`
+	LoanOutstanding     float64            `json:"loanOutstanding" example:"2500000"`
+	TotalShortfall      float64            `json:"totalShortfall" example:"184220.5"`
+	TotalUncovered      float64            `json:"totalUncovered" example:"12034.75"`
+	Positions           []StressPosition   `json:"positions"`
+}
+
+type StressPrice struct {
+	Price        float64   `json:"price" example:"1042475.25"`
+	ShockedPrice float64   `json:"shockedPrice" example:"729732.68"`
+	Timestamp    time.Time `json:"timestamp" example:"2021-01-02T12:13:14+07:00"`
+}
+
+// StressPosition is the position of an account at the shocked prices. Shortfall is the repayment that would bring the loan
+// back to the margin call limit, Uncovered the part of the loan the collateral would no longer cover.
+type StressPosition struct {
+	Position
+	CurrentStatus string  `json:"currentStatus" example:"NONE"`
+	Shortfall     float64 `json:"shortfall" example:"4610.16"`
+	Uncovered     float64 `json:"uncovered" example:"0"`
+}
+
 type SendLiquidationClientRequest struct {
 	From     string                    `json:"from" example:"k.apiwattanawong@gmail.com"`
 	To       []string                  `json:"to" example:"[yoisak4@gmail.com]"`
@@ -348,3 +659,25 @@ type SendLiquidationClientResult struct {
 	Title       string `json:"title" example:"Success."`
 	Description string `json:"description" example:"Please contact administrator for more information."`
 }
+
+// reserves
+type GetReservesResponse struct {
+	ID        int            `json:"id" example:"1"`
+	Report    ReservesReport `json:"report"`
+	Message   string         `json:"message" example:"{\"root\":\"0x...\"}"`
+	Signature string         `json:"signature" example:"0x..."`
+	Signer    string         `json:"signer" example:"0xa9B6D99bA92D7d691c6EF4f49A1DC909822Cee46"`
+}
+
+type GetReserveProofResponse struct {
+	ReportID  int                `json:"reportId" example:"1"`
+	Root      string             `json:"root" example:"0x3ad2c1b9a5ed6e1e0a0e6a1b0c6a4cfd1c4e7b8e0c5a9b7d2c8e1f0a6b4d3c2e"`
+	AccountID int                `json:"accountId" example:"1"`
+	BTCVolume float64            `json:"btcVolume" example:"0.1"`
+	ETHVolume float64            `json:"ethVolume" example:"0.1"`
+	Salt      string             `json:"salt" example:"9f86d081884c7d659a2feaa0c55ad015"`
+	LeafData  string             `json:"leafData" example:"1|0.10000000|0.10000000|9f86d081884c7d659a2feaa0c55ad015"`
+	Leaf      string             `json:"leaf" example:"0x5c7e0b3e4d2f8a1b9c6d7e8f0a1b2c3d4e5f60718293a4b5c6d7e8f9a0b1c2d3"`
+	LeafIndex int                `json:"leafIndex" example:"0"`
+	Proof     []merkle.ProofStep `json:"proof"`
+}
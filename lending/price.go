@@ -0,0 +1,88 @@
+package lending
+
+import (
+	"context"
+	"fmt"
+	"lending-engine/internal/handler"
+	"lending-engine/oracle"
+	"lending-engine/response"
+	"lending-engine/risk"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+// BaseCurrency is the currency of prices and loans, other currencies are converted by the fx oracle.
+const BaseCurrency = "THB"
+
+// validateCurrency upper-cases currency, defaults it to THB and checks it is in "fx.currencies".
+func validateCurrency(currency *string) error {
+	*currency = strings.ToUpper(*currency)
+	if *currency == "" {
+		*currency = BaseCurrency
+	}
+	for _, supported := range viper.GetStringSlice("fx.currencies") {
+		if strings.EqualFold(supported, *currency) {
+			return nil
+		}
+	}
+	return errors.Wrapf(errors.New(fmt.Sprintf("'currency' must be one of %s but the input is '%v'.", strings.Join(viper.GetStringSlice("fx.currencies"), ", "), *currency)), response.ValidateFieldError)
+}
+
+// fxRate returns the THB price of one unit of currency, nil for THB.
+func (s *lendingHandler) fxRate(ctx context.Context, currency string) (*oracle.Price, error) {
+	if currency == BaseCurrency {
+		return nil, nil
+	}
+	return s.GetFXRateFn(ctx, currency)
+}
+
+// convert turns a THB value into the currency of rate.
+func convert(value float64, rate *oracle.Price) float64 {
+	if rate == nil {
+		return value
+	}
+	return value / rate.Price
+}
+
+// tradablePrice returns the oracle price of asset, oracle.ErrCircuitOpen while its circuit breaker is open
+// and oracle.ErrStalePrice when it is older than "oracle.max-age".
+func (s *lendingHandler) tradablePrice(ctx context.Context, asset string) (*oracle.Price, error) {
+	if err := s.CheckBreakerFn(ctx, asset); err != nil {
+		return nil, err
+	}
+	price, err := s.GetPriceFn(ctx, asset)
+	if err != nil {
+		return nil, err
+	}
+	if err := price.Fresh(); err != nil {
+		return nil, err
+	}
+	return price, nil
+}
+
+// priceErrResponse answers 503 for an open circuit breaker or a stale price and 500 when the oracle has no price.
+func priceErrResponse(c *handler.Ctx, err error) error {
+	if errors.Is(err, oracle.ErrCircuitOpen) {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).PriceHalted, err.Error()))
+	}
+	if errors.Is(err, oracle.ErrStalePrice) {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).StalePrice, err.Error()))
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalPrice, err.Error()))
+}
+
+// riskParameters returns the live haircut and margin call LTV of BTC and ETH.
+func riskParameters(getRiskParametersFn risk.GetParametersFn) (*risk.Parameters, *risk.Parameters, error) {
+	btc, err := getRiskParametersFn("BTC")
+	if err != nil {
+		return nil, nil, err
+	}
+	eth, err := getRiskParametersFn("ETH")
+	if err != nil {
+		return nil, nil, err
+	}
+	return btc, eth, nil
+}
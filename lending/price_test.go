@@ -0,0 +1,146 @@
+package lending
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"lending-engine/common"
+	"lending-engine/internal/handler"
+	"lending-engine/risk"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+func TestFXRate(t *testing.T) {
+	lendingHandler := &lendingHandler{
+		GetFXRateFn: oracleOf(map[string]float64{"USD": 32}),
+	}
+	ctx := context.Background()
+
+	rate, err := lendingHandler.fxRate(ctx, BaseCurrency)
+	if err != nil || rate != nil {
+		t.Fatalf("rate of %s = %+v, %v, want none", BaseCurrency, rate, err)
+	}
+	if got := convert(64000, rate); got != 64000 {
+		t.Errorf("convert to %s = %f, want 64000", BaseCurrency, got)
+	}
+
+	rate, err = lendingHandler.fxRate(ctx, "USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := convert(64000, rate); got != 2000 {
+		t.Errorf("convert to USD = %f, want 2000", got)
+	}
+
+	if rate, err = lendingHandler.fxRate(ctx, "EUR"); err == nil {
+		t.Errorf("rate of EUR = %+v without a quote, want error", rate)
+	}
+}
+
+// newPriceTestApp routes GET /credit and POST /price/calculation of accountId priced at 1,000,000 THB/BTC and 50,000 THB/ETH
+// with a haircut of 0.5, converted by fxRates.
+func newPriceTestApp(repository LendingRepository, fxRates map[string]float64, accountId int) *fiber.App {
+	lendingHandler := &lendingHandler{
+		LendingRepository: repository,
+		GetPriceFn:        oracleOf(map[string]float64{"BTC": 1000000, "ETH": 50000}),
+		GetFXRateFn:       oracleOf(fxRates),
+		GetRiskParametersFn: func(asset string) (*risk.Parameters, error) {
+			return &risk.Parameters{Asset: asset, Haircut: 0.5, MarginCallLTV: 0.6}, nil
+		},
+	}
+	logger := zap.NewNop()
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Context().SetUserValue(common.LocaleKey, "en")
+		c.Locals(common.JWTClaimsKey, &jwt.Token{Claims: jwt.MapClaims{"accountId": float64(accountId)}})
+		return c.Next()
+	})
+	app.Get("/credit", handler.Helper(lendingHandler.GetCreditAvailable, logger))
+	app.Post("/price/calculation", handler.Helper(lendingHandler.PreCalculationLoan, logger))
+	return app
+}
+
+func TestGetCreditAvailableConvertsCurrency(t *testing.T) {
+	viper.Set("fx.currencies", []string{"THB", "USD", "EUR"})
+	defer viper.Set("fx.currencies", nil)
+
+	repository := newMemRepository()
+	repository.addWallet(1, 1, 2)
+	repository.addContract(1, 200000, common.OngoingStatus)
+	// only USD has a quote.
+	app := newPriceTestApp(repository, map[string]float64{"USD": 32}, 1)
+
+	tests := []struct {
+		currency   string
+		collateral float64
+		loan       float64
+		credit     float64
+		rate       float64
+	}{
+		{currency: "", collateral: 550000, loan: 200000, credit: 350000},
+		{currency: "thb", collateral: 550000, loan: 200000, credit: 350000},
+		{currency: "usd", collateral: 17187.5, loan: 6250, credit: 10937.5, rate: 32},
+	}
+	for _, tt := range tests {
+		t.Run(tt.currency, func(t *testing.T) {
+			var credit GetCreditAvailableResponse
+			get(t, app, "/credit?currency="+tt.currency, &credit)
+			if math.Abs(credit.CollateralValue-tt.collateral) > 1e-6 || math.Abs(credit.LoanOutstanding-tt.loan) > 1e-6 || math.Abs(credit.CreditAvailable-tt.credit) > 1e-6 {
+				t.Errorf("collateral %f, loan %f and credit %f, want %f, %f and %f", credit.CollateralValue, credit.LoanOutstanding, credit.CreditAvailable, tt.collateral, tt.loan, tt.credit)
+			}
+			if tt.rate == 0 && credit.FXRate != nil || tt.rate != 0 && (credit.FXRate == nil || credit.FXRate.Price != tt.rate) {
+				t.Errorf("%s with fx rate %+v, want %v", credit.Currency, credit.FXRate, tt.rate)
+			}
+		})
+	}
+
+	// a supported currency without a quote fails instead of answering in THB.
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/credit?currency=EUR", nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("GET /credit in EUR without a quote = %d, want %d", resp.StatusCode, http.StatusInternalServerError)
+	}
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/credit?currency=JPY", nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("GET /credit in JPY = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
+
+func TestPreCalculationLoanConvertsCurrency(t *testing.T) {
+	viper.Set("fx.currencies", []string{"THB", "USD", "EUR"})
+	viper.Set("loan.interest", 0.12)
+	defer func() {
+		viper.Set("fx.currencies", nil)
+		viper.Set("loan.interest", nil)
+	}()
+	app := newPriceTestApp(newMemRepository(), map[string]float64{"USD": 25}, 1)
+
+	var thb, usd PreCalculationLoanResponse
+	post(t, app, "/price/calculation", PreCalculationLoanRequest{BTCAmount: 1, ETHAmount: 2, Period: 12}, &thb)
+	post(t, app, "/price/calculation", PreCalculationLoanRequest{BTCAmount: 1, ETHAmount: 2, Period: 12, Currency: "USD"}, &usd)
+	if thb.Currency != BaseCurrency || thb.FXRate != nil || thb.Summary.TotalLoanAmount != 550000 {
+		t.Errorf("%s loan %f with fx rate %+v, want THB 550000 without one", thb.Currency, thb.Summary.TotalLoanAmount, thb.FXRate)
+	}
+	if usd.Currency != "USD" || usd.FXRate == nil || usd.BTC.LoanAmount != 20000 || usd.ETH.LoanAmount != 2000 || usd.Summary.TotalLoanAmount != 22000 {
+		t.Errorf("%s loans %f and %f with fx rate %+v, want USD 20000 and 2000 at 25", usd.Currency, usd.BTC.LoanAmount, usd.ETH.LoanAmount, usd.FXRate)
+	}
+	if math.Abs(usd.Summary.MonthlyInterest-220) > 1e-6 {
+		t.Errorf("monthly interest = %f USD, want 220", usd.Summary.MonthlyInterest)
+	}
+
+	if status := postStatus(t, app, "/price/calculation", PreCalculationLoanRequest{BTCAmount: 1, Period: 12, Currency: "EUR"}); status != http.StatusInternalServerError {
+		t.Errorf("POST /price/calculation in EUR without a quote = %d, want %d", status, http.StatusInternalServerError)
+	}
+}
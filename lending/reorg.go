@@ -0,0 +1,131 @@
+package lending
+
+import (
+	"context"
+	"fmt"
+	"lending-engine/blockchain"
+	"lending-engine/common"
+	"lending-engine/mail"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// reorgWatcher re-verifies block hash of credited deposits for "deposit.reorg-window" blocks after the confirmed block,
+// and reverses the credit when the deposit's block has been orphaned.
+type reorgWatcher struct {
+	LendingRepository      LendingRepository
+	ListChainFn            blockchain.ListChainFn
+	QuerySafeBlockClientFn blockchain.QuerySafeBlockClientFn
+	QueryBlockHashClientFn blockchain.QueryBlockHashClientFn
+	QueryReceiptClientFn   blockchain.QueryReceiptClientFn
+	AlertOpsFn             mail.AlertOpsFn
+	Logger                 *zap.Logger
+	mu                     sync.Mutex
+}
+
+func NewReorgWatcher(lendingRepository LendingRepository, listChainFn blockchain.ListChainFn, querySafeBlockClientFn blockchain.QuerySafeBlockClientFn, queryBlockHashClientFn blockchain.QueryBlockHashClientFn, queryReceiptClientFn blockchain.QueryReceiptClientFn, alertOpsFn mail.AlertOpsFn, logger *zap.Logger) *reorgWatcher {
+	return &reorgWatcher{
+		LendingRepository:      lendingRepository,
+		ListChainFn:            listChainFn,
+		QuerySafeBlockClientFn: querySafeBlockClientFn,
+		QueryBlockHashClientFn: queryBlockHashClientFn,
+		QueryReceiptClientFn:   queryReceiptClientFn,
+		AlertOpsFn:             alertOpsFn,
+		Logger:                 logger,
+	}
+}
+
+// Run verifies deposits of every evm chain. Native bitcoin deposits aren't on ListChainFn, they are confirmed by admin
+// after "bitcoin.confirmations" and aren't re-checked.
+func (w *reorgWatcher) Run(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, chain := range w.ListChainFn() {
+		if err := w.verify(ctx, chain.ChainID); err != nil {
+			w.Logger.Error(fmt.Sprintf("ChainID: %d | %s", chain.ChainID, err.Error()))
+		}
+	}
+	return nil
+}
+
+func (w *reorgWatcher) verify(ctx context.Context, chainId int) error {
+	safeBlock, err := w.QuerySafeBlockClientFn(ctx, chainId)
+	if err != nil {
+		return err
+	}
+	txns, err := w.LendingRepository.QueryBlockTransactionRepo(ctx, chainId, common.DepositStatus, common.ConfirmStatus, safeBlock-viper.GetInt64("deposit.reorg-window"))
+	if err != nil {
+		return err
+	}
+
+	canonical := make(map[int64]string)
+	for _, txn := range *txns {
+		blockHash, ok := canonical[*txn.BlockNumber]
+		if !ok {
+			blockHash, err = w.QueryBlockHashClientFn(ctx, chainId, *txn.BlockNumber)
+			if err != nil {
+				return err
+			}
+			canonical[*txn.BlockNumber] = blockHash
+		}
+		if strings.EqualFold(blockHash, *txn.BlockHash) {
+			continue
+		}
+		if err := w.reverify(ctx, txn); err != nil {
+			w.Logger.Error(fmt.Sprintf("TxnID: %d | %s", *txn.ID, err.Error()))
+		}
+	}
+	return nil
+}
+
+// reverify is called when the deposit's block is no longer canonical. A transaction re-included in another block keeps its credit,
+// one still waiting in mempool is checked again next run, otherwise the credit is reversed.
+func (w *reorgWatcher) reverify(ctx context.Context, txn WalletTransaction) error {
+	receipt, err := w.QueryReceiptClientFn(ctx, *txn.ChainID, *txn.TxnHash)
+	if err != nil {
+		return err
+	}
+	switch {
+	case receipt.Found && !receipt.Pending && receipt.Status == 1:
+		if _, err := w.LendingRepository.UpdateDepositBlockRepo(ctx, int64(*txn.ID), receipt.Block, receipt.BlockHash); err != nil {
+			return err
+		}
+		w.Logger.Info(fmt.Sprintf("TxnID: %d | Reorg moved Txn Hash: %s from block %d (%s) to %d (%s)", *txn.ID, *txn.TxnHash, *txn.BlockNumber, *txn.BlockHash, receipt.Block, receipt.BlockHash))
+		return nil
+	case receipt.Pending:
+		w.Logger.Info(fmt.Sprintf("TxnID: %d | Reorg put Txn Hash: %s back to mempool", *txn.ID, *txn.TxnHash))
+		return nil
+	}
+
+	wallet, err := w.LendingRepository.QueryWalletRepo(ctx, *txn.AccountID)
+	if err != nil {
+		return err
+	}
+	if wallet == nil {
+		return fmt.Errorf("wallet of account %d doesn't exist", *txn.AccountID)
+	}
+	btc, eth, err := collateralVolumes(*wallet, *txn.CollateralType, -*txn.Volume)
+	if err != nil {
+		return err
+	}
+	// the deposit stays CONFIRMED when the debit fails, so it's reverified next run.
+	rows, err := w.LendingRepository.OrphanDepositRepo(ctx, *txn.ID, *txn.AccountID, btc, eth, time.Now().Format(common.DateYYYYMMDDHHMMSSFormat))
+	if err != nil {
+		return err
+	}
+	if rows != 1 {
+		return nil
+	}
+	w.Logger.Info(fmt.Sprintf("TxnID: %d - Status: %s | AccountID: %d - BTC: %f - ETH: %f", *txn.ID, common.OrphanedStatus, *txn.AccountID, btc, eth))
+
+	message := fmt.Sprintf("Deposit id %d (%f %s of account %d) on chain %d, txn hash %s, was in orphaned block %d (%s). The credit has been reversed, please check the account for loans using this collateral.", *txn.ID, *txn.Volume, *txn.CollateralType, *txn.AccountID, *txn.ChainID, *txn.TxnHash, *txn.BlockNumber, *txn.BlockHash)
+	if err := w.AlertOpsFn(w.Logger, "Deposit orphaned by reorg", message); err != nil {
+		w.Logger.Error(err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package lending
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"lending-engine/blockchain"
+	"lending-engine/common"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+type alerts []string
+
+func (a *alerts) alertOpsFn() func(*zap.Logger, string, string) error {
+	return func(logger *zap.Logger, title string, message string) error {
+		*a = append(*a, title)
+		return nil
+	}
+}
+
+func newTestReorgWatcher(repository LendingRepository, chain *testChain, alerted *alerts) *reorgWatcher {
+	return NewReorgWatcher(
+		repository,
+		blockchain.NewListChainFn(chain.registry),
+		blockchain.NewQuerySafeBlockClientFn(chain.registry),
+		blockchain.NewQueryBlockHashClientFn(chain.registry),
+		blockchain.NewQueryReceiptClientFn(chain.registry),
+		alerted.alertOpsFn(),
+		zap.NewNop(),
+	)
+}
+
+// creditDeposit records a confirmed deposit of tx the way SubmitDeposit does and credits the wallet.
+func creditDeposit(t *testing.T, repository *memRepository, chain *testChain, accountId int, volume float64, txnHash string) int {
+	t.Helper()
+	id := repository.addTransaction(WalletTransaction{
+		AccountID:      intPtr(accountId),
+		Address:        stringPtr(chain.depositor.From.Hex()),
+		ChainID:        intPtr(testChainId),
+		TxnHash:        stringPtr(txnHash),
+		CollateralType: stringPtr("BTC"),
+		Volume:         float64Ptr(volume),
+		TxnType:        stringPtr(common.DepositStatus),
+		Status:         stringPtr(common.ConfirmStatus),
+	})
+	wallet := repository.wallet(t, accountId)
+	repository.UpdateWalletRepo(context.Background(), accountId, *wallet.BTCVolume+volume, *wallet.ETHVolume, nil, "")
+	return id
+}
+
+func TestReorgWatcherReversesOrphanedDeposit(t *testing.T) {
+	viper.Set("deposit.reorg-window", 10)
+	defer viper.Set("deposit.reorg-window", nil)
+
+	chain := newTestChain(t)
+	repository := newMemRepository()
+	repository.addWallet(1, 0, 0)
+	tx := chain.deposit(t, chain.chain.Address, 2)
+	id := creditDeposit(t, repository, chain, 1, 2, tx.Hash().Hex())
+	block, blockHash := chain.blockOf(t, tx)
+	repository.UpdateDepositBlockRepo(context.Background(), int64(id), block, blockHash)
+
+	var alerted alerts
+	watcher := newTestReorgWatcher(repository, chain, &alerted)
+
+	// nothing changes while the block is canonical.
+	if err := watcher.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := repository.transaction(t, id); *got.Status != common.ConfirmStatus {
+		t.Fatalf("status before reorg = %s, want %s", *got.Status, common.ConfirmStatus)
+	}
+
+	chain.orphan(t, tx)
+	if err := watcher.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := repository.transaction(t, id); *got.Status != common.OrphanedStatus {
+		t.Errorf("status = %s, want %s", *got.Status, common.OrphanedStatus)
+	}
+	if got := repository.wallet(t, 1); *got.BTCVolume != 0 {
+		t.Errorf("wallet btc = %f, want credit reversed to 0", *got.BTCVolume)
+	}
+	if len(alerted) != 1 {
+		t.Errorf("alerts = %v, want one", alerted)
+	}
+
+	// the reversed deposit isn't picked up again.
+	if err := watcher.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := repository.wallet(t, 1); *got.BTCVolume != 0 {
+		t.Errorf("wallet btc after second run = %f, want 0", *got.BTCVolume)
+	}
+}
+
+func TestReorgWatcherKeepsReincludedDeposit(t *testing.T) {
+	viper.Set("deposit.reorg-window", 10)
+	defer viper.Set("deposit.reorg-window", nil)
+
+	chain := newTestChain(t)
+	repository := newMemRepository()
+	repository.addWallet(1, 0, 0)
+	tx := chain.deposit(t, chain.chain.Address, 2)
+	id := creditDeposit(t, repository, chain, 1, 2, tx.Hash().Hex())
+	block, blockHash := chain.blockOf(t, tx)
+	repository.UpdateDepositBlockRepo(context.Background(), int64(id), block, blockHash)
+
+	chain.orphan(t, tx)
+	// the orphaned transaction is mined again on the new branch.
+	if err := chain.SendTransaction(context.Background(), tx); err != nil {
+		t.Fatal(err)
+	}
+	newBlock, newBlockHash := chain.blockOf(t, tx)
+	if newBlockHash == blockHash {
+		t.Fatal("transaction should be mined in another block")
+	}
+
+	var alerted alerts
+	if err := newTestReorgWatcher(repository, chain, &alerted).Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	got := repository.transaction(t, id)
+	if *got.Status != common.ConfirmStatus {
+		t.Errorf("status = %s, want %s", *got.Status, common.ConfirmStatus)
+	}
+	if *got.BlockNumber != newBlock || *got.BlockHash != newBlockHash {
+		t.Errorf("block = %d (%s), want %d (%s)", *got.BlockNumber, *got.BlockHash, newBlock, newBlockHash)
+	}
+	if got := repository.wallet(t, 1); *got.BTCVolume != 2 {
+		t.Errorf("wallet btc = %f, want credit kept at 2", *got.BTCVolume)
+	}
+	if len(alerted) != 0 {
+		t.Errorf("alerts = %v, want none", alerted)
+	}
+}
+
+func TestReorgWatcherKeepsDepositWhenDebitFails(t *testing.T) {
+	viper.Set("deposit.reorg-window", 10)
+	defer viper.Set("deposit.reorg-window", nil)
+
+	chain := newTestChain(t)
+	repository := newMemRepository()
+	repository.addWallet(1, 0, 0)
+	tx := chain.deposit(t, chain.chain.Address, 2)
+	id := creditDeposit(t, repository, chain, 1, 2, tx.Hash().Hex())
+	block, blockHash := chain.blockOf(t, tx)
+	repository.UpdateDepositBlockRepo(context.Background(), int64(id), block, blockHash)
+	chain.orphan(t, tx)
+
+	var alerted alerts
+	watcher := newTestReorgWatcher(repository, chain, &alerted)
+	repository.walletErr = errors.New("connection reset")
+	if err := watcher.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := repository.transaction(t, id); *got.Status != common.ConfirmStatus {
+		t.Fatalf("status = %s after failed debit, want %s", *got.Status, common.ConfirmStatus)
+	}
+	if got := repository.wallet(t, 1); *got.BTCVolume != 2 {
+		t.Errorf("wallet btc = %f after failed debit, want 2", *got.BTCVolume)
+	}
+	if len(alerted) != 0 {
+		t.Errorf("alerts = %v, want none", alerted)
+	}
+
+	// the still CONFIRMED deposit is reverified and reversed on the next run.
+	repository.walletErr = nil
+	if err := watcher.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := repository.transaction(t, id); *got.Status != common.OrphanedStatus {
+		t.Errorf("status = %s, want %s", *got.Status, common.OrphanedStatus)
+	}
+	if got := repository.wallet(t, 1); *got.BTCVolume != 0 {
+		t.Errorf("wallet btc = %f, want credit reversed to 0", *got.BTCVolume)
+	}
+}
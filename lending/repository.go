@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"lending-engine/common"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -31,7 +32,12 @@ func (r lendingRepositoryDB) QueryWalletTransactionByIDRepo(ctx context.Context,
 				txn_type,
 				status,
 				created_datetime,
-				updated_datetime
+				updated_datetime,
+				block_number,
+				block_hash,
+				parent_id,
+				sweep_id,
+				to_address
 		FROM lending.public.wallet_transaction
 		WHERE id = $1
 	;`, id)
@@ -58,7 +64,12 @@ func (r lendingRepositoryDB) QueryWalletTransactionRepo(ctx context.Context, req
 				txn_type,
 				status,
 				created_datetime,
-				updated_datetime
+				updated_datetime,
+				block_number,
+				block_hash,
+				parent_id,
+				sweep_id,
+				to_address
 		FROM lending.public.wallet_transaction
 		WHERE 1 = 1
 	`
@@ -80,7 +91,7 @@ func (r lendingRepositoryDB) QueryWalletTransactionRepo(ctx context.Context, req
 	return &walletTransactions, nil
 }
 
-func (r lendingRepositoryDB) InsertDepositRepo(ctx context.Context, accountId int, address string, chainId int, txnHash string, collateralType string, volume float64, txnType string, status string) (int64, error) {
+func (r lendingRepositoryDB) InsertDepositRepo(ctx context.Context, accountId int, address string, toAddress string, chainId int, txnHash string, collateralType string, volume float64, txnType string, status string) (int64, error) {
 	var depositId int64
 	if err := r.db.QueryRowContext(ctx, `
 		INSERT INTO lending.public.wallet_transaction
@@ -92,7 +103,8 @@ func (r lendingRepositoryDB) InsertDepositRepo(ctx context.Context, accountId in
 			collateral_type,
 			volume,
 			txn_type,
-			status
+			status,
+			to_address
 		)
 		VALUES
 		(
@@ -103,10 +115,77 @@ func (r lendingRepositoryDB) InsertDepositRepo(ctx context.Context, accountId in
 			$5,
 			$6,
 			$7,
-			$8
+			$8,
+			NULLIF($9, '')
+		)
+		RETURNING id
+	;`, accountId, address, chainId, txnHash, collateralType, volume, txnType, status, toAddress).Scan(&depositId); err != nil {
+		return 0, err
+	}
+	return depositId, nil
+}
+
+// CreditDepositRepo records a CONFIRMED deposit in blockNumber and sets the credited wallet volumes in one transaction, so a
+// deposit is never recorded without its credit.
+func (r lendingRepositoryDB) CreditDepositRepo(ctx context.Context, accountId int, address string, toAddress string, chainId int, txnHash string, collateralType string, volume float64, blockNumber int64, blockHash string, btc float64, eth float64, timestamp string) (int64, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var depositId int64
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO lending.public.wallet_transaction
+		(
+			account_id,
+			address,
+			chain_id,
+			txn_hash,
+			collateral_type,
+			volume,
+			txn_type,
+			status,
+			to_address,
+			block_number,
+			block_hash
+		)
+		VALUES
+		(
+			$1,
+			$2,
+			$3,
+			$4,
+			$5,
+			$6,
+			$7,
+			$8,
+			NULLIF($9, ''),
+			$10,
+			$11
 		)
 		RETURNING id
-	;`, accountId, address, chainId, txnHash, collateralType, volume, txnType, status).Scan(&depositId); err != nil {
+	;`, accountId, address, chainId, txnHash, collateralType, volume, common.DepositStatus, common.ConfirmStatus, toAddress, blockNumber, blockHash).Scan(&depositId); err != nil {
+		return 0, err
+	}
+	result, err := tx.ExecContext(ctx, `
+		UPDATE lending.public.wallet
+		SET btc_volume = $1,
+			eth_volume = $2,
+			latest_datetime = $3
+		WHERE account_id = $4
+	;`, btc, eth, timestamp, accountId)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if rows != 1 {
+		return 0, fmt.Errorf("expected to update 1 wallet, affected %d", rows)
+	}
+	if err := tx.Commit(); err != nil {
 		return 0, err
 	}
 	return depositId, nil
@@ -129,6 +208,60 @@ func (r lendingRepositoryDB) UpdateDepositRepo(ctx context.Context, id int, stat
 	return rows, nil
 }
 
+func (r lendingRepositoryDB) UpdateDepositBlockRepo(ctx context.Context, id int64, blockNumber int64, blockHash string) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE lending.public.wallet_transaction
+		SET 	block_number = $1,
+				block_hash = $2
+		WHERE id = $3
+	;`, blockNumber, blockHash, id)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return rows, nil
+}
+
+// QueryBlockTransactionRepo returns transactions on chainId recorded with block hash at or after fromBlock.
+func (r lendingRepositoryDB) QueryBlockTransactionRepo(ctx context.Context, chainId int, txnType string, status string, fromBlock int64) (*[]WalletTransaction, error) {
+	walletTransactions := make([]WalletTransaction, 0)
+	err := r.db.SelectContext(ctx, &walletTransactions, `
+		SELECT	id,
+				account_id,
+				address,
+				chain_id,
+				txn_hash,
+				collateral_type,
+				volume,
+				txn_type,
+				status,
+				created_datetime,
+				updated_datetime,
+				block_number,
+				block_hash,
+				parent_id,
+				sweep_id,
+				to_address
+		FROM lending.public.wallet_transaction
+		WHERE chain_id = $1
+		AND txn_type = $2
+		AND status = $3
+		AND block_hash IS NOT NULL
+		AND block_number >= $4
+	;`, chainId, txnType, status, fromBlock)
+	switch {
+	case err == sql.ErrNoRows:
+		return &walletTransactions, nil
+	case err != nil:
+		return nil, err
+	default:
+		return &walletTransactions, nil
+	}
+}
+
 func (r lendingRepositoryDB) InsertWithdrawRepo(ctx context.Context, accountId int, address string, chainId int, collateralType string, volume float64, txnType string, status string) (int64, error) {
 	var withdrawId int64
 	if err := r.db.QueryRowContext(ctx, `
@@ -159,6 +292,38 @@ func (r lendingRepositoryDB) InsertWithdrawRepo(ctx context.Context, accountId i
 	return withdrawId, nil
 }
 
+func (r lendingRepositoryDB) InsertWithdrawFeeRepo(ctx context.Context, parentId int64, accountId int, address string, chainId int, collateralType string, volume float64, status string) (int64, error) {
+	var feeId int64
+	if err := r.db.QueryRowContext(ctx, `
+		INSERT INTO lending.public.wallet_transaction
+		(
+			account_id,
+			address,
+			chain_id,
+			collateral_type,
+			volume,
+			txn_type,
+			status,
+			parent_id
+		)
+		VALUES
+		(
+			$1,
+			$2,
+			$3,
+			$4,
+			$5,
+			$6,
+			$7,
+			$8
+		)
+		RETURNING id
+	;`, accountId, address, chainId, collateralType, volume, common.WithdrawFeeType, status, parentId).Scan(&feeId); err != nil {
+		return 0, err
+	}
+	return feeId, nil
+}
+
 func (r lendingRepositoryDB) UpdateWithdrawRepo(ctx context.Context, id int, txnHash string, status string, timestamp string) (int64, error) {
 	result, err := r.db.ExecContext(ctx, `
 		UPDATE lending.public.wallet_transaction
@@ -177,6 +342,82 @@ func (r lendingRepositoryDB) UpdateWithdrawRepo(ctx context.Context, id int, txn
 	return rows, nil
 }
 
+// UpdateWithdrawStatusRepo moves withdraw id from status "from" to "to", it affects no row when another request has moved it first.
+func (r lendingRepositoryDB) UpdateWithdrawStatusRepo(ctx context.Context, id int, from string, to string, timestamp string) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE lending.public.wallet_transaction
+		SET 	status = $1,
+				updated_datetime = $2
+		WHERE id = $3
+		AND status = $4
+	;`, to, timestamp, id, from)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return rows, nil
+}
+
+// FailWithdrawRepo marks a BROADCAST withdrawal FAILED and sets the wallet to the refunded btc and eth in one transaction,
+// so a withdrawal is never FAILED without its refund nor refunded twice. It affects no row when the withdrawal isn't BROADCAST anymore.
+func (r lendingRepositoryDB) FailWithdrawRepo(ctx context.Context, id int, accountId int, btc float64, eth float64, timestamp string) (int64, error) {
+	return r.updateStatusAndWallet(ctx, id, common.BroadcastStatus, common.FailedStatus, accountId, btc, eth, timestamp)
+}
+
+// OrphanDepositRepo marks a CONFIRMED deposit ORPHANED and sets the wallet to the debited btc and eth in one transaction,
+// so a deposit is never ORPHANED with its credit kept nor reversed twice. It affects no row when the deposit isn't CONFIRMED anymore.
+func (r lendingRepositoryDB) OrphanDepositRepo(ctx context.Context, id int, accountId int, btc float64, eth float64, timestamp string) (int64, error) {
+	return r.updateStatusAndWallet(ctx, id, common.ConfirmStatus, common.OrphanedStatus, accountId, btc, eth, timestamp)
+}
+
+// updateStatusAndWallet moves a wallet transaction from status to status and sets the account's wallet volumes in one
+// transaction, nothing is written when the transaction isn't in from or the wallet can't be updated.
+func (r lendingRepositoryDB) updateStatusAndWallet(ctx context.Context, id int, from string, to string, accountId int, btc float64, eth float64, timestamp string) (int64, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE lending.public.wallet_transaction
+		SET 	status = $1,
+				updated_datetime = $2
+		WHERE id = $3
+		AND status = $4
+	;`, to, timestamp, id, from)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil || rows != 1 {
+		return 0, err
+	}
+	result, err = tx.ExecContext(ctx, `
+		UPDATE lending.public.wallet
+		SET btc_volume = $1,
+			eth_volume = $2,
+			latest_datetime = $3
+		WHERE account_id = $4
+	;`, btc, eth, timestamp, accountId)
+	if err != nil {
+		return 0, err
+	}
+	if rows, err = result.RowsAffected(); err != nil {
+		return 0, err
+	}
+	if rows != 1 {
+		return 0, fmt.Errorf("expected to update 1 wallet, affected %d", rows)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
 func (r lendingRepositoryDB) QueryWalletRepo(ctx context.Context, accountId int) (*Wallet, error) {
 	var wallet Wallet
 	err := r.db.GetContext(ctx, &wallet, `
@@ -194,6 +435,23 @@ func (r lendingRepositoryDB) QueryWalletRepo(ctx context.Context, accountId int)
 	}
 }
 
+func (r lendingRepositoryDB) QueryWalletsRepo(ctx context.Context) (*[]Wallet, error) {
+	wallets := make([]Wallet, 0)
+	err := r.db.SelectContext(ctx, &wallets, `
+		SELECT account_id, btc_volume, eth_volume, margin_call_date, latest_datetime
+		FROM lending.public.wallet
+		ORDER BY account_id
+	;`)
+	switch {
+	case err == sql.ErrNoRows:
+		return &wallets, nil
+	case err != nil:
+		return nil, err
+	default:
+		return &wallets, nil
+	}
+}
+
 func (r lendingRepositoryDB) UpdateWalletRepo(ctx context.Context, accountId int, btc float64, eth float64, margin *string, latest string) (int64, error) {
 	result, err := r.db.ExecContext(ctx, `
 		UPDATE lending.public.wallet
@@ -213,6 +471,26 @@ func (r lendingRepositoryDB) UpdateWalletRepo(ctx context.Context, accountId int
 	return rows, nil
 }
 
+// UpdateMarginCallRepo sets margin_call_date of an account that isn't margin called yet, or clears it when margin is nil,
+// so a margin call already running keeps its first date.
+func (r lendingRepositoryDB) UpdateMarginCallRepo(ctx context.Context, accountId int, margin *string, latest string) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE lending.public.wallet
+		SET margin_call_date = $1,
+			latest_datetime = $2
+		WHERE account_id = $3
+		AND (margin_call_date IS NULL OR $1::date IS NULL)
+	;`, margin, latest, accountId)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return rows, nil
+}
+
 func (r lendingRepositoryDB) QueryContractByIDRepo(ctx context.Context, id int) (*Contract, error) {
 	var contract Contract
 	err := r.db.GetContext(ctx, &contract, `
@@ -456,3 +734,418 @@ func (r lendingRepositoryDB) LiquidationRepo(ctx context.Context, accountId int,
 		return &liquidation, nil
 	}
 }
+
+func (r lendingRepositoryDB) QueryDepositAddressRepo(ctx context.Context, accountId int) (*DepositAddress, error) {
+	var depositAddress DepositAddress
+	err := r.db.GetContext(ctx, &depositAddress, `
+		SELECT account_id, address, derivation_path, created_datetime
+		FROM lending.public.deposit_address
+		WHERE account_id = $1
+	;`, accountId)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, nil
+	case err != nil:
+		return nil, err
+	default:
+		return &depositAddress, nil
+	}
+}
+
+func (r lendingRepositoryDB) QueryDepositAddressesRepo(ctx context.Context) (*[]DepositAddress, error) {
+	depositAddresses := make([]DepositAddress, 0)
+	err := r.db.SelectContext(ctx, &depositAddresses, `
+		SELECT account_id, address, derivation_path, created_datetime
+		FROM lending.public.deposit_address
+	;`)
+	switch {
+	case err == sql.ErrNoRows:
+		return &depositAddresses, nil
+	case err != nil:
+		return nil, err
+	default:
+		return &depositAddresses, nil
+	}
+}
+
+func (r lendingRepositoryDB) InsertDepositAddressRepo(ctx context.Context, accountId int, address string, derivationPath string) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO lending.public.deposit_address
+		(
+			account_id,
+			address,
+			derivation_path
+		)
+		VALUES
+		(
+			$1,
+			$2,
+			$3
+		)
+		ON CONFLICT (account_id) DO NOTHING
+	;`, accountId, address, derivationPath)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return rows, nil
+}
+
+func (r lendingRepositoryDB) QueryDepositScanRepo(ctx context.Context, chainId int) (*int64, error) {
+	var blockNumber int64
+	err := r.db.GetContext(ctx, &blockNumber, `
+		SELECT block_number
+		FROM lending.public.deposit_scan
+		WHERE chain_id = $1
+	;`, chainId)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, nil
+	case err != nil:
+		return nil, err
+	default:
+		return &blockNumber, nil
+	}
+}
+
+func (r lendingRepositoryDB) UpsertDepositScanRepo(ctx context.Context, chainId int, blockNumber int64, timestamp string) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO lending.public.deposit_scan
+		(
+			chain_id,
+			block_number,
+			updated_datetime
+		)
+		VALUES
+		(
+			$1,
+			$2,
+			$3
+		)
+		ON CONFLICT (chain_id) DO UPDATE
+		SET		block_number = EXCLUDED.block_number,
+				updated_datetime = EXCLUDED.updated_datetime
+	;`, chainId, blockNumber, timestamp)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return rows, nil
+}
+
+func (r lendingRepositoryDB) QueryWithdrawAddressRepo(ctx context.Context, accountId int, address string) (*WithdrawAddress, error) {
+	var withdrawAddress WithdrawAddress
+	err := r.db.GetContext(ctx, &withdrawAddress, `
+		SELECT id, account_id, address, label, verified, active_datetime, created_datetime
+		FROM lending.public.withdraw_address
+		WHERE account_id = $1 AND address = $2
+	;`, accountId, address)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, nil
+	case err != nil:
+		return nil, err
+	default:
+		return &withdrawAddress, nil
+	}
+}
+
+func (r lendingRepositoryDB) QueryWithdrawAddressesRepo(ctx context.Context, accountId int) (*[]WithdrawAddress, error) {
+	withdrawAddresses := make([]WithdrawAddress, 0)
+	err := r.db.SelectContext(ctx, &withdrawAddresses, `
+		SELECT id, account_id, address, label, verified, active_datetime, created_datetime
+		FROM lending.public.withdraw_address
+		WHERE account_id = $1
+		ORDER BY id
+	;`, accountId)
+	switch {
+	case err == sql.ErrNoRows:
+		return &withdrawAddresses, nil
+	case err != nil:
+		return nil, err
+	default:
+		return &withdrawAddresses, nil
+	}
+}
+
+func (r lendingRepositoryDB) InsertWithdrawAddressRepo(ctx context.Context, accountId int, address string, label string, verified bool, activeDatetime string) (int64, error) {
+	var id int64
+	if err := r.db.QueryRowContext(ctx, `
+		INSERT INTO lending.public.withdraw_address
+		(
+			account_id,
+			address,
+			label,
+			verified,
+			active_datetime
+		)
+		VALUES
+		(
+			$1,
+			$2,
+			$3,
+			$4,
+			$5
+		)
+		RETURNING id
+	;`, accountId, address, label, verified, activeDatetime).Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r lendingRepositoryDB) DeleteWithdrawAddressRepo(ctx context.Context, id int, accountId int) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM lending.public.withdraw_address
+		WHERE id = $1 AND account_id = $2
+	;`, id, accountId)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return rows, nil
+}
+
+// InsertReserveReportRepo saves a report with the liabilities of its merkle tree in one transaction, so a published root
+// always has all its leaves.
+func (r lendingRepositoryDB) InsertReserveReportRepo(ctx context.Context, root string, report string, signature string, signer string, liabilities []ReserveLiability) (int64, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	for _, liability := range liabilities {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO lending.public.reserve_liability
+			(
+				root,
+				account_id,
+				btc_volume,
+				eth_volume,
+				salt,
+				leaf_index
+			)
+			VALUES
+			(
+				$1,
+				$2,
+				$3,
+				$4,
+				$5,
+				$6
+			)
+		;`, root, *liability.AccountID, *liability.BTCVolume, *liability.ETHVolume, *liability.Salt, *liability.LeafIndex); err != nil {
+			return 0, err
+		}
+	}
+	var reportId int64
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO lending.public.reserve_report
+		(
+			root,
+			report,
+			signature,
+			signer
+		)
+		VALUES
+		(
+			$1,
+			$2,
+			$3,
+			$4
+		)
+		RETURNING id
+	;`, root, report, signature, signer).Scan(&reportId); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return reportId, nil
+}
+
+func (r lendingRepositoryDB) QueryLatestReserveReportRepo(ctx context.Context) (*ReserveReport, error) {
+	var reserveReport ReserveReport
+	err := r.db.GetContext(ctx, &reserveReport, `
+		SELECT id, root, report, signature, signer, created_datetime
+		FROM lending.public.reserve_report
+		ORDER BY id DESC
+		LIMIT 1
+	;`)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, nil
+	case err != nil:
+		return nil, err
+	default:
+		return &reserveReport, nil
+	}
+}
+
+func (r lendingRepositoryDB) QueryReserveLiabilitiesRepo(ctx context.Context, root string) (*[]ReserveLiability, error) {
+	reserveLiabilities := make([]ReserveLiability, 0)
+	err := r.db.SelectContext(ctx, &reserveLiabilities, `
+		SELECT root, account_id, btc_volume, eth_volume, salt, leaf_index
+		FROM lending.public.reserve_liability
+		WHERE root = $1
+		ORDER BY leaf_index
+	;`, root)
+	switch {
+	case err == sql.ErrNoRows:
+		return &reserveLiabilities, nil
+	case err != nil:
+		return nil, err
+	default:
+		return &reserveLiabilities, nil
+	}
+}
+
+func (r lendingRepositoryDB) QuerySweepRepo(ctx context.Context, request map[string]interface{}) (*[]Sweep, error) {
+	sweeps := make([]Sweep, 0)
+	query := `
+		SELECT	id,
+				chain_id,
+				account_id,
+				address,
+				collateral_type,
+				volume,
+				txn_hash,
+				gas_txn_hash,
+				status,
+				created_datetime,
+				updated_datetime
+		FROM lending.public.sweep
+		WHERE 1 = 1
+	`
+	for key, _ := range request {
+		query = fmt.Sprintf("%s AND %s = :%s", query, key, key)
+	}
+	rows, err := r.db.NamedQueryContext(ctx, query, request)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var sweep Sweep
+		if err := rows.StructScan(&sweep); err != nil {
+			return nil, err
+		}
+		sweeps = append(sweeps, sweep)
+	}
+	defer rows.Close()
+	return &sweeps, nil
+}
+
+func (r lendingRepositoryDB) InsertSweepRepo(ctx context.Context, chainId int, accountId int, address string, collateralType string, volume float64, txnHash string, gasTxnHash string, status string) (int64, error) {
+	var sweepId int64
+	if err := r.db.QueryRowContext(ctx, `
+		INSERT INTO lending.public.sweep
+		(
+			chain_id,
+			account_id,
+			address,
+			collateral_type,
+			volume,
+			txn_hash,
+			gas_txn_hash,
+			status
+		)
+		VALUES
+		(
+			$1,
+			$2,
+			$3,
+			$4,
+			$5,
+			NULLIF($6, ''),
+			NULLIF($7, ''),
+			$8
+		)
+		RETURNING id
+	;`, chainId, accountId, address, collateralType, volume, txnHash, gasTxnHash, status).Scan(&sweepId); err != nil {
+		return 0, err
+	}
+	return sweepId, nil
+}
+
+func (r lendingRepositoryDB) UpdateSweepRepo(ctx context.Context, id int, txnHash string, volume float64, status string, timestamp string) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE lending.public.sweep
+		SET 	txn_hash = NULLIF($1, ''),
+				volume = $2,
+				status = $3,
+				updated_datetime = $4
+		WHERE id = $5
+	;`, txnHash, volume, status, timestamp, id)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return rows, nil
+}
+
+// UpdateSweepStatusRepo moves sweep from status to status, it affects no row when the sweep isn't in from anymore.
+func (r lendingRepositoryDB) UpdateSweepStatusRepo(ctx context.Context, id int, from string, to string, timestamp string) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE lending.public.sweep
+		SET 	status = $1,
+				updated_datetime = $2
+		WHERE id = $3
+		AND status = $4
+	;`, to, timestamp, id, from)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return rows, nil
+}
+
+// UpdateDepositSweepRepo links confirmed deposits of the account paid to the swept address that aren't swept yet to the sweep.
+// Deposits paid to the chain's address, or whose destination isn't known, never reach the address and are left out.
+func (r lendingRepositoryDB) UpdateDepositSweepRepo(ctx context.Context, sweepId int64, accountId int, toAddress string, chainId int, collateralType string) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE lending.public.wallet_transaction
+		SET 	sweep_id = $1
+		WHERE account_id = $2 AND to_address = $3 AND chain_id = $4 AND collateral_type = $5 AND txn_type = $6 AND status = $7 AND sweep_id IS NULL
+	;`, sweepId, accountId, toAddress, chainId, collateralType, common.DepositStatus, common.ConfirmStatus)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return rows, nil
+}
+
+// ClearDepositSweepRepo unlinks deposits of a failed sweep, so the next sweep picks them up.
+func (r lendingRepositoryDB) ClearDepositSweepRepo(ctx context.Context, sweepId int) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE lending.public.wallet_transaction
+		SET 	sweep_id = NULL
+		WHERE sweep_id = $1
+	;`, sweepId)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return rows, nil
+}
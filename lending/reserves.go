@@ -0,0 +1,211 @@
+package lending
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"lending-engine/blockchain"
+	"lending-engine/common"
+	"lending-engine/internal/merkle"
+	"lending-engine/mail"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"go.uber.org/zap"
+)
+
+// reservesReporter publishes proof-of-reserves: wallet balances are committed in a merkle tree of liabilities
+// and their totals are compared with token balances held by custody addresses on every chain.
+type reservesReporter struct {
+	LendingRepository         LendingRepository
+	ListChainFn               blockchain.ListChainFn
+	QueryTokenBalanceClientFn blockchain.QueryTokenBalanceClientFn
+	SignMessageFn             blockchain.SignMessageFn
+	AlertOpsFn                mail.AlertOpsFn
+	Logger                    *zap.Logger
+	mu                        sync.Mutex
+}
+
+func NewReservesReporter(lendingRepository LendingRepository, listChainFn blockchain.ListChainFn, queryTokenBalanceClientFn blockchain.QueryTokenBalanceClientFn, signMessageFn blockchain.SignMessageFn, alertOpsFn mail.AlertOpsFn, logger *zap.Logger) *reservesReporter {
+	return &reservesReporter{
+		LendingRepository:         lendingRepository,
+		ListChainFn:               listChainFn,
+		QueryTokenBalanceClientFn: queryTokenBalanceClientFn,
+		SignMessageFn:             signMessageFn,
+		AlertOpsFn:                alertOpsFn,
+		Logger:                    logger,
+	}
+}
+
+type ReservesReport struct {
+	Root            string         `json:"root" example:"0x3ad2c1b9a5ed6e1e0a0e6a1b0c6a4cfd1c4e7b8e0c5a9b7d2c8e1f0a6b4d3c2e"`
+	Accounts        int            `json:"accounts" example:"120"`
+	Assets          []ReserveAsset `json:"assets"`
+	CreatedDatetime string         `json:"createdDatetime" example:"2021-01-02 12:13:14"`
+}
+
+type ReserveAsset struct {
+	CollateralType string         `json:"collateralType" example:"BTC"`
+	Liabilities    float64        `json:"liabilities" example:"12.5"`
+	Reserves       float64        `json:"reserves" example:"12.7"`
+	Ratio          float64        `json:"ratio" example:"1.016"`
+	Chains         []ReserveChain `json:"chains"`
+}
+
+type ReserveChain struct {
+	ChainID int     `json:"chainId" example:"56"`
+	Name    string  `json:"name" example:"binance"`
+	Balance float64 `json:"balance" example:"12.7"`
+}
+
+// Run builds one report, signs it and saves it with the liabilities needed for inclusion proofs.
+func (r *reservesReporter) Run(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	wallets, err := r.LendingRepository.QueryWalletsRepo(ctx)
+	if err != nil {
+		return err
+	}
+	salts := make([]string, 0, len(*wallets))
+	leaves := make([][]byte, 0, len(*wallets))
+	liabilities := map[string]float64{"BTC": 0, "ETH": 0}
+	for _, wallet := range *wallets {
+		salt, err := newSalt()
+		if err != nil {
+			return err
+		}
+		salts = append(salts, salt)
+		leaves = append(leaves, reserveLeaf(*wallet.AccountID, *wallet.BTCVolume, *wallet.ETHVolume, salt))
+		liabilities["BTC"] += *wallet.BTCVolume
+		liabilities["ETH"] += *wallet.ETHVolume
+	}
+	root := hexutil.Encode(merkle.New(leaves).Root())
+
+	assets, err := r.reserves(ctx, liabilities)
+	if err != nil {
+		return err
+	}
+	report := ReservesReport{
+		Root:            root,
+		Accounts:        len(*wallets),
+		Assets:          assets,
+		CreatedDatetime: time.Now().Format(common.DateYYYYMMDDHHMMSSFormat),
+	}
+	message, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	signature, signer, err := r.SignMessageFn(string(message))
+	if err != nil {
+		return err
+	}
+
+	liabilityRows := make([]ReserveLiability, 0, len(*wallets))
+	for i, wallet := range *wallets {
+		leafIndex := i
+		liabilityRows = append(liabilityRows, ReserveLiability{
+			Root:      &root,
+			AccountID: wallet.AccountID,
+			BTCVolume: wallet.BTCVolume,
+			ETHVolume: wallet.ETHVolume,
+			Salt:      &salts[i],
+			LeafIndex: &leafIndex,
+		})
+	}
+	reportId, err := r.LendingRepository.InsertReserveReportRepo(ctx, root, string(message), signature, signer, liabilityRows)
+	if err != nil {
+		return err
+	}
+	r.Logger.Info(fmt.Sprintf("Reserve Report ID: %d | Root: %s - Accounts: %d", reportId, root, len(*wallets)))
+
+	for _, asset := range assets {
+		if asset.Reserves < asset.Liabilities {
+			message := fmt.Sprintf("Reserve report id %d: %s reserves %f are below liabilities %f.", reportId, asset.CollateralType, asset.Reserves, asset.Liabilities)
+			if err := r.AlertOpsFn(r.Logger, "Reserves below liabilities", message); err != nil {
+				r.Logger.Error(err.Error())
+			}
+		}
+	}
+	return nil
+}
+
+// reserves sums token balance of custody addresses and deposit addresses per collateral type over every chain.
+func (r *reservesReporter) reserves(ctx context.Context, liabilities map[string]float64) ([]ReserveAsset, error) {
+	depositAddresses, err := r.LendingRepository.QueryDepositAddressesRepo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	assets := make(map[string]*ReserveAsset)
+	for collateralType, liability := range liabilities {
+		assets[collateralType] = &ReserveAsset{
+			CollateralType: collateralType,
+			Liabilities:    liability,
+			Chains:         make([]ReserveChain, 0),
+		}
+	}
+	for _, chain := range r.ListChainFn() {
+		owners := chain.CustodyAddresses()
+		for _, depositAddress := range *depositAddresses {
+			owners = append(owners, *depositAddress.Address)
+		}
+		for collateralType := range chain.Tokens {
+			balance, err := r.QueryTokenBalanceClientFn(ctx, chain.ChainID, collateralType, owners)
+			if err != nil {
+				return nil, err
+			}
+			asset, ok := assets[collateralType]
+			if !ok {
+				asset = &ReserveAsset{
+					CollateralType: collateralType,
+					Chains:         make([]ReserveChain, 0),
+				}
+				assets[collateralType] = asset
+			}
+			asset.Reserves += balance
+			asset.Chains = append(asset.Chains, ReserveChain{
+				ChainID: chain.ChainID,
+				Name:    chain.Name,
+				Balance: balance,
+			})
+		}
+	}
+	reserveAssets := make([]ReserveAsset, 0, len(assets))
+	for _, asset := range assets {
+		if asset.Liabilities > 0 {
+			asset.Ratio = asset.Reserves / asset.Liabilities
+		}
+		reserveAssets = append(reserveAssets, *asset)
+	}
+	sort.Slice(reserveAssets, func(i, j int) bool { return reserveAssets[i].CollateralType < reserveAssets[j].CollateralType })
+	return reserveAssets, nil
+}
+
+// ReserveLeafData is what each account's leaf commits to, "accountId|btc|eth|salt" with 8 decimals.
+// The salt keeps balances from being guessed from a published leaf.
+func ReserveLeafData(accountId int, btc float64, eth float64, salt string) string {
+	return strings.Join([]string{
+		strconv.Itoa(accountId),
+		strconv.FormatFloat(btc, 'f', 8, 64),
+		strconv.FormatFloat(eth, 'f', 8, 64),
+		salt,
+	}, "|")
+}
+
+func reserveLeaf(accountId int, btc float64, eth float64, salt string) []byte {
+	return merkle.Leaf([]byte(ReserveLeafData(accountId, btc, eth, salt)))
+}
+
+func newSalt() (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(salt), nil
+}
@@ -0,0 +1,71 @@
+package lending
+
+import (
+	"context"
+	"testing"
+
+	"lending-engine/blockchain"
+	"lending-engine/internal/merkle"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"go.uber.org/zap"
+)
+
+func TestReservesReporterProofsRebuildRoot(t *testing.T) {
+	chain := newTestChain(t)
+	chain.deposit(t, chain.chain.Address, 6)
+	repository := newMemRepository()
+	// three wallets, so the last leaf is carried up an odd level.
+	repository.addWallet(1, 1, 0)
+	repository.addWallet(2, 2, 0)
+	repository.addWallet(3, 3, 0)
+	var alerted alerts
+	signMessageFn := func(message string) (string, string, error) {
+		return "0xsignature", "0xsigner", nil
+	}
+	reporter := NewReservesReporter(repository, blockchain.NewListChainFn(chain.registry), blockchain.NewQueryTokenBalanceClientFn(chain.registry), signMessageFn, alerted.alertOpsFn(), zap.NewNop())
+
+	if err := reporter.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	report, err := repository.QueryLatestReserveReportRepo(context.Background())
+	if err != nil || report == nil {
+		t.Fatalf("report = %v, %v", report, err)
+	}
+	liabilities, err := repository.QueryReserveLiabilitiesRepo(context.Background(), *report.Root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*liabilities) != 3 {
+		t.Fatalf("%d liabilities saved with the report, want 3", len(*liabilities))
+	}
+	leaves := make([][]byte, 0, len(*liabilities))
+	for _, liability := range *liabilities {
+		leaves = append(leaves, merkle.Leaf([]byte(ReserveLeafData(*liability.AccountID, *liability.BTCVolume, *liability.ETHVolume, *liability.Salt))))
+	}
+	if root := hexutil.Encode(merkle.New(leaves).Root()); root != *report.Root {
+		t.Fatalf("root rebuilt from liabilities = %s, want %s", root, *report.Root)
+	}
+	if len(alerted) != 0 {
+		t.Errorf("alerts = %v, want none with reserves equal to liabilities", alerted)
+	}
+
+	for accountId := 1; accountId <= 3; accountId++ {
+		var proof GetReserveProofResponse
+		get(t, newTestApp(repository, chain, accountId), "/reserves/proof", &proof)
+		leaf, err := hexutil.Decode(proof.Leaf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		root, err := hexutil.Decode(proof.Root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !merkle.Verify(leaf, proof.Proof, root) || proof.BTCVolume != float64(accountId) {
+			t.Errorf("proof of account %d with %f BTC doesn't verify", accountId, proof.BTCVolume)
+		}
+		if hexutil.Encode(merkle.Leaf([]byte(proof.LeafData))) != proof.Leaf {
+			t.Errorf("leaf of account %d isn't the hash of its leaf data", accountId)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package lending
+
+import (
+	"context"
+	"fmt"
+	"lending-engine/blockchain"
+	"lending-engine/common"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// depositScanner credits token transfers sent to per-account deposit addresses, so users don't need to submit the deposit themselves.
+type depositScanner struct {
+	LendingRepository        LendingRepository
+	ListChainFn              blockchain.ListChainFn
+	QuerySafeBlockClientFn   blockchain.QuerySafeBlockClientFn
+	QueryTransferLogClientFn blockchain.QueryTransferLogClientFn
+	Logger                   *zap.Logger
+	mu                       sync.Mutex
+}
+
+func NewDepositScanner(lendingRepository LendingRepository, listChainFn blockchain.ListChainFn, querySafeBlockClientFn blockchain.QuerySafeBlockClientFn, queryTransferLogClientFn blockchain.QueryTransferLogClientFn, logger *zap.Logger) *depositScanner {
+	return &depositScanner{
+		LendingRepository:        lendingRepository,
+		ListChainFn:              listChainFn,
+		QuerySafeBlockClientFn:   querySafeBlockClientFn,
+		QueryTransferLogClientFn: queryTransferLogClientFn,
+		Logger:                   logger,
+	}
+}
+
+// Run scans every chain from its last scanned block up to the latest confirmed block, at most "deposit.scan-max-blocks" blocks per chain.
+func (d *depositScanner) Run(ctx context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	depositAddresses, err := d.LendingRepository.QueryDepositAddressesRepo(ctx)
+	if err != nil {
+		return err
+	}
+	accounts := make(map[string]int)
+	recipients := make([]string, 0, len(*depositAddresses))
+	for _, depositAddress := range *depositAddresses {
+		accounts[strings.ToLower(*depositAddress.Address)] = *depositAddress.AccountID
+		recipients = append(recipients, *depositAddress.Address)
+	}
+
+	for _, chain := range d.ListChainFn() {
+		if err := d.scan(ctx, chain.ChainID, accounts, recipients); err != nil {
+			d.Logger.Error(fmt.Sprintf("ChainID: %d | %s", chain.ChainID, err.Error()))
+		}
+	}
+	return nil
+}
+
+func (d *depositScanner) scan(ctx context.Context, chainId int, accounts map[string]int, recipients []string) error {
+	safeBlock, err := d.QuerySafeBlockClientFn(ctx, chainId)
+	if err != nil {
+		return err
+	}
+	lastBlock, err := d.LendingRepository.QueryDepositScanRepo(ctx, chainId)
+	if err != nil {
+		return err
+	}
+	if lastBlock == nil {
+		// first run on this chain starts from now, older deposits are submitted by hand.
+		_, err := d.LendingRepository.UpsertDepositScanRepo(ctx, chainId, safeBlock, time.Now().Format(common.DateYYYYMMDDHHMMSSFormat))
+		return err
+	}
+	fromBlock := *lastBlock + 1
+	toBlock := safeBlock
+	if maxBlocks := viper.GetInt64("deposit.scan-max-blocks"); toBlock-fromBlock+1 > maxBlocks {
+		toBlock = fromBlock + maxBlocks - 1
+	}
+	if fromBlock > toBlock {
+		return nil
+	}
+
+	transferLogs, err := d.QueryTransferLogClientFn(ctx, chainId, fromBlock, toBlock, recipients)
+	if err != nil {
+		return err
+	}
+	for _, transferLog := range mergeTransferLogs(*transferLogs) {
+		accountId, ok := accounts[strings.ToLower(transferLog.To)]
+		if !ok {
+			continue
+		}
+		if err := d.credit(ctx, chainId, accountId, transferLog); err != nil {
+			return err
+		}
+	}
+	_, err = d.LendingRepository.UpsertDepositScanRepo(ctx, chainId, toBlock, time.Now().Format(common.DateYYYYMMDDHHMMSSFormat))
+	return err
+}
+
+func (d *depositScanner) credit(ctx context.Context, chainId int, accountId int, transferLog blockchain.TransferLog) error {
+	txns, err := d.LendingRepository.QueryWalletTransactionRepo(ctx, map[string]interface{}{
+		"txn_hash":        transferLog.TxnHash,
+		"txn_type":        common.DepositStatus,
+		"collateral_type": transferLog.CollateralType,
+	})
+	if err != nil {
+		return err
+	}
+	for _, txn := range *txns {
+		if *txn.Status != common.RejectStatus && *txn.Status != common.OrphanedStatus {
+			d.Logger.Info(fmt.Sprintf("Txn Hash: %s has already been recorded.", transferLog.TxnHash))
+			return nil
+		}
+	}
+
+	wallet, err := d.LendingRepository.QueryWalletRepo(ctx, accountId)
+	if err != nil {
+		return err
+	}
+	if wallet == nil {
+		return fmt.Errorf("wallet of account %d doesn't exist", accountId)
+	}
+	btc, eth, err := collateralVolumes(*wallet, transferLog.CollateralType, transferLog.Amount)
+	if err != nil {
+		return err
+	}
+	// nothing is recorded when the credit fails, so the block range is scanned again next run.
+	depositId, err := d.LendingRepository.CreditDepositRepo(ctx, accountId, transferLog.From, transferLog.To, chainId, transferLog.TxnHash, transferLog.CollateralType, transferLog.Amount, transferLog.Block, transferLog.BlockHash, btc, eth, time.Now().Format(common.DateYYYYMMDDHHMMSSFormat))
+	if err != nil {
+		return err
+	}
+	d.Logger.Info(fmt.Sprintf("TxnID: %d - Status: %s | AccountID: %d - BTC: %f - ETH: %f", depositId, common.ConfirmStatus, accountId, btc, eth))
+	return nil
+}
+
+// mergeTransferLogs sums transfers of the same token to the same address within one transaction, since a deposit is recorded once per txn hash and collateral type.
+func mergeTransferLogs(transferLogs []blockchain.TransferLog) []blockchain.TransferLog {
+	merged := make([]blockchain.TransferLog, 0, len(transferLogs))
+	index := make(map[string]int)
+	for _, transferLog := range transferLogs {
+		key := fmt.Sprintf("%s|%s|%s", transferLog.TxnHash, transferLog.CollateralType, strings.ToLower(transferLog.To))
+		if i, ok := index[key]; ok {
+			merged[i].Amount += transferLog.Amount
+			continue
+		}
+		index[key] = len(merged)
+		merged = append(merged, transferLog)
+	}
+	return merged
+}
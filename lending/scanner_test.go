@@ -0,0 +1,80 @@
+package lending
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"lending-engine/blockchain"
+	"lending-engine/common"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+func TestDepositScannerCreditsOnlyWithWallet(t *testing.T) {
+	viper.Set("deposit.scan-max-blocks", 100)
+	defer viper.Set("deposit.scan-max-blocks", nil)
+
+	chain := newTestChain(t)
+	repository := newMemRepository()
+	repository.addWallet(12, 0, 0)
+	depositAddress := "0x00000000000000000000000000000000000000d1"
+	repository.addDepositAddress(12, depositAddress)
+	scanner := NewDepositScanner(
+		repository,
+		blockchain.NewListChainFn(chain.registry),
+		blockchain.NewQuerySafeBlockClientFn(chain.registry),
+		blockchain.NewQueryTransferLogClientFn(chain.registry),
+		zap.NewNop(),
+	)
+	ctx := context.Background()
+
+	// the first run only records where scanning starts.
+	if err := scanner.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	tx := chain.deposit(t, depositAddress, 2)
+	block, blockHash := chain.blockOf(t, tx)
+
+	repository.walletErr = errors.New("connection reset")
+	if err := scanner.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	deposits, err := repository.QueryWalletTransactionRepo(ctx, map[string]interface{}{"txn_hash": tx.Hash().Hex()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*deposits) != 0 {
+		t.Fatalf("deposit is recorded as %s although the credit failed", *(*deposits)[0].Status)
+	}
+
+	// the same blocks are scanned again once the wallet can be written.
+	repository.walletErr = nil
+	if err := scanner.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	deposits, err = repository.QueryWalletTransactionRepo(ctx, map[string]interface{}{"txn_hash": tx.Hash().Hex()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*deposits) != 1 {
+		t.Fatalf("%d deposits recorded, want 1", len(*deposits))
+	}
+	deposit := (*deposits)[0]
+	if *deposit.Status != common.ConfirmStatus || *deposit.BlockNumber != block || *deposit.BlockHash != blockHash {
+		t.Errorf("deposit is %s in block %d (%s), want %s in %d (%s)", *deposit.Status, *deposit.BlockNumber, *deposit.BlockHash, common.ConfirmStatus, block, blockHash)
+	}
+	if got := repository.wallet(t, 12); *got.BTCVolume != 2 {
+		t.Errorf("wallet btc = %f, want 2", *got.BTCVolume)
+	}
+
+	// a later scan doesn't credit it twice.
+	chain.Commit()
+	if err := scanner.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := repository.wallet(t, 12); *got.BTCVolume != 2 {
+		t.Errorf("wallet btc after another scan = %f, want 2", *got.BTCVolume)
+	}
+}
@@ -0,0 +1,362 @@
+package lending
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"lending-engine/common"
+	"lending-engine/internal/handler"
+	"lending-engine/internal/redis"
+	"lending-engine/oracle"
+	"lending-engine/response"
+	"lending-engine/risk"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// positionStreamer pushes prices and positions to GET /stream clients. It subscribes to oracle.PriceChannel and WalletChannel,
+// so a price or a balance change on any engine instance reaches the clients of every instance.
+type positionStreamer struct {
+	LendingRepository   LendingRepository
+	GetPriceFn          oracle.GetPriceFn
+	GetRiskParametersFn risk.GetParametersFn
+	SubscribeRedisFn    redis.SubscribeRedisFn
+	Logger              *zap.Logger
+	mu                  sync.RWMutex
+	prices              map[string]oracle.Price
+	clients             map[*streamClient]struct{}
+	done                chan struct{}
+}
+
+// streamClient is signalled on prices when any price changes and on wallet when its account changes.
+// Both hold one signal, so a slow client skips to the latest state instead of queueing.
+type streamClient struct {
+	accountId int
+	prices    chan struct{}
+	wallet    chan struct{}
+}
+
+func NewPositionStreamer(lendingRepository LendingRepository, getPriceFn oracle.GetPriceFn, getRiskParametersFn risk.GetParametersFn, subscribeRedisFn redis.SubscribeRedisFn, logger *zap.Logger) *positionStreamer {
+	return &positionStreamer{
+		LendingRepository:   lendingRepository,
+		GetPriceFn:          getPriceFn,
+		GetRiskParametersFn: getRiskParametersFn,
+		SubscribeRedisFn:    subscribeRedisFn,
+		Logger:              logger,
+		prices:              make(map[string]oracle.Price),
+		clients:             make(map[*streamClient]struct{}),
+		done:                make(chan struct{}),
+	}
+}
+
+// Run subscribes until ctx is done, resubscribing after "stream.reconnect-delay" when redis drops the subscription.
+// Every client reloads its position after a resubscription since wallet changes may have been missed. Open streams end with Run.
+func (s *positionStreamer) Run(ctx context.Context) {
+	defer close(s.done)
+	for {
+		messages, err := s.SubscribeRedisFn(ctx, oracle.PriceChannel, WalletChannel)
+		if err != nil {
+			s.Logger.Error(fmt.Sprintf("subscribe: %s", err.Error()))
+		} else {
+			s.Logger.Info(fmt.Sprintf("subscribed to %s, %s", oracle.PriceChannel, WalletChannel))
+			s.notify(func(*streamClient) bool { return true }, true)
+			for message := range messages {
+				s.dispatch(message)
+			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		s.Logger.Error("subscription lost")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(viper.GetDuration("stream.reconnect-delay")):
+		}
+	}
+}
+
+func (s *positionStreamer) dispatch(message redis.Message) {
+	switch message.Channel {
+	case oracle.PriceChannel:
+		var price oracle.Price
+		if err := json.Unmarshal(message.Data, &price); err != nil {
+			s.Logger.Error(fmt.Sprintf("%s: %s", message.Channel, err.Error()))
+			return
+		}
+		s.mu.Lock()
+		last, ok := s.prices[price.Asset]
+		if ok && oracle.SamePrice(&last, &price) {
+			s.mu.Unlock()
+			return
+		}
+		s.prices[price.Asset] = price
+		s.mu.Unlock()
+		s.notify(func(*streamClient) bool { return true }, false)
+	case WalletChannel:
+		accountId, err := strconv.Atoi(string(message.Data))
+		if err != nil {
+			s.Logger.Error(fmt.Sprintf("%s: %s", message.Channel, err.Error()))
+			return
+		}
+		s.notify(func(client *streamClient) bool { return client.accountId == accountId }, true)
+	}
+}
+
+func (s *positionStreamer) notify(match func(*streamClient) bool, wallet bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for client := range s.clients {
+		if !match(client) {
+			continue
+		}
+		signal := client.prices
+		if wallet {
+			signal = client.wallet
+		}
+		select {
+		case signal <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (s *positionStreamer) register(accountId int) *streamClient {
+	client := &streamClient{
+		accountId: accountId,
+		prices:    make(chan struct{}, 1),
+		wallet:    make(chan struct{}, 1),
+	}
+	s.mu.Lock()
+	s.clients[client] = struct{}{}
+	s.mu.Unlock()
+	return client
+}
+
+func (s *positionStreamer) unregister(client *streamClient) {
+	s.mu.Lock()
+	delete(s.clients, client)
+	s.mu.Unlock()
+}
+
+// price returns the latest published price of asset, or asks the oracle before the first one arrives.
+func (s *positionStreamer) price(ctx context.Context, asset string) (*oracle.Price, error) {
+	s.mu.RLock()
+	price, ok := s.prices[asset]
+	s.mu.RUnlock()
+	if ok {
+		return &price, nil
+	}
+	return s.GetPriceFn(ctx, asset)
+}
+
+// StreamPosition
+// @Summary Stream Position
+// @Description server-sent events of "price" with every oracle.Price change and "position" with the user's collateral value, LTV and margin call state in THB
+// @Description whenever a price, the wallet or a contract changes. A comment line is sent every "stream.keepalive".
+// @Tags Lending
+// @Produce text/event-stream
+// @Success 200 {object} lending.Position "Success"
+// @Failure 500 {object} response.ErrResponse "Internal Server Error"
+// @Security ApiKeyAuth
+// @Router /stream [get]
+func (s *positionStreamer) StreamPosition(c *handler.Ctx) error {
+	bearer := c.Locals(common.JWTClaimsKey).(*jwt.Token)
+	claims := bearer.Claims.(jwt.MapClaims)
+	id := claims["accountId"].(float64)
+	accountId := int(id)
+
+	wallet, contracts, err := s.account(c.Context(), accountId)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+	}
+	if wallet == nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalOperation, "Wallet doesn't exist."))
+	}
+	prices := make(map[string]oracle.Price)
+	for _, asset := range []string{"BTC", "ETH"} {
+		price, err := s.price(c.Context(), asset)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalPrice, err.Error()))
+		}
+		prices[asset] = *price
+	}
+
+	client := s.register(accountId)
+	logger := c.Log()
+	logger.Info(fmt.Sprintf("AccountID: %d | stream opened", accountId))
+
+	c.Set(common.ContentType, common.TextEventStream)
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+	conn := c.Context().Conn()
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer s.unregister(client)
+		defer logger.Info(fmt.Sprintf("AccountID: %d | stream closed", accountId))
+
+		keepalive := time.NewTicker(viper.GetDuration("stream.keepalive"))
+		defer keepalive.Stop()
+
+		for _, price := range prices {
+			if err := writeEvent(w, conn, "price", price); err != nil {
+				return
+			}
+		}
+		if err := s.writePosition(w, conn, logger, accountId, wallet, contracts, prices); err != nil {
+			return
+		}
+		for {
+			select {
+			case <-s.done:
+				return
+			case <-keepalive.C:
+				if err := writeKeepalive(w, conn); err != nil {
+					return
+				}
+			case <-client.prices:
+				s.mu.RLock()
+				var changed []oracle.Price
+				for asset, price := range s.prices {
+					if last, ok := prices[asset]; !ok || !oracle.SamePrice(&last, &price) {
+						prices[asset] = price
+						changed = append(changed, price)
+					}
+				}
+				s.mu.RUnlock()
+				if len(changed) == 0 {
+					continue
+				}
+				for _, price := range changed {
+					if err := writeEvent(w, conn, "price", price); err != nil {
+						return
+					}
+				}
+				if err := s.writePosition(w, conn, logger, accountId, wallet, contracts, prices); err != nil {
+					return
+				}
+			case <-client.wallet:
+				ctx, cancel := context.WithTimeout(context.Background(), viper.GetDuration("stream.query-timeout"))
+				latestWallet, latestContracts, err := s.account(ctx, accountId)
+				cancel()
+				if err != nil {
+					logger.Error(fmt.Sprintf("AccountID: %d | %s", accountId, err.Error()))
+					continue
+				}
+				if latestWallet == nil {
+					continue
+				}
+				wallet, contracts = latestWallet, latestContracts
+				if err := s.writePosition(w, conn, logger, accountId, wallet, contracts, prices); err != nil {
+					return
+				}
+			}
+		}
+	})
+	return nil
+}
+
+func (s *positionStreamer) account(ctx context.Context, accountId int) (*Wallet, *[]Contract, error) {
+	wallet, err := s.LendingRepository.QueryWalletRepo(ctx, accountId)
+	if err != nil {
+		return nil, nil, err
+	}
+	contracts, err := s.LendingRepository.QueryContractRepo(ctx, map[string]interface{}{"account_id": accountId})
+	if err != nil {
+		return nil, nil, err
+	}
+	return wallet, contracts, nil
+}
+
+// writePosition writes the position event with the live risk parameters. When redis can't give them the event is skipped
+// rather than ending the stream.
+func (s *positionStreamer) writePosition(w *bufio.Writer, conn net.Conn, logger *zap.Logger, accountId int, wallet *Wallet, contracts *[]Contract, prices map[string]oracle.Price) error {
+	btcRisk, ethRisk, err := riskParameters(s.GetRiskParametersFn)
+	if err != nil {
+		logger.Error(fmt.Sprintf("AccountID: %d | %s", accountId, err.Error()))
+		return nil
+	}
+	return writeEvent(w, conn, "position", newPosition(accountId, wallet, contracts, prices, btcRisk, ethRisk))
+}
+
+// writeEvent writes a server-sent event of v as JSON and flushes it, an error means the client is gone.
+// The server write timeout is set once per response, so every write pushes the deadline of conn forward instead.
+func writeEvent(w *bufio.Writer, conn net.Conn, event string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := conn.SetWriteDeadline(time.Now().Add(2 * viper.GetDuration("stream.keepalive"))); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, b); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// writeKeepalive writes a comment line that clients ignore, so proxies don't close an idle stream.
+func writeKeepalive(w *bufio.Writer, conn net.Conn) error {
+	if err := conn.SetWriteDeadline(time.Now().Add(2 * viper.GetDuration("stream.keepalive"))); err != nil {
+		return err
+	}
+	if _, err := w.WriteString(": keepalive\n\n"); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+func newPosition(accountId int, wallet *Wallet, contracts *[]Contract, prices map[string]oracle.Price, btcRisk *risk.Parameters, ethRisk *risk.Parameters) Position {
+	btc, eth := prices["BTC"], prices["ETH"]
+	position := Position{
+		AccountID: accountId,
+		BTCVolume: *wallet.BTCVolume,
+		ETHVolume: *wallet.ETHVolume,
+		BTCPrice:  btc.Price,
+		ETHPrice:  eth.Price,
+		Timestamp: btc.Timestamp,
+	}
+	if eth.Timestamp.Before(position.Timestamp) {
+		position.Timestamp = eth.Timestamp
+	}
+	position.CollateralValue = position.BTCVolume*btc.Price + position.ETHVolume*eth.Price
+	position.CreditLimit = position.BTCVolume*btc.Price*btcRisk.Haircut + position.ETHVolume*eth.Price*ethRisk.Haircut
+	position.MarginCallLimit = position.BTCVolume*btc.Price*btcRisk.MarginCallLTV + position.ETHVolume*eth.Price*ethRisk.MarginCallLTV
+	for _, contract := range *contracts {
+		if *contract.Status != common.ClosedStatus {
+			position.LoanOutstanding += *contract.LoanOutstanding
+		}
+	}
+	position.CreditAvailable = position.CreditLimit - position.LoanOutstanding
+	if position.CollateralValue > 0 {
+		position.LTV = position.LoanOutstanding / position.CollateralValue
+	}
+
+	position.MarginCall = MarginCallState{
+		Status:         common.MarginNoneStatus,
+		Date:           wallet.MarginCallDate,
+		LiquidateLimit: viper.GetInt("loan.liquidate-limit"),
+	}
+	switch {
+	case wallet.MarginCallDate != nil:
+		position.MarginCall.Status = common.MarginCalledStatus
+		if margin, err := time.ParseInLocation(time.RFC3339, *wallet.MarginCallDate, time.Local); err == nil {
+			position.MarginCall.Days = int(time.Since(margin).Hours() / 24)
+		}
+		if position.MarginCall.Days > position.MarginCall.LiquidateLimit {
+			position.MarginCall.Status = common.MarginLiquidatableStatus
+		}
+	case position.LoanOutstanding > position.MarginCallLimit:
+		position.MarginCall.Status = common.MarginAtRiskStatus
+	}
+	return position
+}
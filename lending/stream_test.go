@@ -0,0 +1,94 @@
+package lending
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"lending-engine/internal/redis"
+	"lending-engine/oracle"
+
+	"go.uber.org/zap"
+)
+
+// signalled drains signal and tells whether it held one.
+func signalled(signal chan struct{}) bool {
+	select {
+	case <-signal:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestPositionStreamerDispatchesWalletToItsAccount(t *testing.T) {
+	streamer := NewPositionStreamer(nil, nil, nil, nil, zap.NewNop())
+	first := streamer.register(1)
+	second := streamer.register(1)
+	other := streamer.register(2)
+	gone := streamer.register(1)
+	streamer.unregister(gone)
+
+	// two changes before the client reads coalesce into one signal.
+	streamer.dispatch(redis.Message{Channel: WalletChannel, Data: []byte("1")})
+	streamer.dispatch(redis.Message{Channel: WalletChannel, Data: []byte("1")})
+	for name, client := range map[string]*streamClient{"first": first, "second": second} {
+		if !signalled(client.wallet) {
+			t.Errorf("%s client of account 1 not signalled", name)
+		}
+		if signalled(client.wallet) || signalled(client.prices) {
+			t.Errorf("%s client of account 1 signalled more than once", name)
+		}
+	}
+	if signalled(other.wallet) || signalled(other.prices) {
+		t.Error("client of account 2 signalled by a wallet change of account 1")
+	}
+	if signalled(gone.wallet) {
+		t.Error("unregistered client signalled")
+	}
+
+	// an account id that isn't a number reaches nobody.
+	streamer.dispatch(redis.Message{Channel: WalletChannel, Data: []byte("account 2")})
+	if signalled(other.wallet) {
+		t.Error("client of account 2 signalled by a malformed wallet message")
+	}
+}
+
+func TestPositionStreamerDispatchesPriceToEveryClient(t *testing.T) {
+	streamer := NewPositionStreamer(nil, nil, nil, nil, zap.NewNop())
+	clients := []*streamClient{streamer.register(1), streamer.register(2)}
+	publish := func(price oracle.Price) {
+		t.Helper()
+		data, err := json.Marshal(price)
+		if err != nil {
+			t.Fatal(err)
+		}
+		streamer.dispatch(redis.Message{Channel: oracle.PriceChannel, Data: data})
+	}
+
+	timestamp := time.Date(2021, 1, 2, 12, 13, 14, 0, time.UTC)
+	publish(oracle.Price{Asset: "BTC", Price: 1000000, Timestamp: timestamp})
+	for _, client := range clients {
+		if !signalled(client.prices) || signalled(client.wallet) {
+			t.Errorf("client of account %d not signalled on prices alone", client.accountId)
+		}
+	}
+
+	// the same price published again by another instance is dropped.
+	publish(oracle.Price{Asset: "BTC", Price: 1000000, Timestamp: timestamp})
+	for _, client := range clients {
+		if signalled(client.prices) {
+			t.Errorf("client of account %d signalled by an unchanged price", client.accountId)
+		}
+	}
+
+	publish(oracle.Price{Asset: "BTC", Price: 1000100, Timestamp: timestamp.Add(time.Second)})
+	for _, client := range clients {
+		if !signalled(client.prices) {
+			t.Errorf("client of account %d not signalled by a price change", client.accountId)
+		}
+	}
+	if price := streamer.prices["BTC"]; price.Price != 1000100 {
+		t.Errorf("latest BTC price = %f, want 1000100", price.Price)
+	}
+}
@@ -0,0 +1,28 @@
+package lending
+
+import (
+	"lending-engine/common"
+	"lending-engine/oracle"
+
+	"github.com/spf13/viper"
+)
+
+// shockPrice moves price by shock in percent, -100 takes it to zero.
+func shockPrice(price oracle.Price, shock float64) oracle.Price {
+	price.Price = price.Price * (1 + shock/100)
+	return price
+}
+
+// stressStatus classifies a position at shocked prices by its LTV alone, so an account margin called today isn't counted
+// unless the shock keeps it over its limit: LIQUIDATABLE once the LTV reaches "stress.liquidation-ltv", CALLED over the
+// margin call limit and NONE otherwise.
+func stressStatus(position Position) string {
+	switch {
+	case position.LoanOutstanding > 0 && position.LoanOutstanding >= position.CollateralValue*viper.GetFloat64("stress.liquidation-ltv"):
+		return common.MarginLiquidatableStatus
+	case position.LoanOutstanding > position.MarginCallLimit:
+		return common.MarginCalledStatus
+	default:
+		return common.MarginNoneStatus
+	}
+}
@@ -0,0 +1,134 @@
+package lending
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"lending-engine/common"
+	"lending-engine/internal/handler"
+	"lending-engine/oracle"
+	"lending-engine/risk"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+func TestShockPrice(t *testing.T) {
+	price := oracle.Price{Asset: "BTC", Price: 1000000, Timestamp: time.Now()}
+	tests := []struct {
+		shock float64
+		want  float64
+	}{
+		{shock: 0, want: 1000000},
+		{shock: -30, want: 700000},
+		{shock: 25, want: 1250000},
+		{shock: -100, want: 0},
+	}
+	for _, tt := range tests {
+		shocked := shockPrice(price, tt.shock)
+		if math.Abs(shocked.Price-tt.want) > 1e-6 {
+			t.Errorf("shock %v%% = %f, want %f", tt.shock, shocked.Price, tt.want)
+		}
+		if shocked.Asset != price.Asset || !shocked.Timestamp.Equal(price.Timestamp) {
+			t.Errorf("shock %v%% changed %+v", tt.shock, shocked)
+		}
+	}
+}
+
+func TestStressTestAdminRequestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		shocks map[string]float64
+		err    bool
+	}{
+		{name: "price to zero", shocks: map[string]float64{"btc": -100}},
+		{name: "below -100", shocks: map[string]float64{"BTC": -100.01}, err: true},
+		{name: "rise", shocks: map[string]float64{"ETH": 50}},
+		{name: "unknown asset", shocks: map[string]float64{"XRP": -10}, err: true},
+		{name: "no shocks", shocks: map[string]float64{}, err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := StressTestAdminRequest{Shocks: tt.shocks}
+			if err := req.validate(); (err != nil) != tt.err {
+				t.Fatalf("err = %v, want error %t", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestStressTestAdminClassifiesShockedPositions(t *testing.T) {
+	viper.Set("stress.liquidation-ltv", 1)
+	defer viper.Set("stress.liquidation-ltv", nil)
+
+	repository := newMemRepository()
+	// margin called yesterday but repaid far below the shocked limit of 350,000.
+	repository.addWallet(1, 1, 0)
+	repository.addContract(1, 100000, common.OngoingStatus)
+	calledBefore := time.Now().AddDate(0, 0, -1).Format(time.RFC3339)
+	if _, err := repository.UpdateMarginCallRepo(context.Background(), 1, &calledBefore, ""); err != nil {
+		t.Fatal(err)
+	}
+	// within the limit of 500,000 today, over the shocked one.
+	repository.addWallet(2, 1, 0)
+	repository.addContract(2, 400000, common.OngoingStatus)
+	// over the shocked collateral value of 700,000.
+	repository.addWallet(3, 1, 0)
+	repository.addContract(3, 800000, common.OngoingStatus)
+
+	lendingHandler := &lendingHandler{
+		LendingRepository: repository,
+		GetPriceFn:        oracleOf(map[string]float64{"BTC": 1000000, "ETH": 50000}),
+		GetRiskParametersFn: func(asset string) (*risk.Parameters, error) {
+			return &risk.Parameters{Asset: asset, Haircut: 0.5, MarginCallLTV: 0.5}, nil
+		},
+	}
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Context().SetUserValue(common.LocaleKey, "en")
+		return c.Next()
+	})
+	app.Post("/admin/stress-test", handler.Helper(lendingHandler.StressTestAdmin, zap.NewNop()))
+
+	var stressed StressTestAdminResponse
+	post(t, app, "/admin/stress-test", StressTestAdminRequest{Shocks: map[string]float64{"BTC": -30}}, &stressed)
+	if stressed.BTCPrice.ShockedPrice != 700000 || stressed.ETHPrice.ShockedPrice != 50000 {
+		t.Errorf("shocked prices = %f and %f, want 700000 and 50000", stressed.BTCPrice.ShockedPrice, stressed.ETHPrice.ShockedPrice)
+	}
+	if stressed.Accounts != 3 || stressed.MarginCallAccounts != 1 || stressed.LiquidationAccounts != 1 {
+		t.Errorf("%d accounts, %d margin called and %d liquidatable, want 3, 1 and 1", stressed.Accounts, stressed.MarginCallAccounts, stressed.LiquidationAccounts)
+	}
+	want := []struct {
+		accountId     int
+		status        string
+		currentStatus string
+		shortfall     float64
+	}{
+		{accountId: 3, status: common.MarginLiquidatableStatus, currentStatus: common.MarginAtRiskStatus, shortfall: 450000},
+		{accountId: 2, status: common.MarginCalledStatus, currentStatus: common.MarginNoneStatus, shortfall: 50000},
+	}
+	if len(stressed.Positions) != len(want) {
+		t.Fatalf("%d positions, want %d", len(stressed.Positions), len(want))
+	}
+	for i, w := range want {
+		position := stressed.Positions[i]
+		if position.AccountID != w.accountId || position.MarginCall.Status != w.status || position.CurrentStatus != w.currentStatus {
+			t.Errorf("position %d is account %d %s (now %s), want account %d %s (now %s)", i, position.AccountID, position.MarginCall.Status, position.CurrentStatus, w.accountId, w.status, w.currentStatus)
+		}
+		if math.Abs(position.Shortfall-w.shortfall) > 1e-6 {
+			t.Errorf("account %d shortfall = %f, want %f", position.AccountID, position.Shortfall, w.shortfall)
+		}
+	}
+	if math.Abs(stressed.TotalUncovered-100000) > 1e-6 {
+		t.Errorf("total uncovered = %f, want 100000", stressed.TotalUncovered)
+	}
+
+	// at -100 the collateral is worth nothing and every loan is liquidatable.
+	post(t, app, "/admin/stress-test", StressTestAdminRequest{Shocks: map[string]float64{"BTC": -100}}, &stressed)
+	if stressed.BTCPrice.ShockedPrice != 0 || stressed.LiquidationAccounts != 3 {
+		t.Errorf("shocked price %f with %d liquidatable, want 0 with 3", stressed.BTCPrice.ShockedPrice, stressed.LiquidationAccounts)
+	}
+}
@@ -0,0 +1,175 @@
+package lending
+
+import (
+	"context"
+	"fmt"
+	"lending-engine/blockchain"
+	"lending-engine/common"
+	"lending-engine/mail"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// depositSweeper finds token balances of deposit addresses to consolidate into the chain's address (treasury) and records them
+// UNSIGNED for the offline signer, see GetUnsignedSweepAdmin. An address without gas is funded by the hot wallet first and
+// becomes UNSIGNED once funding is mined.
+type depositSweeper struct {
+	LendingRepository         LendingRepository
+	ListChainFn               blockchain.ListChainFn
+	QueryTokenBalanceClientFn blockchain.QueryTokenBalanceClientFn
+	QueryReceiptClientFn      blockchain.QueryReceiptClientFn
+	FundSweepGasClientFn      blockchain.FundSweepGasClientFn
+	AlertOpsFn                mail.AlertOpsFn
+	Logger                    *zap.Logger
+	mu                        sync.Mutex
+}
+
+func NewDepositSweeper(lendingRepository LendingRepository, listChainFn blockchain.ListChainFn, queryTokenBalanceClientFn blockchain.QueryTokenBalanceClientFn, queryReceiptClientFn blockchain.QueryReceiptClientFn, fundSweepGasClientFn blockchain.FundSweepGasClientFn, alertOpsFn mail.AlertOpsFn, logger *zap.Logger) *depositSweeper {
+	return &depositSweeper{
+		LendingRepository:         lendingRepository,
+		ListChainFn:               listChainFn,
+		QueryTokenBalanceClientFn: queryTokenBalanceClientFn,
+		QueryReceiptClientFn:      queryReceiptClientFn,
+		FundSweepGasClientFn:      fundSweepGasClientFn,
+		AlertOpsFn:                alertOpsFn,
+		Logger:                    logger,
+	}
+}
+
+// Run follows BROADCAST sweeps, then starts at most "sweeper.batch-size" sweeps over every chain and token.
+func (d *depositSweeper) Run(ctx context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	sweeps, err := d.LendingRepository.QuerySweepRepo(ctx, map[string]interface{}{
+		"status": common.BroadcastStatus,
+	})
+	if err != nil {
+		return err
+	}
+	for _, sweep := range *sweeps {
+		if err := d.track(ctx, sweep); err != nil {
+			d.Logger.Error(fmt.Sprintf("SweepID: %d | %s", *sweep.ID, err.Error()))
+		}
+	}
+
+	depositAddresses, err := d.LendingRepository.QueryDepositAddressesRepo(ctx)
+	if err != nil {
+		return err
+	}
+	batchSize := viper.GetInt("sweeper.batch-size")
+	started := 0
+	for _, chain := range d.ListChainFn() {
+		collateralTypes := make([]string, 0, len(chain.Tokens))
+		for collateralType := range chain.Tokens {
+			collateralTypes = append(collateralTypes, collateralType)
+		}
+		sort.Strings(collateralTypes)
+		for _, collateralType := range collateralTypes {
+			for _, depositAddress := range *depositAddresses {
+				if started >= batchSize {
+					return nil
+				}
+				ok, err := d.sweep(ctx, chain.ChainID, collateralType, depositAddress)
+				if err != nil {
+					d.Logger.Error(fmt.Sprintf("ChainID: %d | %s - Address: %s | %s", chain.ChainID, collateralType, *depositAddress.Address, err.Error()))
+					continue
+				}
+				if ok {
+					started++
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// sweep moves one address forward: waits for an open sweep, hands a sweep to the signer once funding is mined, or funds a balance
+// above dust. It returns true when a sweep was started.
+func (d *depositSweeper) sweep(ctx context.Context, chainId int, collateralType string, depositAddress DepositAddress) (bool, error) {
+	sweeps, err := d.LendingRepository.QuerySweepRepo(ctx, map[string]interface{}{
+		"chain_id":        chainId,
+		"address":         *depositAddress.Address,
+		"collateral_type": collateralType,
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, sweep := range *sweeps {
+		switch *sweep.Status {
+		case common.UnsignedStatus, common.BroadcastingStatus, common.BroadcastStatus:
+			return false, nil
+		case common.FundingStatus:
+			receipt, err := d.QueryReceiptClientFn(ctx, chainId, *sweep.GasTxnHash)
+			if err != nil {
+				return false, err
+			}
+			if !receipt.Found || receipt.Pending {
+				return false, nil
+			}
+			status := common.UnsignedStatus
+			if receipt.Status == 0 {
+				status = common.FailedStatus
+			}
+			if _, err := d.LendingRepository.UpdateSweepStatusRepo(ctx, *sweep.ID, common.FundingStatus, status, time.Now().Format(common.DateYYYYMMDDHHMMSSFormat)); err != nil {
+				return false, err
+			}
+			d.Logger.Info(fmt.Sprintf("SweepID: %d - Status: %s | ChainID: %d - Address: %s", *sweep.ID, status, chainId, *depositAddress.Address))
+			return false, nil
+		}
+	}
+
+	balance, err := d.QueryTokenBalanceClientFn(ctx, chainId, collateralType, []string{*depositAddress.Address})
+	if err != nil {
+		return false, err
+	}
+	if balance == 0 || balance < viper.GetFloat64(fmt.Sprintf("sweeper.dust.%s", strings.ToLower(collateralType))) {
+		return false, nil
+	}
+	gasTxnHash, err := d.FundSweepGasClientFn(ctx, chainId, collateralType, *depositAddress.Address)
+	if err != nil {
+		return false, err
+	}
+	status := common.UnsignedStatus
+	if gasTxnHash != "" {
+		status = common.FundingStatus
+	}
+	sweepId, err := d.LendingRepository.InsertSweepRepo(ctx, chainId, *depositAddress.AccountID, *depositAddress.Address, collateralType, balance, "", gasTxnHash, status)
+	if err != nil {
+		return true, err
+	}
+	d.Logger.Info(fmt.Sprintf("SweepID: %d - Status: %s | ChainID: %d - Address: %s | %s: %f - Gas Txn Hash: %s", sweepId, status, chainId, *depositAddress.Address, collateralType, balance, gasTxnHash))
+	return true, nil
+}
+
+// track marks a sweep MINED once confirmed. A reverted sweep is FAILED, its deposits are unlinked and ops are alerted.
+func (d *depositSweeper) track(ctx context.Context, sweep Sweep) error {
+	receipt, err := d.QueryReceiptClientFn(ctx, *sweep.ChainID, *sweep.TxnHash)
+	if err != nil {
+		return err
+	}
+	switch {
+	case receipt.Found && !receipt.Pending && receipt.Status == 0:
+		if _, err := d.LendingRepository.UpdateSweepRepo(ctx, *sweep.ID, *sweep.TxnHash, *sweep.Volume, common.FailedStatus, time.Now().Format(common.DateYYYYMMDDHHMMSSFormat)); err != nil {
+			return err
+		}
+		if _, err := d.LendingRepository.ClearDepositSweepRepo(ctx, *sweep.ID); err != nil {
+			return err
+		}
+		message := fmt.Sprintf("Sweep id %d of %f %s from %s on chain %d reverted, txn hash %s.", *sweep.ID, *sweep.Volume, *sweep.CollateralType, *sweep.Address, *sweep.ChainID, *sweep.TxnHash)
+		if err := d.AlertOpsFn(d.Logger, "Sweep failed", message); err != nil {
+			d.Logger.Error(err.Error())
+		}
+	case receipt.Confirmed:
+		if _, err := d.LendingRepository.UpdateSweepRepo(ctx, *sweep.ID, *sweep.TxnHash, *sweep.Volume, common.MinedStatus, time.Now().Format(common.DateYYYYMMDDHHMMSSFormat)); err != nil {
+			return err
+		}
+		d.Logger.Info(fmt.Sprintf("SweepID: %d - Status: %s | Block: %d", *sweep.ID, common.MinedStatus, receipt.Block))
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package lending
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"lending-engine/blockchain"
+	"lending-engine/blockchain/chaintest"
+	"lending-engine/common"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+func newTestDepositSweeper(repository LendingRepository, chain *testChain, alerted *alerts) *depositSweeper {
+	return NewDepositSweeper(
+		repository,
+		blockchain.NewListChainFn(chain.registry),
+		blockchain.NewQueryTokenBalanceClientFn(chain.registry),
+		blockchain.NewQueryReceiptClientFn(chain.registry),
+		blockchain.NewFundSweepGasClientFn(chain.registry, blockchain.NewExecutor(chain.hotWallet)),
+		alerted.alertOpsFn(),
+		zap.NewNop(),
+	)
+}
+
+// signOffline signs unsigned transaction the way the offline signer does, from its fields only.
+func signOffline(t *testing.T, unsigned blockchain.UnsignedTransaction, key *ecdsa.PrivateKey) string {
+	t.Helper()
+	gasTipCap, _ := new(big.Int).SetString(unsigned.MaxPriorityFeePerGas, 10)
+	gasFeeCap, _ := new(big.Int).SetString(unsigned.MaxFeePerGas, 10)
+	value, _ := new(big.Int).SetString(unsigned.Value, 10)
+	to := ethcommon.HexToAddress(unsigned.To)
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(int64(unsigned.ChainID))), &types.DynamicFeeTx{
+		ChainID:   big.NewInt(int64(unsigned.ChainID)),
+		Nonce:     unsigned.Nonce,
+		GasTipCap: gasTipCap,
+		GasFeeCap: gasFeeCap,
+		Gas:       unsigned.Gas,
+		To:        &to,
+		Value:     value,
+		Data:      hexutil.MustDecode(unsigned.Data),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hexutil.Encode(raw)
+}
+
+func TestDepositSweeperOfflineSigning(t *testing.T) {
+	viper.Set("sweeper.batch-size", 10)
+	defer viper.Set("sweeper.batch-size", nil)
+
+	chain := newTestChain(t)
+	repository := newMemRepository()
+	depositKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	depositAddress := crypto.PubkeyToAddress(depositKey.PublicKey).Hex()
+	repository.addDepositAddress(12, depositAddress)
+	tx := chain.deposit(t, depositAddress, 3)
+	depositId := repository.addTransaction(WalletTransaction{
+		AccountID:      intPtr(12),
+		Address:        stringPtr(chain.depositor.From.Hex()),
+		ToAddress:      stringPtr(depositAddress),
+		ChainID:        intPtr(testChainId),
+		TxnHash:        stringPtr(tx.Hash().Hex()),
+		CollateralType: stringPtr("BTC"),
+		Volume:         float64Ptr(3),
+		TxnType:        stringPtr(common.DepositStatus),
+		Status:         stringPtr(common.ConfirmStatus),
+	})
+	// the account also paid the chain's address, which the sweep doesn't move.
+	centralTx := chain.deposit(t, chain.chain.Address, 1)
+	centralId := repository.addTransaction(WalletTransaction{
+		AccountID:      intPtr(12),
+		Address:        stringPtr(chain.depositor.From.Hex()),
+		ToAddress:      stringPtr(chain.chain.Address),
+		ChainID:        intPtr(testChainId),
+		TxnHash:        stringPtr(centralTx.Hash().Hex()),
+		CollateralType: stringPtr("BTC"),
+		Volume:         float64Ptr(1),
+		TxnType:        stringPtr(common.DepositStatus),
+		Status:         stringPtr(common.ConfirmStatus),
+	})
+	var alerted alerts
+	sweeper := newTestDepositSweeper(repository, chain, &alerted)
+	ctx := context.Background()
+
+	// the deposit address has no ether, so the hot wallet funds its gas first.
+	if err := sweeper.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if sweep := repository.sweep(t, 1); *sweep.Status != common.FundingStatus || sweep.GasTxnHash == nil {
+		t.Fatalf("sweep status = %s, want %s with gas txn hash", *sweep.Status, common.FundingStatus)
+	}
+	if err := sweeper.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if sweep := repository.sweep(t, 1); *sweep.Status != common.UnsignedStatus {
+		t.Fatalf("sweep status = %s, want %s once funding is mined", *sweep.Status, common.UnsignedStatus)
+	}
+
+	app := newTestApp(repository, chain, 12)
+	var unsigned GetUnsignedSweepAdminResponse
+	get(t, app, "/admin/sweep/unsigned?id=1", &unsigned)
+	if unsigned.DerivationPath != blockchain.DerivationPath(12) {
+		t.Errorf("derivation path = %s, want %s", unsigned.DerivationPath, blockchain.DerivationPath(12))
+	}
+	if unsigned.Volume != 3 || unsigned.Transaction.From != depositAddress {
+		t.Errorf("unsigned sweep of %f from %s, want 3 from %s", unsigned.Volume, unsigned.Transaction.From, depositAddress)
+	}
+
+	// a transaction signed by another key is refused and the sweep can be signed again.
+	otherKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := postStatus(t, app, "/admin/sweep/signed", ImportSignedSweepAdminRequest{
+		ID:             1,
+		RawTransaction: signOffline(t, unsigned.Transaction, otherKey),
+	}); status != http.StatusBadRequest {
+		t.Fatalf("import of transaction signed by another key = %d, want %d", status, http.StatusBadRequest)
+	}
+	if sweep := repository.sweep(t, 1); *sweep.Status != common.UnsignedStatus {
+		t.Fatalf("sweep status = %s after refused import, want %s", *sweep.Status, common.UnsignedStatus)
+	}
+
+	var imported ImportSignedSweepAdminResponse
+	post(t, app, "/admin/sweep/signed", ImportSignedSweepAdminRequest{
+		ID:             1,
+		RawTransaction: signOffline(t, unsigned.Transaction, depositKey),
+	}, &imported)
+	if imported.Volume != 3 {
+		t.Errorf("swept volume = %f, want 3", imported.Volume)
+	}
+	if got := chain.balanceOf(t, chain.chain.Address); got.Cmp(chaintest.Wei(4)) != 0 {
+		t.Errorf("treasury balance = %s, want %s", got, chaintest.Wei(4))
+	}
+	sweep := repository.sweep(t, 1)
+	if *sweep.Status != common.BroadcastStatus || *sweep.TxnHash != imported.TxnHash {
+		t.Fatalf("sweep is %s in %s, want %s in %s", *sweep.Status, stringValue(sweep.TxnHash), common.BroadcastStatus, imported.TxnHash)
+	}
+	if deposit := repository.transaction(t, depositId); deposit.SweepID == nil || *deposit.SweepID != 1 {
+		t.Errorf("deposit isn't linked to sweep 1")
+	}
+	if deposit := repository.transaction(t, centralId); deposit.SweepID != nil {
+		t.Errorf("deposit paid to the chain's address is linked to sweep %d", *deposit.SweepID)
+	}
+
+	// the same sweep can't be imported twice.
+	if status := postStatus(t, app, "/admin/sweep/signed", ImportSignedSweepAdminRequest{
+		ID:             1,
+		RawTransaction: signOffline(t, unsigned.Transaction, depositKey),
+	}); status != http.StatusBadRequest {
+		t.Errorf("second import = %d, want %d", status, http.StatusBadRequest)
+	}
+
+	if err := sweeper.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if sweep := repository.sweep(t, 1); *sweep.Status != common.MinedStatus {
+		t.Errorf("sweep status = %s, want %s", *sweep.Status, common.MinedStatus)
+	}
+	if len(alerted) != 0 {
+		t.Errorf("alerts = %v, want none", alerted)
+	}
+}
@@ -0,0 +1,137 @@
+package lending
+
+import (
+	"context"
+	"fmt"
+	"lending-engine/blockchain"
+	"lending-engine/common"
+	"lending-engine/mail"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// withdrawTracker follows BROADCAST withdrawals until they are MINED or FAILED.
+type withdrawTracker struct {
+	LendingRepository    LendingRepository
+	QueryReceiptClientFn blockchain.QueryReceiptClientFn
+	AlertOpsFn           mail.AlertOpsFn
+	Logger               *zap.Logger
+	mu                   sync.Mutex
+	alerted              map[int]time.Time
+}
+
+func NewWithdrawTracker(lendingRepository LendingRepository, queryReceiptClientFn blockchain.QueryReceiptClientFn, alertOpsFn mail.AlertOpsFn, logger *zap.Logger) *withdrawTracker {
+	return &withdrawTracker{
+		LendingRepository:    lendingRepository,
+		QueryReceiptClientFn: queryReceiptClientFn,
+		AlertOpsFn:           alertOpsFn,
+		Logger:               logger,
+		alerted:              make(map[int]time.Time),
+	}
+}
+
+// Run checks every BROADCAST withdrawal once. It's meant to be scheduled by job.Start.
+func (t *withdrawTracker) Run(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	txns, err := t.LendingRepository.QueryWalletTransactionRepo(ctx, map[string]interface{}{
+		"txn_type": common.WithdrawStatus,
+		"status":   common.BroadcastStatus,
+	})
+	if err != nil {
+		return err
+	}
+	for _, txn := range *txns {
+		if err := t.track(ctx, txn); err != nil {
+			t.Logger.Error(fmt.Sprintf("TxnID: %d | %s", *txn.ID, err.Error()))
+		}
+	}
+	return nil
+}
+
+func (t *withdrawTracker) track(ctx context.Context, txn WalletTransaction) error {
+	receipt, err := t.QueryReceiptClientFn(ctx, *txn.ChainID, *txn.TxnHash)
+	if err != nil {
+		return err
+	}
+	switch {
+	case receipt.Found && !receipt.Pending && receipt.Status == 0:
+		return t.fail(ctx, txn)
+	case receipt.Confirmed:
+		if _, err := t.LendingRepository.UpdateWithdrawRepo(ctx, *txn.ID, *txn.TxnHash, common.MinedStatus, time.Now().Format(common.DateYYYYMMDDHHMMSSFormat)); err != nil {
+			return err
+		}
+		delete(t.alerted, *txn.ID)
+		t.Logger.Info(fmt.Sprintf("TxnID: %d - Status: %s | Block: %d", *txn.ID, common.MinedStatus, receipt.Block))
+	case receipt.Found && !receipt.Pending:
+		// mined but not enough confirmations yet.
+	default:
+		t.alertStuck(txn, receipt)
+	}
+	return nil
+}
+
+// fail marks a reverted withdrawal FAILED and gives the debited collateral back to the wallet in one transaction.
+// The withdraw fee line is kept because gas of the reverted transaction was still paid.
+func (t *withdrawTracker) fail(ctx context.Context, txn WalletTransaction) error {
+	wallet, err := t.LendingRepository.QueryWalletRepo(ctx, *txn.AccountID)
+	if err != nil {
+		return err
+	}
+	if wallet == nil {
+		return fmt.Errorf("wallet of account %d doesn't exist", *txn.AccountID)
+	}
+	btc, eth, err := collateralVolumes(*wallet, *txn.CollateralType, *txn.Volume)
+	if err != nil {
+		return err
+	}
+	rows, err := t.LendingRepository.FailWithdrawRepo(ctx, *txn.ID, *txn.AccountID, btc, eth, time.Now().Format(common.DateYYYYMMDDHHMMSSFormat))
+	if err != nil {
+		return err
+	}
+	delete(t.alerted, *txn.ID)
+	if rows != 1 {
+		return nil
+	}
+	t.Logger.Info(fmt.Sprintf("TxnID: %d - Status: %s | AccountID: %d - BTC: %f - ETH: %f", *txn.ID, common.FailedStatus, *txn.AccountID, btc, eth))
+
+	message := fmt.Sprintf("Withdraw id %d (%f %s of account %d) reverted on chain %d, txn hash %s. Collateral has been returned to the wallet.", *txn.ID, *txn.Volume, *txn.CollateralType, *txn.AccountID, *txn.ChainID, *txn.TxnHash)
+	if err := t.AlertOpsFn(t.Logger, "Withdraw failed", message); err != nil {
+		t.Logger.Error(err.Error())
+	}
+	return nil
+}
+
+// alertStuck tells ops about a pending or dropped withdrawal once it waits longer than "withdraw.stuck-after", and again every period after that.
+func (t *withdrawTracker) alertStuck(txn WalletTransaction, receipt *blockchain.ReceiptInfo) {
+	stuckAfter := viper.GetDuration("withdraw.stuck-after")
+	since := *txn.CreatedDatetime
+	if txn.UpdatedDatetime != nil {
+		since = *txn.UpdatedDatetime
+	}
+	if time.Since(wallClock(since)) < stuckAfter {
+		return
+	}
+	if last, ok := t.alerted[*txn.ID]; ok && time.Since(last) < stuckAfter {
+		return
+	}
+	t.alerted[*txn.ID] = time.Now()
+
+	state := "still pending"
+	if !receipt.Found {
+		state = "unknown to the node (dropped or replaced)"
+	}
+	message := fmt.Sprintf("Withdraw id %d on chain %d, txn hash %s, is %s since %s. Speed it up with /admin/withdraw/speedup or replace it manually.", *txn.ID, *txn.ChainID, *txn.TxnHash, state, since.Format(common.DateYYYYMMDDHHMMSSFormat))
+	if err := t.AlertOpsFn(t.Logger, "Withdraw stuck", message); err != nil {
+		t.Logger.Error(err.Error())
+	}
+}
+
+// wallClock reads a "timestamp without time zone" column as local time, the way it was written.
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.Local)
+}
@@ -0,0 +1,98 @@
+package lending
+
+import (
+	"context"
+	"testing"
+
+	"lending-engine/blockchain"
+	"lending-engine/blockchain/chaintest"
+	"lending-engine/common"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+)
+
+// revertedTransfer sends a token transfer the hot wallet can't pay, so it is mined reverted.
+func revertedTransfer(t *testing.T, chain *testChain) string {
+	t.Helper()
+	data, err := chaintest.TokenABI.Pack("transfer", ethcommon.HexToAddress("0x00000000000000000000000000000000000000B2"), chaintest.Wei(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := blockchain.NewExecutor(chain.hotWallet).SendWithGasLimit(context.Background(), chain, testChainId, chain.tokenAddr, nil, data, 100000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx.Hash().Hex()
+}
+
+func TestWithdrawTrackerRefundsReverted(t *testing.T) {
+	chain := newTestChain(t)
+	repository := newMemRepository()
+	// the wallet is already debited by the withdrawal.
+	repository.addWallet(1, 2, 0)
+	withdraw := WalletTransaction{
+		AccountID:      intPtr(1),
+		Address:        stringPtr("0x00000000000000000000000000000000000000B2"),
+		ChainID:        intPtr(testChainId),
+		TxnHash:        stringPtr(revertedTransfer(t, chain)),
+		CollateralType: stringPtr("BTC"),
+		Volume:         float64Ptr(1),
+		TxnType:        stringPtr(common.WithdrawStatus),
+		Status:         stringPtr(common.BroadcastStatus),
+	}
+	id := repository.addTransaction(withdraw)
+	withdraw.ID = intPtr(id)
+	var alerted alerts
+	tracker := NewWithdrawTracker(repository, blockchain.NewQueryReceiptClientFn(chain.registry), alerted.alertOpsFn(), zap.NewNop())
+	ctx := context.Background()
+
+	if err := tracker.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := repository.transaction(t, id); *got.Status != common.FailedStatus {
+		t.Fatalf("withdraw status = %s, want %s", *got.Status, common.FailedStatus)
+	}
+	if got := repository.wallet(t, 1); *got.BTCVolume != 3 {
+		t.Errorf("wallet btc = %f, want 3 after refund", *got.BTCVolume)
+	}
+	if len(alerted) != 1 {
+		t.Errorf("alerts = %v, want one", alerted)
+	}
+
+	// a tracker still holding the BROADCAST row doesn't refund it again.
+	if err := tracker.fail(ctx, withdraw); err != nil {
+		t.Fatal(err)
+	}
+	if got := repository.wallet(t, 1); *got.BTCVolume != 3 {
+		t.Errorf("wallet btc = %f after second fail, want 3", *got.BTCVolume)
+	}
+}
+
+func TestWithdrawTrackerKeepsBroadcastWithoutRefund(t *testing.T) {
+	chain := newTestChain(t)
+	repository := newMemRepository()
+	id := repository.addTransaction(WalletTransaction{
+		AccountID:      intPtr(1),
+		Address:        stringPtr("0x00000000000000000000000000000000000000B2"),
+		ChainID:        intPtr(testChainId),
+		TxnHash:        stringPtr(revertedTransfer(t, chain)),
+		CollateralType: stringPtr("BTC"),
+		Volume:         float64Ptr(1),
+		TxnType:        stringPtr(common.WithdrawStatus),
+		Status:         stringPtr(common.BroadcastStatus),
+	})
+	var alerted alerts
+	tracker := NewWithdrawTracker(repository, blockchain.NewQueryReceiptClientFn(chain.registry), alerted.alertOpsFn(), zap.NewNop())
+
+	// the wallet can't be refunded, so the withdrawal stays BROADCAST for the next run instead of FAILED without refund.
+	if err := tracker.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := repository.transaction(t, id); *got.Status != common.BroadcastStatus {
+		t.Errorf("withdraw status = %s, want %s", *got.Status, common.BroadcastStatus)
+	}
+	if len(alerted) != 0 {
+		t.Errorf("alerts = %v, want none", alerted)
+	}
+}
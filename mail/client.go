@@ -43,3 +43,58 @@ func NewRequestMailOtpClientFn(cli *client.Client) RequestMailOtpClientFn {
 		return nil
 	}
 }
+
+type RequestMailAlertClientFn func(logger *zap.Logger, xRequestID string, request *SendMailAlertClientRequest) error
+
+func NewRequestMailAlertClientFn(cli *client.Client) RequestMailAlertClientFn {
+	return func(logger *zap.Logger, xRequestID string, request *SendMailAlertClientRequest) error {
+		byteRequest, err := json.Marshal(&request)
+		if err != nil {
+			return err
+		}
+		m := make(map[string]string, 0)
+		clientRequest := client.Request{
+			URL:                 viper.GetString("client.email-api.alert.url"),
+			Method:              http.MethodPost,
+			XRequestID:          xRequestID,
+			Header:              m,
+			HideLogRequestBody:  viper.GetBool("client.hidebody"),
+			HideLogResponseBody: viper.GetBool("client.hidebody"),
+			Logger:              logger,
+			Body:                byteRequest,
+		}
+		clientResponse, err := cli.Do(&clientRequest)
+		if err != nil {
+			return err
+		}
+		var sendMailAlertClientResult SendMailAlertClientResult
+		if err := json.Unmarshal(clientResponse.Body, &sendMailAlertClientResult); err != nil {
+			return err
+		}
+		if sendMailAlertClientResult.Code != 2000 {
+			return fmt.Errorf("%s(%s)", sendMailAlertClientResult.Title, sendMailAlertClientResult.Description)
+		}
+		return nil
+	}
+}
+
+// AlertOpsFn logs the alert and mails it to every address in "client.email-api.alert.to".
+type AlertOpsFn func(logger *zap.Logger, title string, message string) error
+
+func NewAlertOpsFn(requestMailAlertClientFn RequestMailAlertClientFn) AlertOpsFn {
+	return func(logger *zap.Logger, title string, message string) error {
+		logger.Warn(fmt.Sprintf("%s: %s", title, message))
+		sendMailAlertClientRequest := SendMailAlertClientRequest{
+			From:     viper.GetString("client.email-api.account"),
+			To:       viper.GetStringSlice("client.email-api.alert.to"),
+			Subject:  title,
+			Template: viper.GetString("client.email-api.alert.template"),
+			Body: BodySendMailAlertClient{
+				Title:   title,
+				Message: message,
+			},
+			Auth: true,
+		}
+		return requestMailAlertClientFn(logger, "", &sendMailAlertClientRequest)
+	}
+}
@@ -30,3 +30,23 @@ type SendMailOtpClientResult struct {
 	Title       string `json:"title" example:"Success."`
 	Description string `json:"description" example:"Please contact administrator for more information."`
 }
+
+type SendMailAlertClientRequest struct {
+	From     string                  `json:"from" example:"Treasury.Admin@gmail.com"`
+	To       []string                `json:"to" example:"[ops@icfin.finance]"`
+	Subject  string                  `json:"subject" example:"Withdrawal is stuck"`
+	Template string                  `json:"template" example:"alert.html"`
+	Body     BodySendMailAlertClient `json:"body"`
+	Auth     bool                    `json:"auth" example:"true"`
+}
+
+type BodySendMailAlertClient struct {
+	Title   string `json:"title" example:"Withdrawal is stuck"`
+	Message string `json:"message" example:"TxnID: 1 has been broadcast for 30m0s without receipt."`
+}
+
+type SendMailAlertClientResult struct {
+	Code        uint64 `json:"code" example:"2000"`
+	Title       string `json:"title" example:"Success."`
+	Description string `json:"description" example:"Please contact administrator for more information."`
+}
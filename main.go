@@ -1,18 +1,25 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"lending-engine/account"
+	"lending-engine/bitcoin"
 	"lending-engine/blockchain"
 	"lending-engine/client"
 	"lending-engine/docs"
+	"lending-engine/ingester"
 	"lending-engine/internal/database"
 	"lending-engine/internal/handler"
+	"lending-engine/internal/job"
 	"lending-engine/internal/redis"
 	"lending-engine/lending"
 	"lending-engine/logz"
 	"lending-engine/mail"
 	"lending-engine/middleware"
+	"lending-engine/oracle"
+	"lending-engine/risk"
+	"lending-engine/treasury"
 	"lending-engine/version"
 	"log"
 	"os"
@@ -24,7 +31,6 @@ import (
 	_ "time/tzdata"
 
 	swagger "github.com/arsmn/fiber-swagger/v2"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/gofiber/fiber/v2"
 	"github.com/spf13/viper"
 )
@@ -52,7 +58,13 @@ var isReady bool
 // @securityDefinitions.apikey ApiKeyAuth
 // @in header
 // @name Authorization
+// @securityDefinitions.basic AdminAuth
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "ingest" {
+		runIngester()
+		return
+	}
+
 	timeout := viper.GetDuration("app.timeout")
 
 	app := fiber.New(fiber.Config{
@@ -84,14 +96,35 @@ func main() {
 
 	httpClient := client.NewClient()
 
-	ethClient, err := ethclient.Dial(viper.GetString("blockchain.ethereum.rpc"))
+	chainRegistry, err := blockchain.NewChainRegistry()
 	if err != nil {
-		logger.Error(err.Error())
+		logger.Fatal(err.Error())
 	}
+	defer chainRegistry.Close()
 
-	bscClient, err := ethclient.Dial(viper.GetString("blockchain.binance.rpc"))
+	executor, err := blockchain.NewExecutorFromKeystore()
 	if err != nil {
-		logger.Error(err.Error())
+		logger.Fatal(err.Error())
+	}
+
+	reserveSigner, err := blockchain.NewSignerFromKeystore("reserves.signer")
+	if err != nil {
+		logger.Fatal(err.Error())
+	}
+
+	addressDeriver, err := blockchain.NewAddressDeriverFromConfig()
+	if err != nil {
+		logger.Fatal(err.Error())
+	}
+
+	priceOracle, err := oracle.NewPriceOracleFromConfig("oracle", redis.NewGetFloatDataRedisFn(pool), blockchain.NewQueryLatestRoundClientFn(chainRegistry))
+	if err != nil {
+		logger.Fatal(err.Error())
+	}
+
+	fxOracle, err := oracle.NewPriceOracleFromConfig("fx", redis.NewGetFloatDataRedisFn(pool), blockchain.NewQueryLatestRoundClientFn(chainRegistry))
+	if err != nil {
+		logger.Fatal(err.Error())
 	}
 
 	middle := middleware.NewMiddleware(
@@ -126,10 +159,28 @@ func main() {
 		mail.NewRequestMailOtpClientFn(httpClient),
 	)
 
+	lendingRepository := lending.NewPublishingRepository(lending.NewLendingRepositoryDB(postgresDB), redis.NewPublishRedisFn(pool), logger)
+
 	lendingHandler := lending.NewLendingHandler(
-		lending.NewLendingRepositoryDB(postgresDB),
-		blockchain.NewQueryTransactionClientFn(ethClient, bscClient),
-		redis.NewGetFloatDataRedisFn(pool),
+		lendingRepository,
+		blockchain.NewGetChainFn(chainRegistry),
+		blockchain.NewQueryTransactionClientFn(chainRegistry),
+		blockchain.NewQueryReceiptClientFn(chainRegistry),
+		blockchain.NewTransferTokenClientFn(chainRegistry, executor),
+		blockchain.NewSpeedUpTransactionClientFn(chainRegistry, executor),
+		blockchain.NewQueryGasCostClientFn(chainRegistry),
+		blockchain.NewBuildUnsignedTransferClientFn(chainRegistry),
+		blockchain.NewBroadcastSignedTransferClientFn(chainRegistry),
+		blockchain.NewBuildUnsignedSweepClientFn(chainRegistry),
+		blockchain.NewBroadcastSignedSweepClientFn(chainRegistry),
+		blockchain.NewQueryHotWalletBalanceClientFn(chainRegistry, executor),
+		blockchain.NewMultisendTokenClientFn(chainRegistry, executor),
+		blockchain.NewDeriveAddressFn(addressDeriver),
+		bitcoin.NewQueryTransactionClientFn(bitcoin.NewRPCClientFromConfig()),
+		oracle.NewGetPriceFn(priceOracle),
+		oracle.NewCheckBreakerFn(redis.NewGetStructDataRedisFn(pool)),
+		oracle.NewGetPriceFn(fxOracle),
+		risk.NewGetParametersFn(redis.NewGetStructDataRedisFn(pool)),
 		lending.NewRequestLiquidationClientFn(httpClient),
 	)
 
@@ -142,8 +193,156 @@ func main() {
 		mail.NewRequestMailOtpClientFn(httpClient),
 	)
 
+	var hotWallet string
+	if executor != nil {
+		hotWallet = executor.Address().Hex()
+	}
+	treasurySnapshotFn := treasury.NewSnapshotFn(
+		treasury.NewTreasuryRepositoryDB(postgresDB),
+		blockchain.NewListChainFn(chainRegistry),
+		blockchain.NewQueryTokenBalanceClientFn(chainRegistry),
+		hotWallet,
+	)
+	treasuryHandler := treasury.NewTreasuryHandler(treasurySnapshotFn)
+
+	oracleHandler := oracle.NewOracleHandler(
+		oracle.NewHistoryRepositoryDB(postgresDB),
+		oracle.NewListBreakerFn(redis.NewGetStructDataRedisFn(pool)),
+		oracle.NewOverrideBreakerFn(redis.NewSetStructWExpireRedisFn(pool), redis.NewDeleteDataRedisFn(pool)),
+	)
+
+	riskHandler := risk.NewRiskHandler(
+		risk.NewRiskRepositoryDB(postgresDB),
+		risk.NewGetParametersFn(redis.NewGetStructDataRedisFn(pool)),
+		risk.NewSetParametersFn(redis.NewSetDataNoExpireRedisFn(pool)),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	alertOpsFn := mail.NewAlertOpsFn(mail.NewRequestMailAlertClientFn(httpClient))
+
+	withdrawTracker := lending.NewWithdrawTracker(
+		lendingRepository,
+		blockchain.NewQueryReceiptClientFn(chainRegistry),
+		alertOpsFn,
+		logger,
+	)
+	job.Start(ctx, logger, "withdraw-tracker", viper.GetDuration("withdraw.tracker-interval"), withdrawTracker.Run)
+
+	reorgWatcher := lending.NewReorgWatcher(
+		lendingRepository,
+		blockchain.NewListChainFn(chainRegistry),
+		blockchain.NewQuerySafeBlockClientFn(chainRegistry),
+		blockchain.NewQueryBlockHashClientFn(chainRegistry),
+		blockchain.NewQueryReceiptClientFn(chainRegistry),
+		alertOpsFn,
+		logger,
+	)
+	job.Start(ctx, logger, "reorg-watcher", viper.GetDuration("deposit.reorg-interval"), reorgWatcher.Run)
+
+	if addressDeriver != nil {
+		depositScanner := lending.NewDepositScanner(
+			lendingRepository,
+			blockchain.NewListChainFn(chainRegistry),
+			blockchain.NewQuerySafeBlockClientFn(chainRegistry),
+			blockchain.NewQueryTransferLogClientFn(chainRegistry),
+			logger,
+		)
+		job.Start(ctx, logger, "deposit-scanner", viper.GetDuration("deposit.scanner-interval"), depositScanner.Run)
+	}
+
+	if viper.GetBool("sweeper.enabled") {
+		depositSweeper := lending.NewDepositSweeper(
+			lendingRepository,
+			blockchain.NewListChainFn(chainRegistry),
+			blockchain.NewQueryTokenBalanceClientFn(chainRegistry),
+			blockchain.NewQueryReceiptClientFn(chainRegistry),
+			blockchain.NewFundSweepGasClientFn(chainRegistry, executor),
+			alertOpsFn,
+			logger,
+		)
+		job.Start(ctx, logger, "deposit-sweeper", viper.GetDuration("sweeper.interval"), depositSweeper.Run)
+	}
+
+	if viper.GetBool("ingester.enabled") {
+		priceIngester, err := ingester.NewIngesterFromConfig(ingester.NewStorePriceFn(redis.NewSetDataNoExpireRedisFn(pool)), logger)
+		if err != nil {
+			logger.Fatal(err.Error())
+		}
+		go priceIngester.Run(ctx)
+	}
+
+	breakerMonitor := oracle.NewBreakerMonitor(
+		oracle.NewGetPriceFn(priceOracle),
+		redis.NewGetStructDataRedisFn(pool),
+		redis.NewSetStructWExpireRedisFn(pool),
+		alertOpsFn,
+		logger,
+	)
+	job.Start(ctx, logger, "breaker-monitor", viper.GetDuration("breaker.interval"), breakerMonitor.Run)
+
+	pricePublisher := oracle.NewPricePublisher(
+		oracle.NewGetPriceFn(priceOracle),
+		redis.NewPublishRedisFn(pool),
+		logger,
+	)
+	job.Start(ctx, logger, "price-publisher", viper.GetDuration("stream.price-interval"), pricePublisher.Run)
+
+	positionStreamer := lending.NewPositionStreamer(
+		lendingRepository,
+		oracle.NewGetPriceFn(priceOracle),
+		risk.NewGetParametersFn(redis.NewGetStructDataRedisFn(pool)),
+		redis.NewSubscribeRedisFn(pool, viper.GetDuration("stream.redis-heartbeat")),
+		logger,
+	)
+	go positionStreamer.Run(ctx)
+
+	priceRecorder := oracle.NewPriceRecorder(
+		oracle.NewHistoryRepositoryDB(postgresDB),
+		oracle.NewGetPriceFn(priceOracle),
+		oracle.NewGetPriceFn(fxOracle),
+		logger,
+	)
+	job.Start(ctx, logger, "price-recorder", viper.GetDuration("oracle.history.interval"), priceRecorder.Run)
+
+	haircutRecommender := risk.NewHaircutRecommender(
+		risk.NewRiskRepositoryDB(postgresDB),
+		oracle.NewHistoryRepositoryDB(postgresDB),
+		risk.NewGetParametersFn(redis.NewGetStructDataRedisFn(pool)),
+		alertOpsFn,
+		logger,
+	)
+	job.Start(ctx, logger, "haircut-recommender", viper.GetDuration("haircut.interval"), haircutRecommender.Run)
+
+	marginCaller := lending.NewMarginCaller(
+		lendingRepository,
+		oracle.NewGetPriceFn(priceOracle),
+		risk.NewGetParametersFn(redis.NewGetStructDataRedisFn(pool)),
+		alertOpsFn,
+		logger,
+	)
+	job.Start(ctx, logger, "margin-caller", viper.GetDuration("loan.margin-call-interval"), marginCaller.Run)
+
+	treasuryMonitor := treasury.NewTreasuryMonitor(treasurySnapshotFn, alertOpsFn, logger)
+	job.Start(ctx, logger, "treasury-monitor", viper.GetDuration("treasury.interval"), treasuryMonitor.Run)
+
+	if reserveSigner != nil {
+		reservesReporter := lending.NewReservesReporter(
+			lendingRepository,
+			blockchain.NewListChainFn(chainRegistry),
+			blockchain.NewQueryTokenBalanceClientFn(chainRegistry),
+			blockchain.NewSignMessageFn(reserveSigner),
+			alertOpsFn,
+			logger,
+		)
+		job.Start(ctx, logger, "reserves-reporter", viper.GetDuration("reserves.interval"), reservesReporter.Run)
+	}
+
 	baseApi.Get("/price", handler.Helper(lendingHandler.GetTokenPrice, logger))
 	baseApi.Post("/price/calculation", handler.Helper(lendingHandler.PreCalculationLoan, logger))
+	baseApi.Get("/price/history", handler.Helper(oracleHandler.GetPriceHistory, logger))
+	baseApi.Get("/reserves", handler.Helper(lendingHandler.GetReserves, logger))
 
 	baseApi.Post("/subscription", handler.Helper(accountHandler.AddUserSubscription, logger))
 	baseApi.Post("/signup", handler.Helper(accountHandler.SignUp, logger))
@@ -152,6 +351,8 @@ func main() {
 	baseApi.Post("/reset", handler.Helper(accountHandler.RequestResetPassword, logger))
 	baseApi.Put("/reset", handler.Helper(accountHandler.ResetPassword, logger))
 
+	adminAuth := middle.AuthorizeAdminMiddleware()
+
 	baseApi.Get("/admin/documentInfo", handler.Helper(accountHandler.GetDocumentInfoAdmin, logger))
 	baseApi.Post("/admin/documentInfo", handler.Helper(accountHandler.CreateDocumentInfoAdmin, logger))
 	baseApi.Put("/admin/documentInfo", handler.Helper(accountHandler.UpdateDocumentInfoAdmin, logger))
@@ -168,8 +369,14 @@ func main() {
 	baseApi.Get("/admin/wallet-transaction", handler.Helper(lendingHandler.GetWalletTransactionAdmin, logger))
 	baseApi.Post("/admin/deposit/confirm", handler.Helper(lendingHandler.ConfirmDepositAdmin, logger))
 	baseApi.Post("/admin/deposit/reject", handler.Helper(lendingHandler.RejectDepositAdmin, logger))
-	baseApi.Post("/admin/withdraw/confirm", handler.Helper(lendingHandler.ConfirmWithdrawAdmin, logger))
+	baseApi.Post("/admin/withdraw/confirm", adminAuth, handler.Helper(lendingHandler.ConfirmWithdrawAdmin, logger))
 	baseApi.Post("/admin/withdraw/reject", handler.Helper(lendingHandler.RejectWithdrawAdmin, logger))
+	baseApi.Post("/admin/withdraw/speedup", adminAuth, handler.Helper(lendingHandler.SpeedUpWithdrawAdmin, logger))
+	baseApi.Post("/admin/withdraw/batch", adminAuth, handler.Helper(lendingHandler.BatchWithdrawAdmin, logger))
+	baseApi.Get("/admin/withdraw/unsigned", handler.Helper(lendingHandler.GetUnsignedWithdrawAdmin, logger))
+	baseApi.Post("/admin/withdraw/signed", adminAuth, handler.Helper(lendingHandler.ImportSignedWithdrawAdmin, logger))
+	baseApi.Get("/admin/sweep/unsigned", handler.Helper(lendingHandler.GetUnsignedSweepAdmin, logger))
+	baseApi.Post("/admin/sweep/signed", adminAuth, handler.Helper(lendingHandler.ImportSignedSweepAdmin, logger))
 
 	baseApi.Get("/admin/contract", handler.Helper(lendingHandler.GetLoanAdmin, logger))
 	baseApi.Post("/admin/contract", handler.Helper(lendingHandler.ConfirmLoanAdmin, logger))
@@ -179,6 +386,16 @@ func main() {
 	baseApi.Post("/admin/repay/reject", handler.Helper(lendingHandler.RejectRepayAdmin, logger))
 
 	baseApi.Post("/admin/liquidation", handler.Helper(lendingHandler.LiquidateFundAdmin, logger))
+	baseApi.Post("/admin/stress-test", handler.Helper(lendingHandler.StressTestAdmin, logger))
+
+	baseApi.Get("/admin/treasury", handler.Helper(treasuryHandler.GetTreasuryAdmin, logger))
+
+	baseApi.Get("/admin/breaker", handler.Helper(oracleHandler.GetBreakerAdmin, logger))
+	baseApi.Post("/admin/breaker", adminAuth, handler.Helper(oracleHandler.OverrideBreakerAdmin, logger))
+
+	baseApi.Get("/admin/haircut", handler.Helper(riskHandler.GetHaircutAdmin, logger))
+	baseApi.Post("/admin/haircut/approve", adminAuth, handler.Helper(riskHandler.ApproveHaircutAdmin, logger))
+	baseApi.Post("/admin/haircut/reject", adminAuth, handler.Helper(riskHandler.RejectHaircutAdmin, logger))
 
 	baseApi.Use(middle.AuthorizeTokenMiddleware())
 
@@ -187,8 +404,14 @@ func main() {
 
 	baseApi.Get("/wallet-transaction", handler.Helper(lendingHandler.GetWalletTransaction, logger))
 	baseApi.Post("/deposit", handler.Helper(lendingHandler.SubmitDeposit, logger))
+	baseApi.Get("/deposit/address", handler.Helper(lendingHandler.GetDepositAddress, logger))
+	baseApi.Get("/withdraw/fee", handler.Helper(lendingHandler.GetWithdrawFee, logger))
+	baseApi.Get("/reserves/proof", handler.Helper(lendingHandler.GetReserveProof, logger))
+	baseApi.Get("/withdraw/address", handler.Helper(lendingHandler.GetWithdrawAddress, logger))
+	baseApi.Delete("/withdraw/address/:id", handler.Helper(lendingHandler.RemoveWithdrawAddress, logger))
 
 	baseApi.Get("/credit", handler.Helper(lendingHandler.GetCreditAvailable, logger))
+	baseApi.Get("/stream", handler.Helper(positionStreamer.StreamPosition, logger))
 	baseApi.Get("/contract", handler.Helper(lendingHandler.GetLoan, logger))
 
 	baseApi.Get("/repay", handler.Helper(lendingHandler.GetRepay, logger))
@@ -200,14 +423,18 @@ func main() {
 
 	baseApi.Post("/borrow", handler.Helper(lendingHandler.BorrowLoan, logger))
 	baseApi.Post("/withdraw", handler.Helper(lendingHandler.SubmitWithdraw, logger))
+	baseApi.Post("/withdraw/address", handler.Helper(lendingHandler.AddWithdrawAddress, logger))
 
 	app.Get("/version", version.VersionHandler)
 	app.Get("/liveness", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
 	app.Get("/readiness", func(c *fiber.Ctx) error {
-		if isReady {
-			return c.SendStatus(fiber.StatusOK)
+		if !isReady {
+			return c.SendStatus(fiber.StatusServiceUnavailable)
+		}
+		if downChains := chainRegistry.DownChains(); len(downChains) > 0 {
+			return c.Status(fiber.StatusServiceUnavailable).SendString(fmt.Sprintf("rpc of every endpoint is down: %s", strings.Join(downChains, ", ")))
 		}
-		return c.SendStatus(fiber.StatusServiceUnavailable)
+		return c.SendStatus(fiber.StatusOK)
 	})
 
 	logger.Info(fmt.Sprintf("⇨ http server started on [::]:%s", viper.GetString("app.port")))
@@ -228,12 +455,45 @@ func main() {
 		logger.Info("terminating: by signal")
 	}
 
+	cancel()
 	app.Shutdown()
 
 	logger.Info("shutting down")
 	os.Exit(0)
 }
 
+// runIngester runs only the exchange websocket price ingester, in place of the separate feeder service.
+func runIngester() {
+	logger, err := logz.NewLogConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pool := redis.NewRedisConn()
+	defer pool.Close()
+
+	priceIngester, err := ingester.NewIngesterFromConfig(ingester.NewStorePriceFn(redis.NewSetDataNoExpireRedisFn(pool)), logger)
+	if err != nil {
+		logger.Fatal(err.Error())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		priceIngester.Run(ctx)
+		close(done)
+	}()
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	<-c
+	logger.Info("terminating: by signal")
+
+	cancel()
+	<-done
+	logger.Info("shutting down")
+}
+
 func initViper() {
 	viper.SetDefault("app.name", "lending-engine")
 	viper.SetDefault("app.port", "9090")
@@ -276,6 +536,12 @@ func initViper() {
 	viper.SetDefault("client.email-api.otp.template", "otp.html")
 	viper.SetDefault("client.email-api.liquidation.url", "http://localhost:8080/email/liquidation")
 	viper.SetDefault("client.email-api.liquidation.template", "liquidation.html")
+	viper.SetDefault("client.email-api.alert.url", "http://localhost:8080/email/alert")
+	viper.SetDefault("client.email-api.alert.template", "alert.html")
+	viper.SetDefault("client.email-api.alert.to", []string{"icfin999@gmail.com"})
+
+	viper.SetDefault("admin.user", "admin")
+	viper.SetDefault("admin.password", "")
 
 	viper.SetDefault("jwt.issuer", "admin")
 	viper.SetDefault("jwt.expired-at", "60m")
@@ -283,17 +549,147 @@ func initViper() {
 
 	viper.SetDefault("loan.haircut.btc", 0.5)
 	viper.SetDefault("loan.haircut.eth", 0.5)
+	viper.SetDefault("loan.margin-call-ltv.btc", 0.5)
+	viper.SetDefault("loan.margin-call-ltv.eth", 0.5)
 	viper.SetDefault("loan.interest", 0.05)
 	viper.SetDefault("loan.liquidate-limit", 3)
-
-	viper.SetDefault("blockchain.ethereum.rpc", "https://rinkeby.infura.io/v3/9657539221eb40a79ce550650f0530a3")
-	viper.SetDefault("blockchain.ethereum.chainId", 14)
-	viper.SetDefault("blockchain.binance.rpc", "https://bsc-dataseed.binance.org/")
-	viper.SetDefault("blockchain.binance.chainId", 56)
-	viper.SetDefault("blockchain.address", "0xa9B6D99bA92D7d691c6EF4f49A1DC909822Cee46")
+	viper.SetDefault("loan.margin-call-interval", "1m")
+
+	viper.SetDefault("stress.liquidation-ltv", 1)
+
+	viper.SetDefault("blockchain.transfer-gas", 65000)
+	viper.SetDefault("blockchain.chains.ethereum.chainId", 14)
+	viper.SetDefault("blockchain.rpc.timeout", "10s")
+	viper.SetDefault("blockchain.rpc.max-failures", 3)
+	viper.SetDefault("blockchain.rpc.cooldown", "1m")
+	viper.SetDefault("blockchain.chains.ethereum.rpc", []string{"https://rpc.ankr.com/eth_rinkeby", "https://rinkeby.eth.aragon.network"})
+	viper.SetDefault("blockchain.chains.ethereum.confirmations", 12)
+	viper.SetDefault("blockchain.chains.ethereum.native", "ETH")
+	viper.SetDefault("blockchain.chains.ethereum.address", "0xa9B6D99bA92D7d691c6EF4f49A1DC909822Cee46")
+	viper.SetDefault("blockchain.chains.binance.chainId", 56)
+	viper.SetDefault("blockchain.chains.binance.rpc", []string{"https://bsc-dataseed.binance.org/", "https://bsc-dataseed1.defibit.io/", "https://bsc-dataseed1.ninicoin.io/"})
+	viper.SetDefault("blockchain.chains.binance.confirmations", 15)
+	viper.SetDefault("blockchain.chains.binance.native", "BNB")
+	viper.SetDefault("blockchain.chains.binance.address", "0xa9B6D99bA92D7d691c6EF4f49A1DC909822Cee46")
+	viper.SetDefault("blockchain.chains.binance.tokens.btc.address", "0x7130d2A12B9BCbFAe4f2634d864A1Ee1Ce3Ead9c")
+	viper.SetDefault("blockchain.chains.binance.tokens.btc.decimals", 18)
+	viper.SetDefault("blockchain.chains.binance.tokens.eth.address", "0x2170Ed0880ac9A755fd29B2688956BD959F933F8")
+	viper.SetDefault("blockchain.chains.binance.tokens.eth.decimals", 18)
+	viper.SetDefault("blockchain.hot-wallet.keystore", "")
+	viper.SetDefault("blockchain.hot-wallet.passphrase", "")
+	viper.SetDefault("blockchain.hd.xpub", "")
+	viper.SetDefault("blockchain.cold-wallet.address", "")
+	viper.SetDefault("blockchain.multisend.abi", "")
+	viper.SetDefault("blockchain.multisend.method", "")
+	viper.SetDefault("blockchain.multisend.approve-timeout", "2m")
+	viper.SetDefault("blockchain.multisend.gas-limit", 0)
+
+	viper.SetDefault("bitcoin.chain-id", -1)
+	viper.SetDefault("bitcoin.rpc.url", "")
+	viper.SetDefault("bitcoin.rpc.user", "")
+	viper.SetDefault("bitcoin.rpc.password", "")
+	viper.SetDefault("bitcoin.rpc.timeout", "10s")
+	viper.SetDefault("bitcoin.confirmations", 3)
+	viper.SetDefault("bitcoin.address", "")
+
+	viper.SetDefault("deposit.scanner-interval", "1m")
+	viper.SetDefault("deposit.scan-max-blocks", 2000)
+	viper.SetDefault("deposit.reorg-interval", "1m")
+	viper.SetDefault("deposit.reorg-window", 200)
+
+	viper.SetDefault("sweeper.enabled", false)
+	viper.SetDefault("sweeper.interval", "10m")
+	viper.SetDefault("sweeper.batch-size", 20)
+	viper.SetDefault("sweeper.dust.btc", 0.001)
+	viper.SetDefault("sweeper.dust.eth", 0.01)
+
+	viper.SetDefault("oracle.sources", []string{"redis"})
+	viper.SetDefault("oracle.timeout", "3s")
+	viper.SetDefault("oracle.min-sources", 1)
+	viper.SetDefault("oracle.max-deviation", 0.02)
+	viper.SetDefault("oracle.max-age", "2m")
+	viper.SetDefault("oracle.source.redis.type", "redis")
+	viper.SetDefault("oracle.assets", []string{"BTC", "ETH"})
+
+	viper.SetDefault("fx.currencies", []string{"THB", "USD"})
+	viper.SetDefault("fx.sources", []string{"redis"})
+	viper.SetDefault("fx.timeout", "3s")
+	viper.SetDefault("fx.min-sources", 1)
+	viper.SetDefault("fx.max-deviation", 0.01)
+	viper.SetDefault("fx.max-age", "24h")
+	viper.SetDefault("fx.source.redis.type", "redis")
+
+	viper.SetDefault("breaker.interval", "10s")
+	viper.SetDefault("breaker.window", "15m")
+	viper.SetDefault("breaker.cool-down", "30m")
+	viper.SetDefault("breaker.threshold.btc", 0.1)
+	viper.SetDefault("breaker.threshold.eth", 0.15)
+
+	viper.SetDefault("haircut.interval", "6h")
+	viper.SetDefault("haircut.window", "720h")
+	viper.SetDefault("haircut.min-samples", 168)
+	viper.SetDefault("haircut.horizon", "96h")
+	viper.SetDefault("haircut.confidence", 0.99)
+	viper.SetDefault("haircut.var-multiple", 3)
+	viper.SetDefault("haircut.step", 0.05)
+	viper.SetDefault("haircut.min", 0.1)
+	viper.SetDefault("haircut.max", 0.8)
+
+	viper.SetDefault("stream.price-interval", "2s")
+	viper.SetDefault("stream.keepalive", "15s")
+	viper.SetDefault("stream.redis-heartbeat", "30s")
+	viper.SetDefault("stream.reconnect-delay", "1s")
+	viper.SetDefault("stream.query-timeout", "10s")
+
+	viper.SetDefault("ingester.enabled", false)
+	viper.SetDefault("ingester.url", "wss://api.bitkub.com/websocket-api/market.ticker.thb_btc,market.ticker.thb_eth")
+	viper.SetDefault("ingester.subscribe", "")
+	viper.SetDefault("ingester.symbol-field", "stream")
+	viper.SetDefault("ingester.symbol-pattern", "thb_([a-z0-9]+)$")
+	viper.SetDefault("ingester.price-field", "last")
+	viper.SetDefault("ingester.heartbeat-timeout", "30s")
+	viper.SetDefault("ingester.backoff.min", "1s")
+	viper.SetDefault("ingester.backoff.max", "1m")
+	viper.SetDefault("oracle.history.interval", "10s")
+	viper.SetDefault("oracle.history.max-points", 1500)
+	viper.SetDefault("oracle.history.retention.tick", "168h")
+	viper.SetDefault("oracle.history.retention.1m", "720h")
+	viper.SetDefault("oracle.history.retention.1h", "8760h")
+	viper.SetDefault("oracle.history.retention.1d", "0")
+
+	viper.SetDefault("treasury.interval", "5m")
+	viper.SetDefault("treasury.hot.addresses", []string{})
+	viper.SetDefault("treasury.cold.addresses", []string{})
+	viper.SetDefault("treasury.hot.floor.btc", 0.5)
+	viper.SetDefault("treasury.hot.floor.eth", 5)
+	viper.SetDefault("treasury.hot.ceiling.btc", 5)
+	viper.SetDefault("treasury.hot.ceiling.eth", 100)
+
+	viper.SetDefault("reserves.interval", "24h")
+	viper.SetDefault("reserves.signer.keystore", "")
+	viper.SetDefault("reserves.signer.passphrase", "")
+
+	viper.SetDefault("withdraw.tracker-interval", "1m")
+	viper.SetDefault("withdraw.stuck-after", "30m")
+	viper.SetDefault("withdraw.fee.policy", "sponsored")
+	viper.SetDefault("withdraw.fee.fixed.btc", 0.0002)
+	viper.SetDefault("withdraw.fee.fixed.eth", 0.003)
+	viper.SetDefault("withdraw.batch.max-size", 100)
+	viper.SetDefault("withdraw.cold-wallet.threshold.btc", 1)
+	viper.SetDefault("withdraw.cold-wallet.threshold.eth", 20)
+	viper.SetDefault("withdraw.address.cooling-off", "24h")
+	viper.SetDefault("withdraw.address.require-signature", false)
 
 	viper.AutomaticEnv()
 	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+
+	viper.SetConfigName("config")
+	viper.AddConfigPath("./config")
+	if err := viper.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+			log.Printf("error reading config file: %v\n", err)
+		}
+	}
 }
 
 func initTimezone() {
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"crypto/subtle"
 	"fmt"
 	"lending-engine/common"
 	"lending-engine/internal/redis"
@@ -55,6 +56,28 @@ func (m *middleware) BasicAuthenicationMiddleware() fiber.Handler {
 	})
 }
 
+// AuthorizeAdminMiddleware guards admin routes that move funds or change risk controls with basic authentication of
+// "admin.user" and "admin.password". Every request is refused while the password isn't set.
+func (m *middleware) AuthorizeAdminMiddleware() fiber.Handler {
+	return basicauth.New(basicauth.Config{
+		Realm: "Admin",
+		Authorizer: func(user, pass string) bool {
+			password := viper.GetString("admin.password")
+			if password == "" {
+				return false
+			}
+			return subtle.ConstantTimeCompare([]byte(user), []byte(viper.GetString("admin.user"))) == 1 &&
+				subtle.ConstantTimeCompare([]byte(pass), []byte(password)) == 1
+		},
+		Unauthorized: func(c *fiber.Ctx) error {
+			c.Set(fiber.HeaderWWWAuthenticate, `Basic realm="Admin"`)
+			return c.Status(fiber.StatusUnauthorized).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).AuthorizationToken, "Admin is unauthorized."))
+		},
+		ContextUsername: "_user",
+		ContextPassword: "_password",
+	})
+}
+
 func (m *middleware) AuthorizeTokenMiddleware() fiber.Handler {
 	return jwtware.New(jwtware.Config{
 		SigningKey:    []byte(viper.GetString("jwt.secret-key")),
@@ -72,7 +95,7 @@ func (m *middleware) AuthorizeTokenMiddleware() fiber.Handler {
 func (m *middleware) CorsMiddleware() fiber.Handler {
 	return cors.New(cors.Config{
 		AllowOrigins: "*",
-		AllowMethods: "GET,POST,PUT,PATCH",
+		AllowMethods: "GET,POST,PUT,PATCH,DELETE",
 		AllowHeaders: "Content-Type, Origin, Authorization, Accept, OTP, ReferenceNo",
 	})
 }
@@ -112,9 +135,12 @@ func (m *middleware) LoggingMiddleware() fiber.Handler {
 		if err := c.Next(); err != nil {
 			return err
 		}
-		logger.Debug(common.ResponseInfoMsg,
-			zap.String("body", string(c.Response().Body())),
-		)
+		// reading a streamed body would block until the stream ends, so it isn't logged.
+		if !c.Response().IsBodyStream() {
+			logger.Debug(common.ResponseInfoMsg,
+				zap.String("body", string(c.Response().Body())),
+			)
+		}
 		logger.Info("Summary Information",
 			zap.String("method", string(c.Request().Header.Method())),
 			zap.String("path_uri", c.Request().URI().String()),
@@ -0,0 +1,201 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"lending-engine/internal/redis"
+	"lending-engine/mail"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrCircuitOpen = errors.New("price circuit breaker is open")
+)
+
+// Breaker is the circuit breaker state of an asset, kept in redis under "BREAKER/<asset>" until Until.
+// No key is CLOSED in AUTO mode.
+type Breaker struct {
+	Asset  string     `json:"asset" example:"BTC"`
+	Status string     `json:"status" example:"OPEN"`
+	Mode   string     `json:"mode" example:"AUTO"`
+	Reason string     `json:"reason,omitempty" example:"BTC moved 12.50% within 15m0s"`
+	Until  *time.Time `json:"until,omitempty" example:"2021-01-02T12:43:14+07:00"`
+}
+
+func breakerKey(asset string) string {
+	return fmt.Sprintf("BREAKER/%s", strings.ToUpper(asset))
+}
+
+// CheckBreakerFn returns ErrCircuitOpen while the breaker of asset is open.
+type CheckBreakerFn func(ctx context.Context, asset string) error
+
+func NewCheckBreakerFn(getStructDataRedisFn redis.GetStructDataRedisFn) CheckBreakerFn {
+	return func(ctx context.Context, asset string) error {
+		var breaker Breaker
+		if err := getStructDataRedisFn(breakerKey(asset), &breaker); err != nil {
+			return err
+		}
+		if breaker.Status == BreakerOpenStatus {
+			return errors.Wrapf(ErrCircuitOpen, "%s (%s)", breaker.Reason, breaker.Mode)
+		}
+		return nil
+	}
+}
+
+// ListBreakerFn returns the breaker of every "oracle.assets".
+type ListBreakerFn func(ctx context.Context) ([]Breaker, error)
+
+func NewListBreakerFn(getStructDataRedisFn redis.GetStructDataRedisFn) ListBreakerFn {
+	return func(ctx context.Context) ([]Breaker, error) {
+		breakers := make([]Breaker, 0)
+		for _, asset := range viper.GetStringSlice("oracle.assets") {
+			breaker := Breaker{
+				Asset:  strings.ToUpper(asset),
+				Status: BreakerClosedStatus,
+				Mode:   BreakerAutoMode,
+			}
+			if err := getStructDataRedisFn(breakerKey(asset), &breaker); err != nil {
+				return nil, err
+			}
+			breakers = append(breakers, breaker)
+		}
+		return breakers, nil
+	}
+}
+
+// OverrideBreakerFn sets the breaker of asset by an admin. HALT opens and RESUME closes it for duration, AUTO hands it back to the monitor.
+type OverrideBreakerFn func(ctx context.Context, asset string, mode string, duration time.Duration, reason string) (*Breaker, error)
+
+func NewOverrideBreakerFn(setStructWExpireRedisFn redis.SetStructWExpireRedisFn, deleteDataRedisFn redis.DeleteDataRedisFn) OverrideBreakerFn {
+	return func(ctx context.Context, asset string, mode string, duration time.Duration, reason string) (*Breaker, error) {
+		breaker := Breaker{
+			Asset:  strings.ToUpper(asset),
+			Status: BreakerClosedStatus,
+			Mode:   mode,
+			Reason: reason,
+		}
+		if mode == BreakerAutoMode {
+			breaker.Reason = ""
+			return &breaker, deleteDataRedisFn(breakerKey(asset))
+		}
+		if mode == BreakerHaltMode {
+			breaker.Status = BreakerOpenStatus
+		}
+		until := time.Now().Add(duration)
+		breaker.Until = &until
+		if err := setStructWExpireRedisFn(breakerKey(asset), int(math.Ceil(duration.Seconds())), breaker); err != nil {
+			return nil, err
+		}
+		return &breaker, nil
+	}
+}
+
+type priceSample struct {
+	Price     float64
+	Timestamp time.Time
+}
+
+// breakerMonitor opens the breaker of an asset when its price moves more than "breaker.threshold.<asset>" within "breaker.window",
+// for "breaker.cool-down" after which it closes by itself. An admin override is left alone and stale prices are ignored.
+type breakerMonitor struct {
+	GetPriceFn              GetPriceFn
+	GetStructDataRedisFn    redis.GetStructDataRedisFn
+	SetStructWExpireRedisFn redis.SetStructWExpireRedisFn
+	AlertOpsFn              mail.AlertOpsFn
+	Logger                  *zap.Logger
+	mu                      sync.Mutex
+	samples                 map[string][]priceSample
+}
+
+func NewBreakerMonitor(getPriceFn GetPriceFn, getStructDataRedisFn redis.GetStructDataRedisFn, setStructWExpireRedisFn redis.SetStructWExpireRedisFn, alertOpsFn mail.AlertOpsFn, logger *zap.Logger) *breakerMonitor {
+	return &breakerMonitor{
+		GetPriceFn:              getPriceFn,
+		GetStructDataRedisFn:    getStructDataRedisFn,
+		SetStructWExpireRedisFn: setStructWExpireRedisFn,
+		AlertOpsFn:              alertOpsFn,
+		Logger:                  logger,
+		samples:                 make(map[string][]priceSample),
+	}
+}
+
+func (b *breakerMonitor) Run(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, asset := range viper.GetStringSlice("oracle.assets") {
+		if err := b.check(ctx, strings.ToUpper(asset)); err != nil {
+			b.Logger.Error(fmt.Sprintf("Asset: %s | %s", asset, err.Error()))
+		}
+	}
+	return nil
+}
+
+func (b *breakerMonitor) check(ctx context.Context, asset string) error {
+	price, err := b.GetPriceFn(ctx, asset)
+	if err != nil {
+		return err
+	}
+	if price.Stale {
+		return nil
+	}
+
+	now := time.Now()
+	window := viper.GetDuration("breaker.window")
+	samples := append(b.samples[asset], priceSample{Price: price.Price, Timestamp: now})
+	for len(samples) > 0 && now.Sub(samples[0].Timestamp) > window {
+		samples = samples[1:]
+	}
+	b.samples[asset] = samples
+
+	low, high := price.Price, price.Price
+	for _, sample := range samples {
+		low = math.Min(low, sample.Price)
+		high = math.Max(high, sample.Price)
+	}
+	move := math.Max((price.Price-low)/low, (high-price.Price)/high)
+	threshold := viper.GetFloat64(fmt.Sprintf("breaker.threshold.%s", strings.ToLower(asset)))
+	if threshold <= 0 || move <= threshold {
+		return nil
+	}
+
+	var current Breaker
+	if err := b.GetStructDataRedisFn(breakerKey(asset), &current); err != nil {
+		return err
+	}
+	if current.Mode != "" && current.Mode != BreakerAutoMode {
+		return nil
+	}
+
+	coolDown := viper.GetDuration("breaker.cool-down")
+	until := now.Add(coolDown)
+	breaker := Breaker{
+		Asset:  asset,
+		Status: BreakerOpenStatus,
+		Mode:   BreakerAutoMode,
+		Reason: fmt.Sprintf("%s moved %.2f%% within %s", asset, move*100, window),
+		Until:  &until,
+	}
+	// a move that goes on keeps the breaker open, the cool-down restarts.
+	if err := b.SetStructWExpireRedisFn(breakerKey(asset), int(math.Ceil(coolDown.Seconds())), breaker); err != nil {
+		return err
+	}
+	// the move is measured from the tripping price from now on, otherwise the samples that tripped the breaker reopen it
+	// as soon as the cool-down ends whenever "breaker.window" is the longer of the two.
+	b.samples[asset] = samples[len(samples)-1:]
+	if current.Status == BreakerOpenStatus {
+		return nil
+	}
+	b.Logger.Info(fmt.Sprintf("Asset: %s - Breaker: %s | %s", asset, BreakerOpenStatus, breaker.Reason))
+	message := fmt.Sprintf("%s. Borrowing, withdrawals and liquidations of %s are paused until %s.", breaker.Reason, asset, until.Format(time.RFC3339))
+	if err := b.AlertOpsFn(b.Logger, "Price circuit breaker open", message); err != nil {
+		b.Logger.Error(err.Error())
+	}
+	return nil
+}
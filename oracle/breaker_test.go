@@ -0,0 +1,234 @@
+package oracle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"lending-engine/common"
+	"lending-engine/internal/handler"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// memRedis keeps breakers as JSON by key. Expiry is left to the test, which deletes a key to end its cool-down.
+type memRedis map[string][]byte
+
+func (m memRedis) get(key string, dest interface{}) error {
+	data, ok := m[key]
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(data, dest)
+}
+
+func (m memRedis) set(key string, ttl int, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	m[key] = data
+	return nil
+}
+
+func (m memRedis) delete(key string) error {
+	delete(m, key)
+	return nil
+}
+
+// testBreaker runs a breaker monitor of BTC over prices set by the test and counts its alerts.
+type testBreaker struct {
+	redis   memRedis
+	price   float64
+	alerts  int
+	monitor *breakerMonitor
+	check   CheckBreakerFn
+}
+
+func newTestBreaker(t *testing.T) *testBreaker {
+	t.Helper()
+	viper.Set("oracle.assets", []string{"BTC"})
+	viper.Set("breaker.window", time.Hour)
+	viper.Set("breaker.cool-down", time.Minute)
+	viper.Set("breaker.threshold.btc", 0.1)
+	t.Cleanup(func() {
+		for _, key := range []string{"oracle.assets", "breaker.window", "breaker.cool-down", "breaker.threshold.btc"} {
+			viper.Set(key, nil)
+		}
+	})
+
+	b := &testBreaker{redis: memRedis{}, price: 1000000}
+	getPriceFn := func(ctx context.Context, asset string) (*Price, error) {
+		return &Price{Asset: asset, Price: b.price, Timestamp: time.Now()}, nil
+	}
+	alertOpsFn := func(logger *zap.Logger, title string, message string) error {
+		b.alerts++
+		return nil
+	}
+	b.monitor = NewBreakerMonitor(getPriceFn, b.redis.get, b.redis.set, alertOpsFn, zap.NewNop())
+	b.check = NewCheckBreakerFn(b.redis.get)
+	return b
+}
+
+func (b *testBreaker) run(t *testing.T, price float64) {
+	t.Helper()
+	b.price = price
+	if err := b.monitor.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (b *testBreaker) open() bool {
+	return errors.Is(b.check(context.Background(), "BTC"), ErrCircuitOpen)
+}
+
+// expire ends the cool-down the way the redis TTL would.
+func (b *testBreaker) expire() {
+	delete(b.redis, breakerKey("BTC"))
+}
+
+func TestBreakerMonitorTripsOnMove(t *testing.T) {
+	b := newTestBreaker(t)
+
+	b.run(t, 1000000)
+	b.run(t, 950000)
+	if b.open() {
+		t.Fatal("breaker open after a 5% move")
+	}
+	b.run(t, 880000)
+	if !b.open() || b.alerts != 1 {
+		t.Fatalf("breaker open %t with %d alerts after a 12%% move, want open with one", b.open(), b.alerts)
+	}
+	// a move that goes on from the tripping price keeps it open without another alert.
+	b.run(t, 780000)
+	if !b.open() || b.alerts != 1 {
+		t.Errorf("breaker open %t with %d alerts after moving on, want open with one", b.open(), b.alerts)
+	}
+}
+
+func TestBreakerMonitorClosesAfterCoolDown(t *testing.T) {
+	b := newTestBreaker(t)
+
+	b.run(t, 1000000)
+	b.run(t, 880000)
+	if !b.open() {
+		t.Fatal("breaker closed after a 12% move")
+	}
+
+	// the samples that tripped it are still within the window but don't reopen it.
+	b.expire()
+	b.run(t, 880000)
+	if b.open() || b.alerts != 1 {
+		t.Fatalf("breaker open %t with %d alerts after the cool-down, want closed with one", b.open(), b.alerts)
+	}
+	// a new move from the tripping price opens it again.
+	b.run(t, 770000)
+	if !b.open() || b.alerts != 2 {
+		t.Errorf("breaker open %t with %d alerts after a new move, want open with two", b.open(), b.alerts)
+	}
+}
+
+func TestBreakerMonitorSkipsStalePrices(t *testing.T) {
+	b := newTestBreaker(t)
+	stale := false
+	b.monitor.GetPriceFn = func(ctx context.Context, asset string) (*Price, error) {
+		return &Price{Asset: asset, Price: b.price, Timestamp: time.Now(), Stale: stale}, nil
+	}
+
+	b.run(t, 1000000)
+	stale = true
+	b.run(t, 500000)
+	if b.open() {
+		t.Error("breaker opened on a stale price")
+	}
+}
+
+func overrideBreaker(t *testing.T, app *fiber.App, req OverrideBreakerAdminRequest) *Breaker {
+	t.Helper()
+	body, err := json.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpReq := httptest.NewRequest(http.MethodPost, "/admin/breaker", bytes.NewReader(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(httpReq, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("POST /admin/breaker = %d: %s", resp.StatusCode, respBody)
+	}
+	var breaker Breaker
+	if err := json.Unmarshal(respBody, &struct {
+		Data interface{} `json:"data"`
+	}{Data: &breaker}); err != nil {
+		t.Fatal(err)
+	}
+	return &breaker
+}
+
+func TestOverrideBreakerAdmin(t *testing.T) {
+	b := newTestBreaker(t)
+	oracleHandler := NewOracleHandler(nil, NewListBreakerFn(b.redis.get), NewOverrideBreakerFn(b.redis.set, b.redis.delete))
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Context().SetUserValue(common.LocaleKey, "en")
+		return c.Next()
+	})
+	app.Post("/admin/breaker", handler.Helper(oracleHandler.OverrideBreakerAdmin, zap.NewNop()))
+
+	// HALT opens it without a move.
+	breaker := overrideBreaker(t, app, OverrideBreakerAdminRequest{Asset: "btc", Mode: BreakerHaltMode, Reason: "exchange outage"})
+	if breaker.Status != BreakerOpenStatus || breaker.Until == nil || !b.open() {
+		t.Fatalf("HALT = %+v, open %t, want open until the cool-down", breaker, b.open())
+	}
+
+	// RESUME keeps it closed through a move the monitor would trip on.
+	overrideBreaker(t, app, OverrideBreakerAdminRequest{Asset: "BTC", Mode: BreakerResumeMode, Duration: 60, Reason: "bad tick"})
+	b.run(t, 1000000)
+	b.run(t, 800000)
+	if b.open() || b.alerts != 0 {
+		t.Fatalf("breaker open %t with %d alerts while resumed, want closed without alerts", b.open(), b.alerts)
+	}
+	breakers, err := oracleHandler.ListBreakerFn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(breakers) != 1 || breakers[0].Mode != BreakerResumeMode || breakers[0].Status != BreakerClosedStatus {
+		t.Errorf("breakers = %+v, want BTC resumed", breakers)
+	}
+
+	// AUTO hands it back to the monitor, which trips on the move that is still in its window.
+	breaker = overrideBreaker(t, app, OverrideBreakerAdminRequest{Asset: "BTC", Mode: BreakerAutoMode})
+	if breaker.Mode != BreakerAutoMode || breaker.Until != nil {
+		t.Fatalf("AUTO = %+v, want the monitor in charge", breaker)
+	}
+	b.run(t, 800000)
+	if !b.open() || b.alerts != 1 {
+		t.Errorf("breaker open %t with %d alerts back in AUTO, want open with one", b.open(), b.alerts)
+	}
+
+	// an invalid mode is rejected.
+	body, _ := json.Marshal(OverrideBreakerAdminRequest{Asset: "BTC", Mode: "PAUSE"})
+	httpReq := httptest.NewRequest(http.MethodPost, "/admin/breaker", bytes.NewReader(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(httpReq, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("POST /admin/breaker with mode PAUSE = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,37 @@
+package oracle
+
+import (
+	"context"
+	"time"
+)
+
+type PriceTick struct {
+	ID              *int64     `db:"id" json:"id" example:"1"`
+	Asset           *string    `db:"asset" json:"asset" example:"BTC"`
+	Price           *float64   `db:"price" json:"price" example:"1042475.25"`
+	Sources         *string    `db:"sources" json:"sources" example:"redis,bitkub"`
+	Stale           *bool      `db:"stale" json:"stale" example:"false"`
+	PriceDatetime   *time.Time `db:"price_datetime" json:"priceDatetime" example:"2021-01-02 12:13:10"`
+	CreatedDatetime *time.Time `db:"created_datetime" json:"createdDatetime" example:"2021-01-02 12:13:14"`
+}
+
+type PriceCandle struct {
+	Asset          *string    `db:"asset" json:"asset" example:"BTC"`
+	CandleInterval *string    `db:"candle_interval" json:"interval" example:"1m"`
+	OpenDatetime   *time.Time `db:"open_datetime" json:"openDatetime" example:"2021-01-02 12:13:00"`
+	OpenPrice      *float64   `db:"open_price" json:"open" example:"1042475.25"`
+	HighPrice      *float64   `db:"high_price" json:"high" example:"1043000"`
+	LowPrice       *float64   `db:"low_price" json:"low" example:"1042000"`
+	ClosePrice     *float64   `db:"close_price" json:"close" example:"1042800.5"`
+	Ticks          *int       `db:"ticks" json:"ticks" example:"6"`
+}
+
+type HistoryRepository interface {
+	InsertPriceTickRepo(context.Context, string, float64, string, bool, string, string) (int64, error)
+	RollupTickRepo(context.Context, string) (int64, error)
+	RollupCandleRepo(context.Context, string, string, string, string) (int64, error)
+	DeletePriceTickRepo(context.Context, string) (int64, error)
+	DeletePriceCandleRepo(context.Context, string, string) (int64, error)
+	QueryPriceCandleRepo(context.Context, string, string, string, string) (*[]PriceCandle, error)
+	QueryPriceTickRepo(context.Context, string, string, string) (*[]PriceTick, error)
+}
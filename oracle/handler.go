@@ -0,0 +1,125 @@
+package oracle
+
+import (
+	"fmt"
+	"lending-engine/common"
+	"lending-engine/internal/handler"
+	"lending-engine/response"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/viper"
+)
+
+type oracleHandler struct {
+	HistoryRepository HistoryRepository
+	ListBreakerFn     ListBreakerFn
+	OverrideBreakerFn OverrideBreakerFn
+}
+
+func NewOracleHandler(historyRepository HistoryRepository, listBreakerFn ListBreakerFn, overrideBreakerFn OverrideBreakerFn) *oracleHandler {
+	return &oracleHandler{
+		HistoryRepository: historyRepository,
+		ListBreakerFn:     listBreakerFn,
+		OverrideBreakerFn: overrideBreakerFn,
+	}
+}
+
+// GetPriceHistory
+// @Summary Get Price History
+// @Description get 1m, 1h or 1d OHLC candles of an asset, or the raw ticks with their sources for auditing
+// @Tags Lending
+// @Accept json
+// @Produce json
+// @Param asset query string true "Asset"
+// @Param interval query string false "tick, 1m, 1h or 1d (default 1h)"
+// @Param from query string false "RFC3339 start (default one span before 'to')"
+// @Param to query string false "RFC3339 end (default now)"
+// @Success 200 {object} response.Response{data=oracle.GetPriceHistoryResponse} "Success"
+// @Failure 400 {object} response.ErrResponse "Bad Request"
+// @Failure 500 {object} response.ErrResponse "Internal Server Error"
+// @Router /price/history [get]
+func (s *oracleHandler) GetPriceHistory(c *handler.Ctx) error {
+	var req GetPriceHistoryRequest
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).GetPriceHistoryRequest, err.Error()))
+	}
+	from, to, err := req.validate()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).GetPriceHistoryRequest, err.Error()))
+	}
+
+	getPriceHistoryResponse := GetPriceHistoryResponse{
+		Asset:    strings.ToUpper(req.Asset),
+		Interval: req.Interval,
+	}
+	if req.Interval == TickInterval {
+		ticks, err := s.HistoryRepository.QueryPriceTickRepo(c.Context(), getPriceHistoryResponse.Asset, from.Format(common.DateYYYYMMDDHHMMSSFormat), to.Format(common.DateYYYYMMDDHHMMSSFormat))
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+		}
+		getPriceHistoryResponse.Ticks = *ticks
+	} else {
+		candles, err := s.HistoryRepository.QueryPriceCandleRepo(c.Context(), getPriceHistoryResponse.Asset, req.Interval, from.Format(common.DateYYYYMMDDHHMMSSFormat), to.Format(common.DateYYYYMMDDHHMMSSFormat))
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+		}
+		getPriceHistoryResponse.Candles = *candles
+	}
+	return c.Status(fiber.StatusOK).JSON(response.NewResponse(response.ResponseContextLocale(c.Context()).GetPriceHistorySuccess, &getPriceHistoryResponse))
+}
+
+// GetBreakerAdmin
+// @Summary Get Breaker Admin
+// @Description get price circuit breaker of every asset
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=oracle.GetBreakerAdminResponse} "Success"
+// @Failure 400 {object} response.ErrResponse "Bad Request"
+// @Failure 500 {object} response.ErrResponse "Internal Server Error"
+// @Router /admin/breaker [get]
+func (s *oracleHandler) GetBreakerAdmin(c *handler.Ctx) error {
+	breakers, err := s.ListBreakerFn(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalRedis, err.Error()))
+	}
+	getBreakerAdminResponse := GetBreakerAdminResponse{
+		Breakers: breakers,
+	}
+	return c.Status(fiber.StatusOK).JSON(response.NewResponse(response.ResponseContextLocale(c.Context()).GetBreakerAdminSuccess, &getBreakerAdminResponse))
+}
+
+// OverrideBreakerAdmin
+// @Summary Override Breaker Admin
+// @Description halt or resume an asset for duration seconds (default breaker.cool-down), or hand it back to the monitor with AUTO
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param OverrideBreakerAdmin body oracle.OverrideBreakerAdminRequest true "request body to override price circuit breaker"
+// @Success 200 {object} response.Response{data=oracle.Breaker} "Success"
+// @Failure 400 {object} response.ErrResponse "Bad Request"
+// @Failure 500 {object} response.ErrResponse "Internal Server Error"
+// @Security AdminAuth
+// @Router /admin/breaker [post]
+func (s *oracleHandler) OverrideBreakerAdmin(c *handler.Ctx) error {
+	var req OverrideBreakerAdminRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).OverrideBreakerAdminRequest, err.Error()))
+	}
+	if err := req.validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).OverrideBreakerAdminRequest, err.Error()))
+	}
+
+	duration := time.Duration(req.Duration) * time.Second
+	if duration == 0 {
+		duration = viper.GetDuration("breaker.cool-down")
+	}
+	breaker, err := s.OverrideBreakerFn(c.Context(), req.Asset, req.Mode, duration, req.Reason)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalRedis, err.Error()))
+	}
+	c.Log().Info(fmt.Sprintf("Asset: %s - Breaker: %s - Mode: %s | %s", breaker.Asset, breaker.Status, breaker.Mode, req.Reason))
+	return c.Status(fiber.StatusOK).JSON(response.NewResponse(response.ResponseContextLocale(c.Context()).OverrideBreakerAdminSuccess, breaker))
+}
@@ -0,0 +1,152 @@
+package oracle
+
+import (
+	"fmt"
+	"lending-engine/response"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+// Price is the aggregated THB price of an asset. Sources are the quotes used, Rejected the ones dropped as outliers or errors.
+// Stale is set when no quote is younger than "<config>.max-age".
+type Price struct {
+	Asset     string    `json:"asset" example:"BTC"`
+	Price     float64   `json:"price" example:"1042475.25"`
+	Timestamp time.Time `json:"timestamp" example:"2021-01-02T12:13:14+07:00"`
+	Stale     bool      `json:"stale" example:"false"`
+	Sources   []Quote   `json:"sources"`
+	Rejected  []Quote   `json:"rejected,omitempty"`
+}
+
+// Quote is one source's price. Timestamp is when the source observed the price.
+type Quote struct {
+	Source    string    `json:"source" example:"redis"`
+	Price     float64   `json:"price" example:"1042475.25"`
+	Timestamp time.Time `json:"timestamp" example:"2021-01-02T12:13:14+07:00"`
+	Error     string    `json:"error,omitempty" example:"deviates 3.1% from median"`
+}
+
+// Fresh returns ErrStalePrice when the price is older than "<config>.max-age" of its oracle.
+func (p *Price) Fresh() error {
+	if p.Stale {
+		return errors.Wrapf(ErrStalePrice, "%s price at %s", p.Asset, p.Timestamp.Format(time.RFC3339))
+	}
+	return nil
+}
+
+const (
+	BreakerOpenStatus   = "OPEN"
+	BreakerClosedStatus = "CLOSED"
+	BreakerAutoMode     = "AUTO"
+	BreakerHaltMode     = "HALT"
+	BreakerResumeMode   = "RESUME"
+)
+
+// TickInterval returns raw ticks instead of candles.
+const TickInterval = "tick"
+
+var CandleIntervals = []string{"1m", "1h", "1d"}
+
+// historyRanges is the default span of GET /price/history when 'from' is omitted.
+var historyRanges = map[string]time.Duration{
+	TickInterval: time.Hour,
+	"1m":         24 * time.Hour,
+	"1h":         30 * 24 * time.Hour,
+	"1d":         365 * 24 * time.Hour,
+}
+
+// price history
+type GetPriceHistoryRequest struct {
+	Asset    string `json:"asset" example:"BTC"`
+	Interval string `json:"interval" example:"1h"`
+	From     string `json:"from" example:"2021-01-02T00:00:00+07:00"`
+	To       string `json:"to" example:"2021-01-03T00:00:00+07:00"`
+}
+
+// validate checks the request and returns the range, 'to' defaults to now and 'from' to the interval's default span before it.
+func (req *GetPriceHistoryRequest) validate() (time.Time, time.Time, error) {
+	if utf8.RuneCountInString(req.Asset) == 0 {
+		return time.Time{}, time.Time{}, errors.Wrapf(errors.New(fmt.Sprintf("'asset' must be REQUIRED field but the input is '%v'.", req.Asset)), response.ValidateFieldError)
+	}
+	if req.Interval == "" {
+		req.Interval = "1h"
+	}
+	span, ok := historyRanges[req.Interval]
+	if !ok {
+		return time.Time{}, time.Time{}, errors.Wrapf(errors.New(fmt.Sprintf("'interval' must be one of tick, 1m, 1h or 1d but the input is '%v'.", req.Interval)), response.ValidateFieldError)
+	}
+	to := time.Now()
+	if req.To != "" {
+		t, err := time.Parse(time.RFC3339, req.To)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.Wrapf(errors.New(fmt.Sprintf("'to' must be RFC3339 but the input is '%v'.", req.To)), response.ValidateFieldError)
+		}
+		to = t
+	}
+	from := to.Add(-span)
+	if req.From != "" {
+		t, err := time.Parse(time.RFC3339, req.From)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.Wrapf(errors.New(fmt.Sprintf("'from' must be RFC3339 but the input is '%v'.", req.From)), response.ValidateFieldError)
+		}
+		from = t
+	}
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, errors.Wrapf(errors.New(fmt.Sprintf("'from' must be before 'to' but the input is '%v'.", req.From)), response.ValidateFieldError)
+	}
+	if maxPoints := viper.GetInt("oracle.history.max-points"); maxPoints > 0 && to.Sub(from) > historyStep(req.Interval)*time.Duration(maxPoints) {
+		return time.Time{}, time.Time{}, errors.Wrapf(errors.New(fmt.Sprintf("'from' to 'to' must span at most %d points of '%v'.", maxPoints, req.Interval)), response.ValidateFieldError)
+	}
+	return from.In(time.Local), to.In(time.Local), nil
+}
+
+// historyStep is the time between two points of interval, ticks are recorded every "oracle.history.interval".
+func historyStep(interval string) time.Duration {
+	switch interval {
+	case "1m":
+		return time.Minute
+	case "1h":
+		return time.Hour
+	case "1d":
+		return 24 * time.Hour
+	default:
+		return viper.GetDuration("oracle.history.interval")
+	}
+}
+
+type GetPriceHistoryResponse struct {
+	Asset    string        `json:"asset" example:"BTC"`
+	Interval string        `json:"interval" example:"1h"`
+	Candles  []PriceCandle `json:"candles,omitempty"`
+	Ticks    []PriceTick   `json:"ticks,omitempty"`
+}
+
+// breaker
+type GetBreakerAdminResponse struct {
+	Breakers []Breaker `json:"breakers"`
+}
+
+type OverrideBreakerAdminRequest struct {
+	Asset    string `json:"asset" example:"BTC"`
+	Mode     string `json:"mode" example:"RESUME"`
+	Duration int    `json:"duration" example:"1800"`
+	Reason   string `json:"reason" example:"bad tick from exchange"`
+}
+
+func (req *OverrideBreakerAdminRequest) validate() error {
+	if utf8.RuneCountInString(req.Asset) == 0 {
+		return errors.Wrapf(errors.New(fmt.Sprintf("'asset' must be REQUIRED field but the input is '%v'.", req.Asset)), response.ValidateFieldError)
+	}
+	switch req.Mode {
+	case BreakerHaltMode, BreakerResumeMode, BreakerAutoMode:
+	default:
+		return errors.Wrapf(errors.New(fmt.Sprintf("'mode' must be HALT, RESUME or AUTO but the input is '%v'.", req.Mode)), response.ValidateFieldError)
+	}
+	if req.Duration < 0 {
+		return errors.Wrapf(errors.New(fmt.Sprintf("'duration' must not be negative but the input is '%v'.", req.Duration)), response.ValidateFieldError)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"lending-engine/blockchain"
+	"lending-engine/internal/redis"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+var (
+	ErrNoPrice    = errors.New("not enough price sources")
+	ErrStalePrice = errors.New("price is stale")
+)
+
+// PriceOracle aggregates the quotes of its sources by median. Settings are read under config, "oracle" for assets and "fx" for currencies.
+// A quote deviating more than "<config>.max-deviation" from the median is rejected and at least "<config>.min-sources" quotes must remain.
+// Quotes older than "<config>.max-age" are used only when too few are fresh, the price is then Stale.
+type PriceOracle struct {
+	config  string
+	sources []Source
+}
+
+func NewPriceOracle(config string, sources ...Source) *PriceOracle {
+	return &PriceOracle{
+		config:  config,
+		sources: sources,
+	}
+}
+
+// NewPriceOracleFromConfig builds the sources named in "<config>.sources" from "<config>.source.<name>.type" (redis, rest or chainlink).
+func NewPriceOracleFromConfig(config string, getFloatDataRedisFn redis.GetFloatDataRedisFn, queryLatestRoundClientFn blockchain.QueryLatestRoundClientFn) (*PriceOracle, error) {
+	sources := make([]Source, 0)
+	for _, name := range viper.GetStringSlice(config + ".sources") {
+		key := fmt.Sprintf("%s.source.%s", config, name)
+		switch sourceType := viper.GetString(key + ".type"); sourceType {
+		case "", "redis":
+			sources = append(sources, NewRedisSource(name, getFloatDataRedisFn))
+		case "rest":
+			url := viper.GetString(key + ".url")
+			if url == "" {
+				return nil, errors.Errorf("%s.url is empty", key)
+			}
+			sources = append(sources, NewRESTSource(name, url, viper.GetString(key+".path"), viper.GetDuration(config+".timeout")))
+		case "chainlink":
+			chainId := viper.GetInt(key + ".chain-id")
+			if chainId == 0 {
+				return nil, errors.Errorf("%s.chain-id is empty", key)
+			}
+			feeds := viper.GetStringMapString(key + ".feeds")
+			if len(feeds) == 0 {
+				return nil, errors.Errorf("%s.feeds is empty", key)
+			}
+			sources = append(sources, NewChainlinkSource(name, chainId, feeds, viper.GetString(key+".quote-feed"), viper.GetDuration(key+".max-age"), viper.GetDuration(key+".quote-max-age"), queryLatestRoundClientFn))
+		default:
+			return nil, errors.Errorf("%s.type '%s' is not supported", key, sourceType)
+		}
+	}
+	if len(sources) == 0 {
+		return nil, errors.Errorf("%s.sources is empty", config)
+	}
+	return NewPriceOracle(config, sources...), nil
+}
+
+// Price queries every source concurrently within "<config>.timeout".
+func (o *PriceOracle) Price(ctx context.Context, asset string) (*Price, error) {
+	asset = strings.ToUpper(asset)
+	ctx, cancel := context.WithTimeout(ctx, viper.GetDuration(o.config+".timeout"))
+	defer cancel()
+
+	quotes := make([]Quote, len(o.sources))
+	var wg sync.WaitGroup
+	for i, source := range o.sources {
+		wg.Add(1)
+		go func(i int, source Source) {
+			defer wg.Done()
+			quote, err := source.Quote(ctx, asset)
+			switch {
+			case err != nil:
+				quotes[i] = Quote{Source: source.Name(), Error: err.Error()}
+			case quote.Price <= 0:
+				quotes[i] = Quote{Source: source.Name(), Timestamp: quote.Timestamp, Error: "price is not positive"}
+			default:
+				quotes[i] = *quote
+			}
+		}(i, source)
+	}
+	wg.Wait()
+	return aggregate(o.config, asset, quotes)
+}
+
+func aggregate(config string, asset string, quotes []Quote) (*Price, error) {
+	price := Price{
+		Asset:    asset,
+		Sources:  make([]Quote, 0, len(quotes)),
+		Rejected: make([]Quote, 0),
+	}
+	minSources := viper.GetInt(config + ".min-sources")
+	if minSources < 1 {
+		minSources = 1
+	}
+	maxAge := viper.GetDuration(config + ".max-age")
+	valid := make([]Quote, 0, len(quotes))
+	stale := make([]Quote, 0)
+	for _, quote := range quotes {
+		switch {
+		case quote.Error != "":
+			price.Rejected = append(price.Rejected, quote)
+		case maxAge > 0 && time.Since(quote.Timestamp) > maxAge:
+			stale = append(stale, quote)
+		default:
+			valid = append(valid, quote)
+		}
+	}
+	if len(valid) < minSources {
+		valid = append(valid, stale...)
+	} else {
+		for _, quote := range stale {
+			quote.Error = "stale"
+			price.Rejected = append(price.Rejected, quote)
+		}
+	}
+	if len(valid) < minSources {
+		return nil, errors.Wrapf(ErrNoPrice, "%s has %d of %d", asset, len(valid), minSources)
+	}
+
+	mid := median(valid)
+	maxDeviation := viper.GetFloat64(config + ".max-deviation")
+	for _, quote := range valid {
+		if deviation := math.Abs(quote.Price-mid) / mid; maxDeviation > 0 && deviation > maxDeviation {
+			quote.Error = fmt.Sprintf("deviates %.2f%% from median", deviation*100)
+			price.Rejected = append(price.Rejected, quote)
+			continue
+		}
+		price.Sources = append(price.Sources, quote)
+	}
+	if len(price.Sources) < minSources {
+		return nil, errors.Wrapf(ErrNoPrice, "%s has %d of %d within deviation", asset, len(price.Sources), minSources)
+	}
+
+	price.Price = median(price.Sources)
+	// the aggregate is as old as its oldest quote.
+	price.Timestamp = price.Sources[0].Timestamp
+	for _, quote := range price.Sources {
+		if quote.Timestamp.Before(price.Timestamp) {
+			price.Timestamp = quote.Timestamp
+		}
+	}
+	price.Stale = maxAge > 0 && time.Since(price.Timestamp) > maxAge
+	return &price, nil
+}
+
+func median(quotes []Quote) float64 {
+	prices := make([]float64, len(quotes))
+	for i, quote := range quotes {
+		prices[i] = quote.Price
+	}
+	sort.Float64s(prices)
+	n := len(prices)
+	if n%2 == 1 {
+		return prices[n/2]
+	}
+	return (prices[n/2-1] + prices[n/2]) / 2
+}
+
+// GetPriceFn returns the aggregated THB price of asset, or of one unit of a currency for an "fx" oracle.
+type GetPriceFn func(ctx context.Context, asset string) (*Price, error)
+
+func NewGetPriceFn(priceOracle *PriceOracle) GetPriceFn {
+	return func(ctx context.Context, asset string) (*Price, error) {
+		return priceOracle.Price(ctx, asset)
+	}
+}
@@ -0,0 +1,151 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// setOracleConfig sets the aggregation settings of the "test" oracle and resets them after the test.
+func setOracleConfig(t *testing.T, minSources int, maxDeviation float64, maxAge time.Duration) {
+	t.Helper()
+	viper.Set("test.min-sources", minSources)
+	viper.Set("test.max-deviation", maxDeviation)
+	viper.Set("test.max-age", maxAge)
+	viper.Set("test.timeout", time.Second)
+	t.Cleanup(func() {
+		for _, key := range []string{"test.min-sources", "test.max-deviation", "test.max-age", "test.timeout"} {
+			viper.Set(key, nil)
+		}
+	})
+}
+
+func TestMedian(t *testing.T) {
+	tests := []struct {
+		name   string
+		prices []float64
+		want   float64
+	}{
+		{name: "single", prices: []float64{5}, want: 5},
+		{name: "odd", prices: []float64{3, 1, 2}, want: 2},
+		{name: "even", prices: []float64{4, 1, 3, 2}, want: 2.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quotes := make([]Quote, 0, len(tt.prices))
+			for _, price := range tt.prices {
+				quotes = append(quotes, Quote{Price: price})
+			}
+			if got := median(quotes); got != tt.want {
+				t.Errorf("median = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-2 * time.Minute)
+	quote := func(source string, price float64, timestamp time.Time) Quote {
+		return Quote{Source: source, Price: price, Timestamp: timestamp}
+	}
+
+	tests := []struct {
+		name         string
+		minSources   int
+		maxDeviation float64
+		quotes       []Quote
+		price        float64
+		stale        bool
+		sources      int
+		rejected     int
+		err          error
+	}{
+		{name: "median of fresh quotes", minSources: 2, maxDeviation: 0.05, quotes: []Quote{quote("a", 100, now), quote("b", 102, now), quote("c", 101, now)}, price: 101, sources: 3},
+		{name: "outlier rejected", minSources: 2, maxDeviation: 0.05, quotes: []Quote{quote("a", 100, now), quote("b", 102, now), quote("c", 150, now)}, price: 101, sources: 2, rejected: 1},
+		{name: "no deviation limit", minSources: 1, maxDeviation: 0, quotes: []Quote{quote("a", 100, now), quote("b", 200, now)}, price: 150, sources: 2},
+		{name: "source error rejected", minSources: 1, maxDeviation: 0.05, quotes: []Quote{quote("a", 100, now), {Source: "b", Error: "timeout"}}, price: 100, sources: 1, rejected: 1},
+		{name: "too few sources", minSources: 2, maxDeviation: 0.05, quotes: []Quote{quote("a", 100, now), {Source: "b", Error: "timeout"}}, err: ErrNoPrice},
+		{name: "too few within deviation", minSources: 2, maxDeviation: 0.05, quotes: []Quote{quote("a", 100, now), quote("b", 150, now)}, err: ErrNoPrice},
+		{name: "stale quote dropped while enough are fresh", minSources: 2, maxDeviation: 0.05, quotes: []Quote{quote("a", 100, now), quote("b", 102, now), quote("c", 90, old)}, price: 101, sources: 2, rejected: 1},
+		{name: "stale fallback", minSources: 2, maxDeviation: 0.05, quotes: []Quote{quote("a", 100, now), quote("b", 102, old)}, price: 101, stale: true, sources: 2},
+		{name: "stale fallback still too few", minSources: 3, maxDeviation: 0.05, quotes: []Quote{quote("a", 100, now), quote("b", 102, old)}, err: ErrNoPrice},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setOracleConfig(t, tt.minSources, tt.maxDeviation, time.Minute)
+
+			price, err := aggregate("test", "BTC", tt.quotes)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("err = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if price.Price != tt.price || price.Stale != tt.stale {
+				t.Errorf("price = %v (stale %t), want %v (stale %t)", price.Price, price.Stale, tt.price, tt.stale)
+			}
+			if len(price.Sources) != tt.sources || len(price.Rejected) != tt.rejected {
+				t.Errorf("%d sources and %d rejected, want %d and %d", len(price.Sources), len(price.Rejected), tt.sources, tt.rejected)
+			}
+		})
+	}
+}
+
+func TestAggregateTimestampIsOldestSource(t *testing.T) {
+	setOracleConfig(t, 1, 0.05, time.Minute)
+	now := time.Now()
+	older := now.Add(-30 * time.Second)
+	price, err := aggregate("test", "BTC", []Quote{{Source: "a", Price: 100, Timestamp: now}, {Source: "b", Price: 101, Timestamp: older}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !price.Timestamp.Equal(older) || price.Stale {
+		t.Errorf("timestamp = %s (stale %t), want %s", price.Timestamp, price.Stale, older)
+	}
+}
+
+// stubSource quotes a fixed price or fails.
+type stubSource struct {
+	name  string
+	price float64
+	err   error
+}
+
+func (s stubSource) Name() string {
+	return s.name
+}
+
+func (s stubSource) Quote(ctx context.Context, asset string) (*Quote, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &Quote{Source: s.name, Price: s.price, Timestamp: time.Now()}, nil
+}
+
+func TestPriceOracleRejectsFailedAndNonPositiveQuotes(t *testing.T) {
+	setOracleConfig(t, 2, 0.05, time.Minute)
+	oracle := NewPriceOracle("test",
+		stubSource{name: "a", price: 100},
+		stubSource{name: "b", price: 101},
+		stubSource{name: "c", price: 0},
+		stubSource{name: "d", err: errors.New("connection refused")},
+	)
+
+	price, err := oracle.Price(context.Background(), "btc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price.Asset != "BTC" || price.Price != 100.5 {
+		t.Errorf("price of %s = %v, want BTC at 100.5", price.Asset, price.Price)
+	}
+	if len(price.Rejected) != 2 {
+		t.Errorf("rejected = %+v, want the zero and the failed quote", price.Rejected)
+	}
+}
@@ -0,0 +1,64 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"lending-engine/internal/redis"
+	"sync"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// PriceChannel is the redis pub/sub channel of "oracle.assets" prices, every message is a JSON Price.
+const PriceChannel = "PRICE"
+
+// pricePublisher publishes the oracle price of every "oracle.assets" to PriceChannel when its price, timestamp or staleness
+// changed since the last run. Every engine instance publishes, so subscribers drop prices they have already seen.
+type pricePublisher struct {
+	GetPriceFn     GetPriceFn
+	PublishRedisFn redis.PublishRedisFn
+	Logger         *zap.Logger
+	mu             sync.Mutex
+	published      map[string]Price
+}
+
+func NewPricePublisher(getPriceFn GetPriceFn, publishRedisFn redis.PublishRedisFn, logger *zap.Logger) *pricePublisher {
+	return &pricePublisher{
+		GetPriceFn:     getPriceFn,
+		PublishRedisFn: publishRedisFn,
+		Logger:         logger,
+		published:      make(map[string]Price),
+	}
+}
+
+func (p *pricePublisher) Run(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, asset := range viper.GetStringSlice("oracle.assets") {
+		price, err := p.GetPriceFn(ctx, asset)
+		if err != nil {
+			p.Logger.Error(fmt.Sprintf("Asset: %s | %s", asset, err.Error()))
+			continue
+		}
+		if last, ok := p.published[asset]; ok && SamePrice(&last, price) {
+			continue
+		}
+		b, err := json.Marshal(price)
+		if err != nil {
+			return err
+		}
+		if err := p.PublishRedisFn(PriceChannel, string(b)); err != nil {
+			return err
+		}
+		p.published[asset] = *price
+	}
+	return nil
+}
+
+// SamePrice reports whether a and b are the same observation of an asset.
+func SamePrice(a, b *Price) bool {
+	return a.Asset == b.Asset && a.Price == b.Price && a.Timestamp.Equal(b.Timestamp) && a.Stale == b.Stale
+}
@@ -0,0 +1,124 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"lending-engine/common"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// candleRollups builds each interval from the one before it, truncated by the postgres date_trunc field.
+var candleRollups = []struct {
+	Source   string
+	Interval string
+	Field    string
+}{
+	{Source: "1m", Interval: "1h", Field: "hour"},
+	{Source: "1h", Interval: "1d", Field: "day"},
+}
+
+// priceRecorder stores a tick of every "oracle.assets" price and "fx.currencies" rate, rolls ticks up into 1m, 1h and 1d candles
+// and drops ticks and candles past "oracle.history.retention.<interval>" (0 keeps them).
+type priceRecorder struct {
+	HistoryRepository HistoryRepository
+	GetPriceFn        GetPriceFn
+	GetFXRateFn       GetPriceFn
+	Logger            *zap.Logger
+	mu                sync.Mutex
+	rolledUp          time.Time
+	pruned            time.Time
+}
+
+func NewPriceRecorder(historyRepository HistoryRepository, getPriceFn GetPriceFn, getFXRateFn GetPriceFn, logger *zap.Logger) *priceRecorder {
+	return &priceRecorder{
+		HistoryRepository: historyRepository,
+		GetPriceFn:        getPriceFn,
+		GetFXRateFn:       getFXRateFn,
+		Logger:            logger,
+	}
+}
+
+func (r *priceRecorder) Run(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.run(ctx, time.Now())
+}
+
+func (r *priceRecorder) run(ctx context.Context, now time.Time) error {
+	for _, asset := range viper.GetStringSlice("oracle.assets") {
+		if err := r.record(ctx, r.GetPriceFn, asset, now); err != nil {
+			r.Logger.Error(fmt.Sprintf("Asset: %s | %s", asset, err.Error()))
+		}
+	}
+	for _, currency := range viper.GetStringSlice("fx.currencies") {
+		if strings.EqualFold(currency, "THB") {
+			continue
+		}
+		if err := r.record(ctx, r.GetFXRateFn, currency, now); err != nil {
+			r.Logger.Error(fmt.Sprintf("Currency: %s | %s", currency, err.Error()))
+		}
+	}
+
+	// candles since the last rollup are rebuilt whole, the first run after a restart goes back a day.
+	from := r.rolledUp
+	if from.IsZero() {
+		from = now.Add(-24 * time.Hour)
+	}
+	if _, err := r.HistoryRepository.RollupTickRepo(ctx, from.Format(common.DateYYYYMMDDHHMMSSFormat)); err != nil {
+		return err
+	}
+	for _, rollup := range candleRollups {
+		if _, err := r.HistoryRepository.RollupCandleRepo(ctx, rollup.Source, rollup.Interval, rollup.Field, from.Format(common.DateYYYYMMDDHHMMSSFormat)); err != nil {
+			return err
+		}
+	}
+	r.rolledUp = now
+
+	if now.Sub(r.pruned) < time.Hour {
+		return nil
+	}
+	if err := r.prune(ctx, now); err != nil {
+		return err
+	}
+	r.pruned = now
+	return nil
+}
+
+func (r *priceRecorder) record(ctx context.Context, getPriceFn GetPriceFn, asset string, now time.Time) error {
+	price, err := getPriceFn(ctx, asset)
+	if err != nil {
+		return err
+	}
+	sources := make([]string, 0, len(price.Sources))
+	for _, quote := range price.Sources {
+		sources = append(sources, quote.Source)
+	}
+	_, err = r.HistoryRepository.InsertPriceTickRepo(ctx, price.Asset, price.Price, strings.Join(sources, ","), price.Stale, price.Timestamp.Format(common.DateYYYYMMDDHHMMSSFormat), now.Format(common.DateYYYYMMDDHHMMSSFormat))
+	return err
+}
+
+func (r *priceRecorder) prune(ctx context.Context, now time.Time) error {
+	if retention := viper.GetDuration("oracle.history.retention.tick"); retention > 0 {
+		rows, err := r.HistoryRepository.DeletePriceTickRepo(ctx, now.Add(-retention).Format(common.DateYYYYMMDDHHMMSSFormat))
+		if err != nil {
+			return err
+		}
+		r.Logger.Info(fmt.Sprintf("Pruned ticks: %d", rows))
+	}
+	for _, interval := range CandleIntervals {
+		if retention := viper.GetDuration(fmt.Sprintf("oracle.history.retention.%s", interval)); retention > 0 {
+			rows, err := r.HistoryRepository.DeletePriceCandleRepo(ctx, interval, now.Add(-retention).Format(common.DateYYYYMMDDHHMMSSFormat))
+			if err != nil {
+				return err
+			}
+			r.Logger.Info(fmt.Sprintf("Pruned %s candles: %d", interval, rows))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package oracle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lending-engine/common"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+type memTick struct {
+	asset   string
+	price   float64
+	created time.Time
+}
+
+type candleKey struct {
+	asset    string
+	interval string
+	open     time.Time
+}
+
+// memHistoryRepository rolls up ticks and candles the way the SQL of historyRepositoryDB does with date_trunc.
+type memHistoryRepository struct {
+	HistoryRepository
+	ticks   []memTick
+	candles map[candleKey]PriceCandle
+}
+
+func newMemHistoryRepository() *memHistoryRepository {
+	return &memHistoryRepository{candles: make(map[candleKey]PriceCandle)}
+}
+
+func parseDatetime(value string) time.Time {
+	t, err := time.Parse(common.DateYYYYMMDDHHMMSSFormat, value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func truncate(t time.Time, field string) time.Time {
+	switch field {
+	case "minute":
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, t.Location())
+	case "hour":
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	}
+}
+
+func (m *memHistoryRepository) InsertPriceTickRepo(ctx context.Context, asset string, price float64, sources string, stale bool, priceDatetime string, createdDatetime string) (int64, error) {
+	m.ticks = append(m.ticks, memTick{asset: asset, price: price, created: parseDatetime(createdDatetime)})
+	return 1, nil
+}
+
+// upsert folds a price range into the candle of key being built, ranges come in time order so the last one closes it.
+func (m *memHistoryRepository) upsert(built map[candleKey]PriceCandle, key candleKey, open, high, low, close float64, ticks int) {
+	candle, ok := built[key]
+	if !ok {
+		asset, interval, openDatetime := key.asset, key.interval, key.open
+		built[key] = PriceCandle{Asset: &asset, CandleInterval: &interval, OpenDatetime: &openDatetime, OpenPrice: &open, HighPrice: &high, LowPrice: &low, ClosePrice: &close, Ticks: &ticks}
+		return
+	}
+	if high > *candle.HighPrice {
+		candle.HighPrice = &high
+	}
+	if low < *candle.LowPrice {
+		candle.LowPrice = &low
+	}
+	total := *candle.Ticks + ticks
+	candle.ClosePrice, candle.Ticks = &close, &total
+	built[key] = candle
+}
+
+func (m *memHistoryRepository) RollupTickRepo(ctx context.Context, from string) (int64, error) {
+	start := truncate(parseDatetime(from), "minute")
+	built := make(map[candleKey]PriceCandle)
+	for _, tick := range m.ticks {
+		if tick.created.Before(start) {
+			continue
+		}
+		m.upsert(built, candleKey{asset: tick.asset, interval: "1m", open: truncate(tick.created, "minute")}, tick.price, tick.price, tick.price, tick.price, 1)
+	}
+	for key, candle := range built {
+		m.candles[key] = candle
+	}
+	return int64(len(built)), nil
+}
+
+func (m *memHistoryRepository) RollupCandleRepo(ctx context.Context, source string, interval string, field string, from string) (int64, error) {
+	start := truncate(parseDatetime(from), field)
+	sources := m.candlesOf(source)
+	built := make(map[candleKey]PriceCandle)
+	for _, candle := range sources {
+		if candle.OpenDatetime.Before(start) {
+			continue
+		}
+		m.upsert(built, candleKey{asset: *candle.Asset, interval: interval, open: truncate(*candle.OpenDatetime, field)}, *candle.OpenPrice, *candle.HighPrice, *candle.LowPrice, *candle.ClosePrice, *candle.Ticks)
+	}
+	for key, candle := range built {
+		m.candles[key] = candle
+	}
+	return int64(len(built)), nil
+}
+
+func (m *memHistoryRepository) DeletePriceTickRepo(ctx context.Context, before string) (int64, error) {
+	cutoff := parseDatetime(before)
+	kept := make([]memTick, 0, len(m.ticks))
+	for _, tick := range m.ticks {
+		if !tick.created.Before(cutoff) {
+			kept = append(kept, tick)
+		}
+	}
+	rows := int64(len(m.ticks) - len(kept))
+	m.ticks = kept
+	return rows, nil
+}
+
+func (m *memHistoryRepository) DeletePriceCandleRepo(ctx context.Context, interval string, before string) (int64, error) {
+	cutoff := parseDatetime(before)
+	var rows int64
+	for key := range m.candles {
+		if key.interval == interval && key.open.Before(cutoff) {
+			delete(m.candles, key)
+			rows++
+		}
+	}
+	return rows, nil
+}
+
+// candlesOf returns the candles of interval ordered by open time.
+func (m *memHistoryRepository) candlesOf(interval string) []PriceCandle {
+	candles := make([]PriceCandle, 0)
+	for key, candle := range m.candles {
+		if key.interval != interval {
+			continue
+		}
+		i := len(candles)
+		for i > 0 && candles[i-1].OpenDatetime.After(key.open) {
+			i--
+		}
+		candles = append(candles, PriceCandle{})
+		copy(candles[i+1:], candles[i:])
+		candles[i] = candle
+	}
+	return candles
+}
+
+type ohlc struct {
+	open       string
+	o, h, l, c float64
+	ticks      int
+}
+
+func checkCandles(t *testing.T, repository *memHistoryRepository, interval string, want []ohlc) {
+	t.Helper()
+	candles := repository.candlesOf(interval)
+	if len(candles) != len(want) {
+		t.Fatalf("%d %s candles, want %d", len(candles), interval, len(want))
+	}
+	for i, w := range want {
+		candle := candles[i]
+		got := ohlc{open: candle.OpenDatetime.Format(common.DateYYYYMMDDHHMMSSFormat), o: *candle.OpenPrice, h: *candle.HighPrice, l: *candle.LowPrice, c: *candle.ClosePrice, ticks: *candle.Ticks}
+		if got != w {
+			t.Errorf("%s candle %d = %+v, want %+v", interval, i, got, w)
+		}
+	}
+}
+
+func TestPriceRecorderRollsUpAcrossBuckets(t *testing.T) {
+	viper.Set("oracle.assets", []string{"BTC"})
+	viper.Set("oracle.history.retention.tick", time.Hour)
+	viper.Set("oracle.history.retention.1m", time.Hour)
+	defer func() {
+		for _, key := range []string{"oracle.assets", "oracle.history.retention.tick", "oracle.history.retention.1m"} {
+			viper.Set(key, nil)
+		}
+	}()
+
+	repository := newMemHistoryRepository()
+	price := 0.0
+	getPriceFn := func(ctx context.Context, asset string) (*Price, error) {
+		return &Price{Asset: asset, Price: price, Timestamp: time.Now()}, nil
+	}
+	recorder := NewPriceRecorder(repository, getPriceFn, nil, zap.NewNop())
+	run := func(at string, p float64) {
+		t.Helper()
+		price = p
+		if err := recorder.run(context.Background(), parseDatetime(at)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the second run rolls up from the middle of the minute, the candle keeps the open of the first tick.
+	run("2021-01-02 10:59:20", 100)
+	run("2021-01-02 10:59:50", 120)
+	checkCandles(t, repository, "1m", []ohlc{{open: "2021-01-02 10:59:00", o: 100, h: 120, l: 100, c: 120, ticks: 2}})
+
+	// a tick on the hour opens the next minute and the next hour.
+	run("2021-01-02 11:00:00", 90)
+	run("2021-01-02 11:00:40", 95)
+	checkCandles(t, repository, "1m", []ohlc{
+		{open: "2021-01-02 10:59:00", o: 100, h: 120, l: 100, c: 120, ticks: 2},
+		{open: "2021-01-02 11:00:00", o: 90, h: 95, l: 90, c: 95, ticks: 2},
+	})
+	checkCandles(t, repository, "1h", []ohlc{
+		{open: "2021-01-02 10:00:00", o: 100, h: 120, l: 100, c: 120, ticks: 2},
+		{open: "2021-01-02 11:00:00", o: 90, h: 95, l: 90, c: 95, ticks: 2},
+	})
+	checkCandles(t, repository, "1d", []ohlc{{open: "2021-01-02 00:00:00", o: 100, h: 120, l: 90, c: 95, ticks: 4}})
+
+	// an hour on, ticks and 1m candles past their retention are dropped and the 1h and 1d candles built from them stay.
+	run("2021-01-02 12:30:00", 110)
+	run("2021-01-02 12:31:00", 105)
+	if len(repository.ticks) != 2 {
+		t.Errorf("%d ticks, want the 2 within an hour", len(repository.ticks))
+	}
+	checkCandles(t, repository, "1m", []ohlc{
+		{open: "2021-01-02 12:30:00", o: 110, h: 110, l: 110, c: 110, ticks: 1},
+		{open: "2021-01-02 12:31:00", o: 105, h: 105, l: 105, c: 105, ticks: 1},
+	})
+	checkCandles(t, repository, "1h", []ohlc{
+		{open: "2021-01-02 10:00:00", o: 100, h: 120, l: 100, c: 120, ticks: 2},
+		{open: "2021-01-02 11:00:00", o: 90, h: 95, l: 90, c: 95, ticks: 2},
+		{open: "2021-01-02 12:00:00", o: 110, h: 110, l: 105, c: 105, ticks: 2},
+	})
+	checkCandles(t, repository, "1d", []ohlc{{open: "2021-01-02 00:00:00", o: 100, h: 120, l: 90, c: 105, ticks: 6}})
+}
@@ -0,0 +1,159 @@
+package oracle
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type historyRepositoryDB struct {
+	db *sqlx.DB
+}
+
+func NewHistoryRepositoryDB(db *sqlx.DB) historyRepositoryDB {
+	return historyRepositoryDB{
+		db: db,
+	}
+}
+
+func (r historyRepositoryDB) InsertPriceTickRepo(ctx context.Context, asset string, price float64, sources string, stale bool, priceDatetime string, createdDatetime string) (int64, error) {
+	var id int64
+	err := r.db.QueryRowxContext(ctx, `
+		INSERT INTO lending.public.price_tick
+		(asset, price, sources, stale, price_datetime, created_datetime)
+		VALUES($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	;`, asset, price, sources, stale, priceDatetime, createdDatetime).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// RollupTickRepo rebuilds 1m candles from ticks recorded since from.
+func (r historyRepositoryDB) RollupTickRepo(ctx context.Context, from string) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO lending.public.price_candle
+		(asset, candle_interval, open_datetime, open_price, high_price, low_price, close_price, ticks, updated_datetime)
+		SELECT	asset,
+				'1m',
+				date_trunc('minute', created_datetime) AS open_datetime,
+				(array_agg(price ORDER BY created_datetime, id))[1],
+				MAX(price),
+				MIN(price),
+				(array_agg(price ORDER BY created_datetime DESC, id DESC))[1],
+				COUNT(*),
+				CURRENT_TIMESTAMP
+		FROM lending.public.price_tick
+		WHERE created_datetime >= date_trunc('minute', $1::timestamp)
+		GROUP BY asset, open_datetime
+		ON CONFLICT (asset, candle_interval, open_datetime) DO UPDATE SET
+			open_price = EXCLUDED.open_price,
+			high_price = EXCLUDED.high_price,
+			low_price = EXCLUDED.low_price,
+			close_price = EXCLUDED.close_price,
+			ticks = EXCLUDED.ticks,
+			updated_datetime = EXCLUDED.updated_datetime
+	;`, from)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// RollupCandleRepo rebuilds interval candles truncated by field (hour or day) from source candles opened since from.
+func (r historyRepositoryDB) RollupCandleRepo(ctx context.Context, source string, interval string, field string, from string) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO lending.public.price_candle
+		(asset, candle_interval, open_datetime, open_price, high_price, low_price, close_price, ticks, updated_datetime)
+		SELECT	asset,
+				$2,
+				date_trunc($3, open_datetime) AS bucket,
+				(array_agg(open_price ORDER BY open_datetime))[1],
+				MAX(high_price),
+				MIN(low_price),
+				(array_agg(close_price ORDER BY open_datetime DESC))[1],
+				SUM(ticks),
+				CURRENT_TIMESTAMP
+		FROM lending.public.price_candle
+		WHERE candle_interval = $1
+		AND open_datetime >= date_trunc($3, $4::timestamp)
+		GROUP BY asset, bucket
+		ON CONFLICT (asset, candle_interval, open_datetime) DO UPDATE SET
+			open_price = EXCLUDED.open_price,
+			high_price = EXCLUDED.high_price,
+			low_price = EXCLUDED.low_price,
+			close_price = EXCLUDED.close_price,
+			ticks = EXCLUDED.ticks,
+			updated_datetime = EXCLUDED.updated_datetime
+	;`, source, interval, field, from)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r historyRepositoryDB) DeletePriceTickRepo(ctx context.Context, before string) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM lending.public.price_tick
+		WHERE created_datetime < $1
+	;`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r historyRepositoryDB) DeletePriceCandleRepo(ctx context.Context, interval string, before string) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM lending.public.price_candle
+		WHERE candle_interval = $1
+		AND open_datetime < $2
+	;`, interval, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r historyRepositoryDB) QueryPriceCandleRepo(ctx context.Context, asset string, interval string, from string, to string) (*[]PriceCandle, error) {
+	candles := make([]PriceCandle, 0)
+	err := r.db.SelectContext(ctx, &candles, `
+		SELECT asset, candle_interval, open_datetime, open_price, high_price, low_price, close_price, ticks
+		FROM lending.public.price_candle
+		WHERE asset = $1
+		AND candle_interval = $2
+		AND open_datetime >= $3
+		AND open_datetime <= $4
+		ORDER BY open_datetime
+	;`, asset, interval, from, to)
+	switch {
+	case err == sql.ErrNoRows:
+		return &candles, nil
+	case err != nil:
+		return nil, err
+	default:
+		return &candles, nil
+	}
+}
+
+func (r historyRepositoryDB) QueryPriceTickRepo(ctx context.Context, asset string, from string, to string) (*[]PriceTick, error) {
+	ticks := make([]PriceTick, 0)
+	err := r.db.SelectContext(ctx, &ticks, `
+		SELECT id, asset, price, sources, stale, price_datetime, created_datetime
+		FROM lending.public.price_tick
+		WHERE asset = $1
+		AND created_datetime >= $2
+		AND created_datetime <= $3
+		ORDER BY created_datetime, id
+	;`, asset, from, to)
+	switch {
+	case err == sql.ErrNoRows:
+		return &ticks, nil
+	case err != nil:
+		return nil, err
+	default:
+		return &ticks, nil
+	}
+}
@@ -0,0 +1,199 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"lending-engine/blockchain"
+	"lending-engine/internal/redis"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Source reads the THB price of asset from one feed.
+type Source interface {
+	Name() string
+	Quote(ctx context.Context, asset string) (*Quote, error)
+}
+
+// redisSource reads "THB/<asset>" and its "THB/<asset>:timestamp" written by the websocket feeder.
+type redisSource struct {
+	name                string
+	getFloatDataRedisFn redis.GetFloatDataRedisFn
+}
+
+func NewRedisSource(name string, getFloatDataRedisFn redis.GetFloatDataRedisFn) Source {
+	return &redisSource{
+		name:                name,
+		getFloatDataRedisFn: getFloatDataRedisFn,
+	}
+}
+
+func (s *redisSource) Name() string {
+	return s.name
+}
+
+func (s *redisSource) Quote(ctx context.Context, asset string) (*Quote, error) {
+	key := fmt.Sprintf("THB/%s", asset)
+	price, err := s.getFloatDataRedisFn(key)
+	if err != nil {
+		return nil, err
+	}
+	// the feeder writes unix seconds of the price to "<key>:timestamp", a missing one leaves the quote at zero time (stale).
+	timestamp, err := s.getFloatDataRedisFn(key + ":timestamp")
+	if err != nil {
+		return nil, err
+	}
+	quote := Quote{
+		Source: s.name,
+		Price:  price,
+	}
+	if timestamp > 0 {
+		quote.Timestamp = time.Unix(int64(timestamp), 0)
+	}
+	return &quote, nil
+}
+
+// restSource reads a ticker of an exchange REST api. "{asset}" in url and path is replaced by the asset,
+// path is the dot-separated field of the price in the json response, e.g. "THB_{asset}.last".
+type restSource struct {
+	name       string
+	url        string
+	path       string
+	httpClient *http.Client
+}
+
+func NewRESTSource(name string, url string, path string, timeout time.Duration) Source {
+	return &restSource{
+		name: name,
+		url:  url,
+		path: path,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+func (s *restSource) Name() string {
+	return s.name
+}
+
+func (s *restSource) Quote(ctx context.Context, asset string) (*Quote, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.ReplaceAll(s.url, "{asset}", asset), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("%s returned http %d", s.name, resp.StatusCode)
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return nil, err
+	}
+	for _, field := range strings.Split(strings.ReplaceAll(s.path, "{asset}", asset), ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("%s response has no field '%s'", s.name, field)
+		}
+		value = object[field]
+	}
+	var price float64
+	switch v := value.(type) {
+	case float64:
+		price = v
+	case string:
+		if price, err = strconv.ParseFloat(v, 64); err != nil {
+			return nil, errors.Wrapf(err, "%s price", s.name)
+		}
+	default:
+		return nil, errors.Errorf("%s response has no price at '%s'", s.name, s.path)
+	}
+	return &Quote{
+		Source:    s.name,
+		Price:     price,
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// chainlinkSource reads Chainlink AggregatorV3Interface feeds on a chain. feeds maps an asset to its feed, quoted in the currency
+// whose THB/<currency> feed is quoteFeed, e.g. BTC/USD over THB/USD. An empty quoteFeed means the feeds are quoted in THB.
+// An answer older than maxAge (quoteMaxAge for quoteFeed) is refused, 0 disables the check. Like a REST ticker the quote is
+// as of the read, since a feed's answer stands until its next deviation or heartbeat update.
+type chainlinkSource struct {
+	name                     string
+	chainId                  int
+	feeds                    map[string]string
+	quoteFeed                string
+	maxAge                   time.Duration
+	quoteMaxAge              time.Duration
+	queryLatestRoundClientFn blockchain.QueryLatestRoundClientFn
+}
+
+func NewChainlinkSource(name string, chainId int, feeds map[string]string, quoteFeed string, maxAge time.Duration, quoteMaxAge time.Duration, queryLatestRoundClientFn blockchain.QueryLatestRoundClientFn) Source {
+	assetFeeds := make(map[string]string)
+	for asset, feed := range feeds {
+		assetFeeds[strings.ToUpper(asset)] = feed
+	}
+	return &chainlinkSource{
+		name:                     name,
+		chainId:                  chainId,
+		feeds:                    assetFeeds,
+		quoteFeed:                quoteFeed,
+		maxAge:                   maxAge,
+		quoteMaxAge:              quoteMaxAge,
+		queryLatestRoundClientFn: queryLatestRoundClientFn,
+	}
+}
+
+func (s *chainlinkSource) Name() string {
+	return s.name
+}
+
+func (s *chainlinkSource) Quote(ctx context.Context, asset string) (*Quote, error) {
+	feed, ok := s.feeds[asset]
+	if !ok {
+		return nil, errors.Errorf("%s has no %s feed", s.name, asset)
+	}
+	price, err := s.answer(ctx, feed, s.maxAge)
+	if err != nil {
+		return nil, err
+	}
+	if s.quoteFeed != "" {
+		rate, err := s.answer(ctx, s.quoteFeed, s.quoteMaxAge)
+		if err != nil {
+			return nil, err
+		}
+		price = price / rate
+	}
+	return &Quote{
+		Source:    s.name,
+		Price:     price,
+		Timestamp: time.Now(),
+	}, nil
+}
+
+func (s *chainlinkSource) answer(ctx context.Context, feed string, maxAge time.Duration) (float64, error) {
+	round, err := s.queryLatestRoundClientFn(ctx, s.chainId, feed)
+	if err != nil {
+		return 0, err
+	}
+	if maxAge > 0 && time.Since(round.UpdatedAt) > maxAge {
+		return 0, errors.Wrapf(ErrStalePrice, "feed %s updated at %s", feed, round.UpdatedAt.Format(time.RFC3339))
+	}
+	return round.Answer, nil
+}
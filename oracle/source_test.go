@@ -0,0 +1,112 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"lending-engine/blockchain"
+	"lending-engine/blockchain/chaintest"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const testChainId = 1337
+
+// newTestFeeds deploys an aggregator mock for each of answers on a simulated chain registered as chain 1337 and returns
+// their addresses. Every feed has 8 decimals and answers its value as round 1 updated at updatedAt.
+func newTestFeeds(t *testing.T, updatedAt map[string]time.Time, answers map[string]float64) (blockchain.QueryLatestRoundClientFn, map[string]string) {
+	t.Helper()
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, big.NewInt(testChainId))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := blockchain.NewSimulatedChain(backends.NewSimulatedBackend(core.GenesisAlloc{
+		auth.From: {Balance: chaintest.Wei(1000)},
+	}, 30000000))
+	t.Cleanup(client.Close)
+
+	feeds := make(map[string]string)
+	for name, answer := range answers {
+		address, contract, err := chaintest.DeployAggregator(auth, client)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := contract.Transact(auth, "setDecimals", uint8(8)); err != nil {
+			t.Fatal(err)
+		}
+		scaled, _ := new(big.Float).Mul(big.NewFloat(answer), big.NewFloat(1e8)).Int(nil)
+		updated := big.NewInt(updatedAt[name].Unix())
+		if _, err := contract.Transact(auth, "setRoundData", big.NewInt(1), scaled, updated, updated, big.NewInt(1)); err != nil {
+			t.Fatal(err)
+		}
+		feeds[name] = address.Hex()
+	}
+	registry := blockchain.NewChainRegistryFromChains(&blockchain.Chain{ChainID: testChainId, Name: "simulated", Client: client})
+	return blockchain.NewQueryLatestRoundClientFn(registry), feeds
+}
+
+func TestChainlinkSourceQuote(t *testing.T) {
+	now := time.Now()
+	queryLatestRound, feeds := newTestFeeds(t,
+		map[string]time.Time{"BTC/USD": now, "ETH/USD": now.Add(-2 * time.Hour), "THB/USD": now, "BTC/THB": now},
+		map[string]float64{"BTC/USD": 30000, "ETH/USD": 2000, "THB/USD": 0.03, "BTC/THB": 1000000},
+	)
+
+	tests := []struct {
+		name      string
+		feeds     map[string]string
+		quoteFeed string
+		asset     string
+		price     float64
+		stale     bool
+	}{
+		// 30,000 USD / 0.03 USD per THB.
+		{name: "usd feed over thb/usd", feeds: map[string]string{"btc": feeds["BTC/USD"]}, quoteFeed: feeds["THB/USD"], asset: "BTC", price: 1000000},
+		{name: "thb feed", feeds: map[string]string{"btc": feeds["BTC/THB"]}, asset: "BTC", price: 1000000},
+		{name: "stale feed", feeds: map[string]string{"eth": feeds["ETH/USD"]}, quoteFeed: feeds["THB/USD"], asset: "ETH", stale: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := NewChainlinkSource("chainlink", testChainId, tt.feeds, tt.quoteFeed, time.Hour, time.Hour, queryLatestRound)
+
+			quote, err := source.Quote(context.Background(), tt.asset)
+			if tt.stale {
+				if !errors.Is(err, ErrStalePrice) {
+					t.Fatalf("err = %v, want %v", err, ErrStalePrice)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(quote.Price-tt.price) > 1e-6 {
+				t.Errorf("price = %f, want %f", quote.Price, tt.price)
+			}
+		})
+	}
+}
+
+func TestChainlinkSourceStaleQuoteFeed(t *testing.T) {
+	now := time.Now()
+	queryLatestRound, feeds := newTestFeeds(t,
+		map[string]time.Time{"BTC/USD": now, "THB/USD": now.Add(-25 * time.Hour)},
+		map[string]float64{"BTC/USD": 30000, "THB/USD": 0.03},
+	)
+	// the THB/USD feed has a daily heartbeat, so its own max age is longer than the asset feed's.
+	source := NewChainlinkSource("chainlink", testChainId, map[string]string{"btc": feeds["BTC/USD"]}, feeds["THB/USD"], time.Hour, 24*time.Hour, queryLatestRound)
+
+	if _, err := source.Quote(context.Background(), "BTC"); !errors.Is(err, ErrStalePrice) {
+		t.Fatalf("err = %v, want %v", err, ErrStalePrice)
+	}
+}
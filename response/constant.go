@@ -13,11 +13,14 @@ const (
 	ErrOperationCode           uint64 = 5002
 	ErrBlockchainCode          uint64 = 5003
 	ErrThirdPartyCode          uint64 = 5004
+	ErrPriceCode               uint64 = 5005
 )
 
 const (
 	SuccessMessageEN           string = "Success."
 	ErrInternalServerMessageEN string = "Internal server error."
+	ErrStalePriceMessageEN     string = "Price is outdated."
+	ErrPriceHaltedMessageEN    string = "Trading is paused by extreme price movement."
 	// Account
 	SuccessSignUpMessageEN               string = "Success sign up account."
 	ErrSignUpMessageEN                   string = "Cannot sign up account."
@@ -44,25 +47,55 @@ const (
 	ErrUpdateDocumentInfoAdminMessageEN     string = "Cannot update document info."
 	// Lending
 	//// User
-	SuccessGetToknPriceMessageEN         string = "Success get token price."
-	SuccessPreCalculationLoanMessageEN   string = "Success calculate loan."
-	ErrPreCalculationLoanMessageEN       string = "Cannot calculate loan."
-	SuccessGetWalletTransactionMessageEN string = "Success get wallet transaction."
-	ErrGetWalletTransactionMessageEN     string = "Cannot get wallet transaction."
-	SuccessSubmitDepositMessageEN        string = "Success submit deposit token."
-	ErrSubmitDepositMessageEN            string = "Cannot submit deposit token."
-	SuccessSubmitWithdrawMessageEN       string = "Success submit withdraw token."
-	ErrSubmitWithdrawMessageEN           string = "Cannot submit withdraw token."
-	SuccessGetCreditAvailableMessageEN   string = "Success get credit available."
-	SuccessGetLoanMessageEN              string = "Success get loan."
-	ErrGetLoanMessageEN                  string = "Cannot get loan."
-	SuccessBorrowLoanMessageEN           string = "Success borrow loan."
-	ErrBorrowLoanMessageEN               string = "Cannot borrow loan."
-	SuccessGetInterestTermMessageEN      string = "Success get interest term."
-	SuccessGetRepaymentMessageEN         string = "Success get repayment."
-	ErrGetRepaymentMessageEN             string = "Cannot get repayment."
-	SuccessSubmitRepaymentMessageEN      string = "Success submit repayment."
-	ErrSubmitRepaymentMessageEN          string = "Cannot submit repayment."
+	SuccessGetToknPriceMessageEN          string = "Success get token price."
+	ErrGetTokenPriceMessageEN             string = "Cannot get token price."
+	SuccessPreCalculationLoanMessageEN    string = "Success calculate loan."
+	ErrPreCalculationLoanMessageEN        string = "Cannot calculate loan."
+	SuccessGetWalletTransactionMessageEN  string = "Success get wallet transaction."
+	ErrGetWalletTransactionMessageEN      string = "Cannot get wallet transaction."
+	SuccessSubmitDepositMessageEN         string = "Success submit deposit token."
+	ErrSubmitDepositMessageEN             string = "Cannot submit deposit token."
+	SuccessSubmitWithdrawMessageEN        string = "Success submit withdraw token."
+	ErrSubmitWithdrawMessageEN            string = "Cannot submit withdraw token."
+	SuccessGetWithdrawFeeMessageEN        string = "Success get withdraw fee."
+	ErrGetWithdrawFeeMessageEN            string = "Cannot get withdraw fee."
+	SuccessGetReservesMessageEN           string = "Success get proof of reserves."
+	ErrGetReservesMessageEN               string = "Cannot get proof of reserves."
+	SuccessGetReserveProofMessageEN       string = "Success get reserve inclusion proof."
+	ErrGetReserveProofMessageEN           string = "Cannot get reserve inclusion proof."
+	SuccessGetTreasuryAdminMessageEN      string = "Success get treasury balances."
+	ErrGetTreasuryAdminMessageEN          string = "Cannot get treasury balances."
+	SuccessGetPriceHistoryMessageEN       string = "Success get price history."
+	ErrGetPriceHistoryMessageEN           string = "Cannot get price history."
+	SuccessGetBreakerAdminMessageEN       string = "Success get circuit breaker."
+	ErrGetBreakerAdminMessageEN           string = "Cannot get circuit breaker."
+	SuccessOverrideBreakerAdminMessageEN  string = "Success override circuit breaker."
+	ErrOverrideBreakerAdminMessageEN      string = "Cannot override circuit breaker."
+	SuccessGetHaircutAdminMessageEN       string = "Success get haircut."
+	ErrGetHaircutAdminMessageEN           string = "Cannot get haircut."
+	SuccessApproveHaircutAdminMessageEN   string = "Success approve haircut proposal."
+	ErrApproveHaircutAdminMessageEN       string = "Cannot approve haircut proposal."
+	SuccessRejectHaircutAdminMessageEN    string = "Success reject haircut proposal."
+	ErrRejectHaircutAdminMessageEN        string = "Cannot reject haircut proposal."
+	SuccessGetCreditAvailableMessageEN    string = "Success get credit available."
+	ErrGetCreditAvailableMessageEN        string = "Cannot get credit available."
+	SuccessGetLoanMessageEN               string = "Success get loan."
+	ErrGetLoanMessageEN                   string = "Cannot get loan."
+	SuccessBorrowLoanMessageEN            string = "Success borrow loan."
+	ErrBorrowLoanMessageEN                string = "Cannot borrow loan."
+	SuccessGetInterestTermMessageEN       string = "Success get interest term."
+	SuccessGetRepaymentMessageEN          string = "Success get repayment."
+	ErrGetRepaymentMessageEN              string = "Cannot get repayment."
+	SuccessSubmitRepaymentMessageEN       string = "Success submit repayment."
+	ErrSubmitRepaymentMessageEN           string = "Cannot submit repayment."
+	SuccessGetDepositAddressMessageEN     string = "Success get deposit address."
+	ErrGetDepositAddressMessageEN         string = "Cannot get deposit address."
+	SuccessGetWithdrawAddressMessageEN    string = "Success get withdraw address."
+	ErrGetWithdrawAddressMessageEN        string = "Cannot get withdraw address."
+	SuccessAddWithdrawAddressMessageEN    string = "Success add withdraw address."
+	ErrAddWithdrawAddressMessageEN        string = "Cannot add withdraw address."
+	SuccessRemoveWithdrawAddressMessageEN string = "Success remove withdraw address."
+	ErrRemoveWithdrawAddressMessageEN     string = "Cannot remove withdraw address."
 	//// Admin
 	SuccessGetAccountAdminMessageEN            string = "Success get account detail."
 	ErrGetAccountAdminMessageEN                string = "Cannot get account detail."
@@ -82,6 +115,18 @@ const (
 	ErrConfirmWithdrawAdminMessageEN           string = "Cannot confirm withdraw token."
 	SuccessRejectWithdrawAdminMessageEN        string = "Success reject withdraw token."
 	ErrRejectWithdrawAdminMessageEN            string = "Cannot reject withdraw token."
+	SuccessSpeedUpWithdrawAdminMessageEN       string = "Success speed up withdraw token."
+	ErrSpeedUpWithdrawAdminMessageEN           string = "Cannot speed up withdraw token."
+	SuccessBatchWithdrawAdminMessageEN         string = "Success batch withdraw."
+	ErrBatchWithdrawAdminMessageEN             string = "Cannot batch withdraw."
+	SuccessGetUnsignedWithdrawAdminMessageEN   string = "Success get unsigned withdraw transaction."
+	ErrGetUnsignedWithdrawAdminMessageEN       string = "Cannot get unsigned withdraw transaction."
+	SuccessImportSignedWithdrawAdminMessageEN  string = "Success broadcast signed withdraw transaction."
+	ErrImportSignedWithdrawAdminMessageEN      string = "Cannot broadcast signed withdraw transaction."
+	SuccessGetUnsignedSweepAdminMessageEN      string = "Success get unsigned sweep transaction."
+	ErrGetUnsignedSweepAdminMessageEN          string = "Cannot get unsigned sweep transaction."
+	SuccessImportSignedSweepAdminMessageEN     string = "Success broadcast signed sweep transaction."
+	ErrImportSignedSweepAdminMessageEN         string = "Cannot broadcast signed sweep transaction."
 	SuccessGetContractAdminMessageEN           string = "Success get loan contract."
 	ErrGetContractAdminMessageEN               string = "Cannot get loan contract."
 	SuccessConfirmContractAdminMessageEN       string = "Success confirm loan contract."
@@ -98,6 +143,8 @@ const (
 	ErrRejectRepaymentAdminMessageEN           string = "Cannot reject repayment."
 	SuccessLiquidateFundAdminMessageEN         string = "Success liquidate fund."
 	ErrLiquidateFundAdminMessageEN             string = "Cannot liquidate fund."
+	SuccessStressTestAdminMessageEN            string = "Success stress test."
+	ErrStressTestAdminMessageEN                string = "Cannot stress test."
 	// Mail
 	SuccessOTPRequestMessageEN      string = "Success request otp."
 	ErrOTPRequestMessageEN          string = "Cannot request otp."
@@ -121,6 +168,8 @@ const (
 const (
 	SuccessMessageTH           string = "สำเร็จ."
 	ErrInternalServerMessageTH string = "มีข้อผิดพลาดภายในเซิร์ฟเวอร์."
+	ErrStalePriceMessageTH     string = "ราคาไม่เป็นปัจจุบัน."
+	ErrPriceHaltedMessageTH    string = "ระงับการทำรายการชั่วคราวเนื่องจากราคาผันผวนรุนแรง."
 	// Account
 	SuccessSignUpMessageTH               string = "สมัครบัญชีเข้าใช้งานสำเร็จ."
 	ErrSignUpMessageTH                   string = "ไม่สามารถสมัครบัญชีเข้าใช้งานได้."
@@ -147,25 +196,55 @@ const (
 	ErrUpdateDocumentInfoAdminMessageTH     string = "ไม่สามารถแก้ไขข้อมูลเอกสารได้."
 	// Lending
 	//// User
-	SuccessGetToknPriceMessageTH         string = "แสดงราคาซื้อขายโทเคนสำเร็จ."
-	SuccessPreCalculationLoanMessageTH   string = "คำนวณอัตราเงินกู้สำเร็จ."
-	ErrPreCalculationLoanMessageTH       string = "ไม่สามารถคำนวณอัตราเงินกู้ได้."
-	SuccessGetWalletTransactionMessageTH string = "แสดงรายการฝากถอนโทเคนสำเร็จ."
-	ErrGetWalletTransactionMessageTH     string = "ไม่สามารถแสดงรายการฝากถอนโทเคนได้."
-	SuccessSubmitDepositMessageTH        string = "ส่งหลักฐานยืนยันการฝากโทเคนสำเร็จ."
-	ErrSubmitDepositMessageTH            string = "ไม่สามารถส่งหลักฐานยืนยันการฝากโทเคนได้."
-	SuccessSubmitWithdrawMessageTH       string = "ส่งคำร้องขอถอนโทเคนสำเร็จ."
-	ErrSubmitWithdrawMessageTH           string = "ไม่สามารถส่งคำร้องขอถอนโทเคนได้."
-	SuccessGetCreditAvailableMessageTH   string = "แสดงเครดิตคงเหลือสำเร็จ."
-	SuccessGetLoanMessageTH              string = "แสดงการกู้ยืมเงินสำเร็จ."
-	ErrGetLoanMessageTH                  string = "ไม่สามารถแสดงการกู้ยืมเงินได้."
-	SuccessBorrowLoanMessageTH           string = "กู้ยืมเงินสำเร็จ."
-	ErrBorrowLoanMessageTH               string = "ไม่สามารถกู้ยืมเงินได้."
-	SuccessGetInterestTermMessageTH      string = "แสดงอัตราดอกเบี้ยสำเร็จ."
-	SuccessGetRepaymentMessageTH         string = "แสดงรายการจ่ายเงินคืนสำเร็จ."
-	ErrGetRepaymentMessageTH             string = "ไม่สามารถแสดงรายการจ่ายเงินคืนได้."
-	SuccessSubmitRepaymentMessageTH      string = "ส่งหลักฐานยืนยันการจ่ายเงินคืนสำเร็จ."
-	ErrSubmitRepaymentMessageTH          string = "ไม่สามารถส่งหลักฐานยืนยันการจ่ายเงินคืนได้."
+	SuccessGetToknPriceMessageTH          string = "แสดงราคาซื้อขายโทเคนสำเร็จ."
+	ErrGetTokenPriceMessageTH             string = "ไม่สามารถแสดงราคาซื้อขายโทเคนได้."
+	SuccessPreCalculationLoanMessageTH    string = "คำนวณอัตราเงินกู้สำเร็จ."
+	ErrPreCalculationLoanMessageTH        string = "ไม่สามารถคำนวณอัตราเงินกู้ได้."
+	SuccessGetWalletTransactionMessageTH  string = "แสดงรายการฝากถอนโทเคนสำเร็จ."
+	ErrGetWalletTransactionMessageTH      string = "ไม่สามารถแสดงรายการฝากถอนโทเคนได้."
+	SuccessSubmitDepositMessageTH         string = "ส่งหลักฐานยืนยันการฝากโทเคนสำเร็จ."
+	ErrSubmitDepositMessageTH             string = "ไม่สามารถส่งหลักฐานยืนยันการฝากโทเคนได้."
+	SuccessSubmitWithdrawMessageTH        string = "ส่งคำร้องขอถอนโทเคนสำเร็จ."
+	ErrSubmitWithdrawMessageTH            string = "ไม่สามารถส่งคำร้องขอถอนโทเคนได้."
+	SuccessGetWithdrawFeeMessageTH        string = "ดึงค่าธรรมเนียมการถอนสำเร็จ."
+	ErrGetWithdrawFeeMessageTH            string = "ไม่สามารถดึงค่าธรรมเนียมการถอนได้."
+	SuccessGetReservesMessageTH           string = "ดึงหลักฐานทุนสำรองสำเร็จ."
+	ErrGetReservesMessageTH               string = "ไม่สามารถดึงหลักฐานทุนสำรองได้."
+	SuccessGetReserveProofMessageTH       string = "ดึงหลักฐานยอดคงเหลือในทุนสำรองสำเร็จ."
+	ErrGetReserveProofMessageTH           string = "ไม่สามารถดึงหลักฐานยอดคงเหลือในทุนสำรองได้."
+	SuccessGetTreasuryAdminMessageTH      string = "ดึงยอดคงเหลือของคลังสำเร็จ."
+	ErrGetTreasuryAdminMessageTH          string = "ไม่สามารถดึงยอดคงเหลือของคลังได้."
+	SuccessGetPriceHistoryMessageTH       string = "ดึงข้อมูลประวัติราคาสำเร็จ."
+	ErrGetPriceHistoryMessageTH           string = "ไม่สามารถดึงข้อมูลประวัติราคาได้."
+	SuccessGetBreakerAdminMessageTH       string = "ดึงข้อมูลตัวตัดวงจรราคาสำเร็จ."
+	ErrGetBreakerAdminMessageTH           string = "ไม่สามารถดึงข้อมูลตัวตัดวงจรราคาได้."
+	SuccessOverrideBreakerAdminMessageTH  string = "ปรับสถานะตัวตัดวงจรราคาสำเร็จ."
+	ErrOverrideBreakerAdminMessageTH      string = "ไม่สามารถปรับสถานะตัวตัดวงจรราคาได้."
+	SuccessGetHaircutAdminMessageTH       string = "ดึงข้อมูลอัตราส่วนลดหลักประกันสำเร็จ."
+	ErrGetHaircutAdminMessageTH           string = "ไม่สามารถดึงข้อมูลอัตราส่วนลดหลักประกันได้."
+	SuccessApproveHaircutAdminMessageTH   string = "อนุมัติข้อเสนออัตราส่วนลดหลักประกันสำเร็จ."
+	ErrApproveHaircutAdminMessageTH       string = "ไม่สามารถอนุมัติข้อเสนออัตราส่วนลดหลักประกันได้."
+	SuccessRejectHaircutAdminMessageTH    string = "ปฏิเสธข้อเสนออัตราส่วนลดหลักประกันสำเร็จ."
+	ErrRejectHaircutAdminMessageTH        string = "ไม่สามารถปฏิเสธข้อเสนออัตราส่วนลดหลักประกันได้."
+	SuccessGetCreditAvailableMessageTH    string = "แสดงเครดิตคงเหลือสำเร็จ."
+	ErrGetCreditAvailableMessageTH        string = "ไม่สามารถแสดงเครดิตคงเหลือได้."
+	SuccessGetLoanMessageTH               string = "แสดงการกู้ยืมเงินสำเร็จ."
+	ErrGetLoanMessageTH                   string = "ไม่สามารถแสดงการกู้ยืมเงินได้."
+	SuccessBorrowLoanMessageTH            string = "กู้ยืมเงินสำเร็จ."
+	ErrBorrowLoanMessageTH                string = "ไม่สามารถกู้ยืมเงินได้."
+	SuccessGetInterestTermMessageTH       string = "แสดงอัตราดอกเบี้ยสำเร็จ."
+	SuccessGetRepaymentMessageTH          string = "แสดงรายการจ่ายเงินคืนสำเร็จ."
+	ErrGetRepaymentMessageTH              string = "ไม่สามารถแสดงรายการจ่ายเงินคืนได้."
+	SuccessSubmitRepaymentMessageTH       string = "ส่งหลักฐานยืนยันการจ่ายเงินคืนสำเร็จ."
+	ErrSubmitRepaymentMessageTH           string = "ไม่สามารถส่งหลักฐานยืนยันการจ่ายเงินคืนได้."
+	SuccessGetDepositAddressMessageTH     string = "แสดงที่อยู่สำหรับฝากโทเคนสำเร็จ."
+	ErrGetDepositAddressMessageTH         string = "ไม่สามารถแสดงที่อยู่สำหรับฝากโทเคนได้."
+	SuccessGetWithdrawAddressMessageTH    string = "แสดงที่อยู่สำหรับถอนโทเคนสำเร็จ."
+	ErrGetWithdrawAddressMessageTH        string = "ไม่สามารถแสดงที่อยู่สำหรับถอนโทเคนได้."
+	SuccessAddWithdrawAddressMessageTH    string = "เพิ่มที่อยู่สำหรับถอนโทเคนสำเร็จ."
+	ErrAddWithdrawAddressMessageTH        string = "ไม่สามารถเพิ่มที่อยู่สำหรับถอนโทเคนได้."
+	SuccessRemoveWithdrawAddressMessageTH string = "ลบที่อยู่สำหรับถอนโทเคนสำเร็จ."
+	ErrRemoveWithdrawAddressMessageTH     string = "ไม่สามารถลบที่อยู่สำหรับถอนโทเคนได้."
 	//// Admin
 	SuccessGetAccountAdminMessageTH            string = "แสดงข้อมูลบัญชีผู้ใช้งานสำเร็จ."
 	ErrGetAccountAdminMessageTH                string = "ไม่สามารถแสดงข้อมูลบัญชีผู้ใช้งานได้."
@@ -185,6 +264,18 @@ const (
 	ErrConfirmWithdrawAdminMessageTH           string = "ไม่สามารถยืนยันการถอนโทเคนได้."
 	SuccessRejectWithdrawAdminMessageTH        string = "ปฏิเสธการถอนโทเคนสำเร็จ."
 	ErrRejectWithdrawAdminMessageTH            string = "ไม่สามารถปฏิเสธการถอนโทเคนได้."
+	SuccessSpeedUpWithdrawAdminMessageTH       string = "เร่งการถอนโทเคนสำเร็จ."
+	ErrSpeedUpWithdrawAdminMessageTH           string = "ไม่สามารถเร่งการถอนโทเคนได้."
+	SuccessBatchWithdrawAdminMessageTH         string = "ถอนเหรียญแบบรวมรายการสำเร็จ."
+	ErrBatchWithdrawAdminMessageTH             string = "ไม่สามารถถอนเหรียญแบบรวมรายการได้."
+	SuccessGetUnsignedWithdrawAdminMessageTH   string = "ดึงธุรกรรมถอนที่ยังไม่ได้ลงนามสำเร็จ."
+	ErrGetUnsignedWithdrawAdminMessageTH       string = "ไม่สามารถดึงธุรกรรมถอนที่ยังไม่ได้ลงนามได้."
+	SuccessImportSignedWithdrawAdminMessageTH  string = "ส่งธุรกรรมถอนที่ลงนามแล้วสำเร็จ."
+	ErrImportSignedWithdrawAdminMessageTH      string = "ไม่สามารถส่งธุรกรรมถอนที่ลงนามแล้วได้."
+	SuccessGetUnsignedSweepAdminMessageTH      string = "ดึงธุรกรรมรวบรวมยอดที่ยังไม่ได้ลงนามสำเร็จ."
+	ErrGetUnsignedSweepAdminMessageTH          string = "ไม่สามารถดึงธุรกรรมรวบรวมยอดที่ยังไม่ได้ลงนามได้."
+	SuccessImportSignedSweepAdminMessageTH     string = "ส่งธุรกรรมรวบรวมยอดที่ลงนามแล้วสำเร็จ."
+	ErrImportSignedSweepAdminMessageTH         string = "ไม่สามารถส่งธุรกรรมรวบรวมยอดที่ลงนามแล้วได้."
 	SuccessGetContractAdminMessageTH           string = "แสดงสัญญากู้ยืมสำเร็จ."
 	ErrGetContractAdminMessageTH               string = "ไม่สามารถแสดงสัญญากู้ยืมได้."
 	SuccessConfirmContractAdminMessageTH       string = "ยืนยันการกู้ยืมสำเร็จ."
@@ -201,6 +292,8 @@ const (
 	ErrRejectRepaymentAdminMessageTH           string = "ไม่สามารถปฏิเสธการจ่ายเงินคืนได้."
 	SuccessLiquidateFundAdminMessageTH         string = "ขายทรัพย์สินทั้งหมดของทุนสำเร็จ."
 	ErrLiquidateFundAdminMessageTH             string = "ไม่สามารถขายทรัพย์สินทั้งหมดของทุนได้."
+	SuccessStressTestAdminMessageTH            string = "ทดสอบภาวะวิกฤตสำเร็จ."
+	ErrStressTestAdminMessageTH                string = "ไม่สามารถทดสอบภาวะวิกฤตได้."
 	// Mail
 	SuccessOTPRequestMessageTH      string = "ขอรหัส OTP สำเร็จ."
 	ErrOTPRequestMessageTH          string = "ไม่สามารถขอรหัส OTP ได้."
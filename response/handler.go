@@ -35,6 +35,7 @@ var (
 		UpdateDocumentInfoAdminSuccess:    Response{Code: SuccessCode, Title: SuccessUpdateDocumentInfoAdminMessageEN},
 		UpdateDocumentInfoAdminRequest:    ErrResponse{Code: ErrInvalidRequestCode, Title: ErrUpdateDocumentInfoAdminMessageEN, Description: ErrRequestDataDescEN},
 		GetTokenPriceSuccess:              Response{Code: SuccessCode, Title: SuccessGetToknPriceMessageEN},
+		GetTokenPriceRequest:              ErrResponse{Code: ErrInvalidRequestCode, Title: ErrGetTokenPriceMessageEN, Description: ErrRequestDataDescEN},
 		PreCalculationLoanSuccess:         Response{Code: SuccessCode, Title: SuccessPreCalculationLoanMessageEN},
 		PreCalculationLoanRequest:         ErrResponse{Code: ErrInvalidRequestCode, Title: ErrPreCalculationLoanMessageEN, Description: ErrRequestDataDescEN},
 		GetWalletTransactionSuccess:       Response{Code: SuccessCode, Title: SuccessGetWalletTransactionMessageEN},
@@ -43,7 +44,30 @@ var (
 		SubmitDepositBlockErr:             ErrResponse{Code: ErrBlockchainCode, Title: ErrSubmitDepositMessageEN, Description: ErrContactAdminDescEN},
 		SubmitWithdrawSuccess:             Response{Code: SuccessCode, Title: SuccessSubmitWithdrawMessageEN},
 		SubmitWithdrawRequest:             ErrResponse{Code: ErrInvalidRequestCode, Title: ErrSubmitDepositMessageEN, Description: ErrRequestDataDescEN},
+		GetWithdrawFeeSuccess:             Response{Code: SuccessCode, Title: SuccessGetWithdrawFeeMessageEN},
+		GetWithdrawFeeRequest:             ErrResponse{Code: ErrInvalidRequestCode, Title: ErrGetWithdrawFeeMessageEN, Description: ErrRequestDataDescEN},
+		GetWithdrawFeeBlockErr:            ErrResponse{Code: ErrBlockchainCode, Title: ErrGetWithdrawFeeMessageEN, Description: ErrContactAdminDescEN},
+		GetReservesSuccess:                Response{Code: SuccessCode, Title: SuccessGetReservesMessageEN},
+		GetReservesRequest:                ErrResponse{Code: ErrInvalidRequestCode, Title: ErrGetReservesMessageEN, Description: ErrRequestDataDescEN},
+		GetReserveProofSuccess:            Response{Code: SuccessCode, Title: SuccessGetReserveProofMessageEN},
+		GetReserveProofRequest:            ErrResponse{Code: ErrInvalidRequestCode, Title: ErrGetReserveProofMessageEN, Description: ErrRequestDataDescEN},
+		GetTreasuryAdminSuccess:           Response{Code: SuccessCode, Title: SuccessGetTreasuryAdminMessageEN},
+		GetTreasuryAdminRequest:           ErrResponse{Code: ErrInvalidRequestCode, Title: ErrGetTreasuryAdminMessageEN, Description: ErrRequestDataDescEN},
+		GetTreasuryAdminBlockErr:          ErrResponse{Code: ErrBlockchainCode, Title: ErrGetTreasuryAdminMessageEN, Description: ErrContactAdminDescEN},
+		GetPriceHistorySuccess:            Response{Code: SuccessCode, Title: SuccessGetPriceHistoryMessageEN},
+		GetPriceHistoryRequest:            ErrResponse{Code: ErrInvalidRequestCode, Title: ErrGetPriceHistoryMessageEN, Description: ErrRequestDataDescEN},
+		GetBreakerAdminSuccess:            Response{Code: SuccessCode, Title: SuccessGetBreakerAdminMessageEN},
+		GetBreakerAdminRequest:            ErrResponse{Code: ErrInvalidRequestCode, Title: ErrGetBreakerAdminMessageEN, Description: ErrRequestDataDescEN},
+		OverrideBreakerAdminSuccess:       Response{Code: SuccessCode, Title: SuccessOverrideBreakerAdminMessageEN},
+		OverrideBreakerAdminRequest:       ErrResponse{Code: ErrInvalidRequestCode, Title: ErrOverrideBreakerAdminMessageEN, Description: ErrRequestDataDescEN},
+		GetHaircutAdminSuccess:            Response{Code: SuccessCode, Title: SuccessGetHaircutAdminMessageEN},
+		GetHaircutAdminRequest:            ErrResponse{Code: ErrInvalidRequestCode, Title: ErrGetHaircutAdminMessageEN, Description: ErrRequestDataDescEN},
+		ApproveHaircutAdminSuccess:        Response{Code: SuccessCode, Title: SuccessApproveHaircutAdminMessageEN},
+		ApproveHaircutAdminRequest:        ErrResponse{Code: ErrInvalidRequestCode, Title: ErrApproveHaircutAdminMessageEN, Description: ErrRequestDataDescEN},
+		RejectHaircutAdminSuccess:         Response{Code: SuccessCode, Title: SuccessRejectHaircutAdminMessageEN},
+		RejectHaircutAdminRequest:         ErrResponse{Code: ErrInvalidRequestCode, Title: ErrRejectHaircutAdminMessageEN, Description: ErrRequestDataDescEN},
 		GetCreditAvailableSuccess:         Response{Code: SuccessCode, Title: SuccessGetCreditAvailableMessageEN},
+		GetCreditAvailableRequest:         ErrResponse{Code: ErrInvalidRequestCode, Title: ErrGetCreditAvailableMessageEN, Description: ErrRequestDataDescEN},
 		GetLoanSuccess:                    Response{Code: SuccessCode, Title: SuccessGetLoanMessageEN},
 		BorrowLoanSuccess:                 Response{Code: SuccessCode, Title: SuccessBorrowLoanMessageEN},
 		BorrowLoanRequest:                 ErrResponse{Code: ErrInvalidRequestCode, Title: ErrBorrowLoanMessageEN, Description: ErrRequestDataDescEN},
@@ -52,6 +76,14 @@ var (
 		GetRepaymentRequest:               ErrResponse{Code: ErrInvalidRequestCode, Title: ErrGetRepaymentMessageEN, Description: ErrRequestDataDescEN},
 		SubmitRepaymentSuccess:            Response{Code: SuccessCode, Title: SuccessSubmitRepaymentMessageEN},
 		SubmitRepaymentRequest:            ErrResponse{Code: ErrInvalidRequestCode, Title: ErrSubmitRepaymentMessageEN, Description: ErrRequestDataDescEN},
+		GetDepositAddressSuccess:          Response{Code: SuccessCode, Title: SuccessGetDepositAddressMessageEN},
+		GetDepositAddressRequest:          ErrResponse{Code: ErrInvalidRequestCode, Title: ErrGetDepositAddressMessageEN, Description: ErrRequestDataDescEN},
+		GetWithdrawAddressSuccess:         Response{Code: SuccessCode, Title: SuccessGetWithdrawAddressMessageEN},
+		GetWithdrawAddressRequest:         ErrResponse{Code: ErrInvalidRequestCode, Title: ErrGetWithdrawAddressMessageEN, Description: ErrRequestDataDescEN},
+		AddWithdrawAddressSuccess:         Response{Code: SuccessCode, Title: SuccessAddWithdrawAddressMessageEN},
+		AddWithdrawAddressRequest:         ErrResponse{Code: ErrInvalidRequestCode, Title: ErrAddWithdrawAddressMessageEN, Description: ErrRequestDataDescEN},
+		RemoveWithdrawAddressSuccess:      Response{Code: SuccessCode, Title: SuccessRemoveWithdrawAddressMessageEN},
+		RemoveWithdrawAddressRequest:      ErrResponse{Code: ErrInvalidRequestCode, Title: ErrRemoveWithdrawAddressMessageEN, Description: ErrRequestDataDescEN},
 		GetAccountAdminSuccess:            Response{Code: SuccessCode, Title: SuccessGetAccountAdminMessageEN},
 		GetAccountAdminRequest:            ErrResponse{Code: ErrInvalidRequestCode, Title: ErrGetAccountAdminMessageEN, Description: ErrRequestDataDescEN},
 		ConfirmAccountAdminSuccess:        Response{Code: SuccessCode, Title: SuccessConfirmAccountAdminMessageEN},
@@ -68,8 +100,27 @@ var (
 		RejectDepostiAdminRequest:         ErrResponse{Code: ErrInvalidRequestCode, Title: ErrRejectDepositAdminMessageEN, Description: ErrRequestDataDescEN},
 		ConfirmWithdrawAdminSuccess:       Response{Code: SuccessCode, Title: SuccessConfirmWithdrawAdminMessageEN},
 		ConfirmWithdrawAdminRequest:       ErrResponse{Code: ErrInvalidRequestCode, Title: ErrConfirmWithdrawAdminMessageEN, Description: ErrRequestDataDescEN},
+		ConfirmWithdrawAdminBlockErr:      ErrResponse{Code: ErrBlockchainCode, Title: ErrConfirmWithdrawAdminMessageEN, Description: ErrContactAdminDescEN},
 		RejectWithdrawAdminSuccess:        Response{Code: SuccessCode, Title: SuccessRejectWithdrawAdminMessageEN},
 		RejectWithdrawAdminRequest:        ErrResponse{Code: ErrInvalidRequestCode, Title: ErrRejectWithdrawAdminMessageEN, Description: ErrRequestDataDescEN},
+		SpeedUpWithdrawAdminSuccess:       Response{Code: SuccessCode, Title: SuccessSpeedUpWithdrawAdminMessageEN},
+		SpeedUpWithdrawAdminRequest:       ErrResponse{Code: ErrInvalidRequestCode, Title: ErrSpeedUpWithdrawAdminMessageEN, Description: ErrRequestDataDescEN},
+		SpeedUpWithdrawAdminBlockErr:      ErrResponse{Code: ErrBlockchainCode, Title: ErrSpeedUpWithdrawAdminMessageEN, Description: ErrContactAdminDescEN},
+		BatchWithdrawAdminSuccess:         Response{Code: SuccessCode, Title: SuccessBatchWithdrawAdminMessageEN},
+		BatchWithdrawAdminRequest:         ErrResponse{Code: ErrInvalidRequestCode, Title: ErrBatchWithdrawAdminMessageEN, Description: ErrRequestDataDescEN},
+		BatchWithdrawAdminBlockErr:        ErrResponse{Code: ErrBlockchainCode, Title: ErrBatchWithdrawAdminMessageEN, Description: ErrContactAdminDescEN},
+		GetUnsignedWithdrawAdminSuccess:   Response{Code: SuccessCode, Title: SuccessGetUnsignedWithdrawAdminMessageEN},
+		GetUnsignedWithdrawAdminRequest:   ErrResponse{Code: ErrInvalidRequestCode, Title: ErrGetUnsignedWithdrawAdminMessageEN, Description: ErrRequestDataDescEN},
+		GetUnsignedWithdrawAdminBlockErr:  ErrResponse{Code: ErrBlockchainCode, Title: ErrGetUnsignedWithdrawAdminMessageEN, Description: ErrContactAdminDescEN},
+		ImportSignedWithdrawAdminSuccess:  Response{Code: SuccessCode, Title: SuccessImportSignedWithdrawAdminMessageEN},
+		ImportSignedWithdrawAdminRequest:  ErrResponse{Code: ErrInvalidRequestCode, Title: ErrImportSignedWithdrawAdminMessageEN, Description: ErrRequestDataDescEN},
+		ImportSignedWithdrawAdminBlockErr: ErrResponse{Code: ErrBlockchainCode, Title: ErrImportSignedWithdrawAdminMessageEN, Description: ErrContactAdminDescEN},
+		GetUnsignedSweepAdminSuccess:      Response{Code: SuccessCode, Title: SuccessGetUnsignedSweepAdminMessageEN},
+		GetUnsignedSweepAdminRequest:      ErrResponse{Code: ErrInvalidRequestCode, Title: ErrGetUnsignedSweepAdminMessageEN, Description: ErrRequestDataDescEN},
+		GetUnsignedSweepAdminBlockErr:     ErrResponse{Code: ErrBlockchainCode, Title: ErrGetUnsignedSweepAdminMessageEN, Description: ErrContactAdminDescEN},
+		ImportSignedSweepAdminSuccess:     Response{Code: SuccessCode, Title: SuccessImportSignedSweepAdminMessageEN},
+		ImportSignedSweepAdminRequest:     ErrResponse{Code: ErrInvalidRequestCode, Title: ErrImportSignedSweepAdminMessageEN, Description: ErrRequestDataDescEN},
+		ImportSignedSweepAdminBlockErr:    ErrResponse{Code: ErrBlockchainCode, Title: ErrImportSignedSweepAdminMessageEN, Description: ErrContactAdminDescEN},
 		GetContractAdminSuccess:           Response{Code: SuccessCode, Title: SuccessGetContractAdminMessageEN},
 		GetContractAdminRequest:           ErrResponse{Code: ErrInvalidRequestCode, Title: ErrGetContractAdminMessageEN, Description: ErrRequestDataDescEN},
 		ConfirmContractAdminSuccess:       Response{Code: SuccessCode, Title: SuccessConfirmContractAdminMessageEN},
@@ -87,6 +138,8 @@ var (
 		LiquidateFundAdminSuccess:         Response{Code: SuccessCode, Title: SuccessLiquidateFundAdminMessageEN},
 		LiquidateFundAdminRequest:         ErrResponse{Code: ErrInvalidRequestCode, Title: ErrLiquidateFundAdminMessageEN, Description: ErrRequestDataDescEN},
 		LiquidateFundAdminThirdParty:      ErrResponse{Code: ErrThirdPartyCode, Title: ErrLiquidateFundAdminMessageEN, Description: ErrThirdPartyDescEN},
+		StressTestAdminSuccess:            Response{Code: SuccessCode, Title: SuccessStressTestAdminMessageEN},
+		StressTestAdminRequest:            ErrResponse{Code: ErrInvalidRequestCode, Title: ErrStressTestAdminMessageEN, Description: ErrRequestDataDescEN},
 		GetOTPSuccess:                     Response{Code: SuccessCode, Title: SuccessOTPRequestMessageEN},
 		GetOTPRequest:                     ErrResponse{Code: ErrInvalidRequestCode, Title: ErrOTPRequestMessageEN, Description: ErrRequestDataDescEN},
 		GetOTPThirdParty:                  ErrResponse{Code: ErrThirdPartyCode, Title: ErrOTPRequestMessageEN, Description: ErrThirdPartyDescEN},
@@ -97,6 +150,9 @@ var (
 		InternalOperation:                 ErrResponse{Code: ErrOperationCode, Title: ErrInternalServerMessageEN, Description: ErrContactAdminDescEN},
 		InternalDatabase:                  ErrResponse{Code: ErrDatabaseCode, Title: ErrInternalServerMessageEN, Description: ErrContactAdminDescEN},
 		InternalRedis:                     ErrResponse{Code: ErrRedisCode, Title: ErrInternalServerMessageEN, Description: ErrContactAdminDescEN},
+		InternalPrice:                     ErrResponse{Code: ErrPriceCode, Title: ErrInternalServerMessageEN, Description: ErrContactAdminDescEN},
+		StalePrice:                        ErrResponse{Code: ErrPriceCode, Title: ErrStalePriceMessageEN, Description: ErrThirdPartyDescEN},
+		PriceHalted:                       ErrResponse{Code: ErrPriceCode, Title: ErrPriceHaltedMessageEN, Description: ErrCooldownDescEN},
 	}
 	TH = Global{
 		AuthenBasicWeb:                    ErrResponse{Code: ErrBasicAuthenticationCode, Title: ErrBasicAuthenticationMessageTH, Description: ErrAuthenticationDescTH},
@@ -127,6 +183,7 @@ var (
 		UpdateDocumentInfoAdminSuccess:    Response{Code: SuccessCode, Title: SuccessUpdateDocumentInfoAdminMessageTH},
 		UpdateDocumentInfoAdminRequest:    ErrResponse{Code: ErrInvalidRequestCode, Title: ErrUpdateDocumentInfoAdminMessageTH, Description: ErrRequestDataDescTH},
 		GetTokenPriceSuccess:              Response{Code: SuccessCode, Title: SuccessGetToknPriceMessageTH},
+		GetTokenPriceRequest:              ErrResponse{Code: ErrInvalidRequestCode, Title: ErrGetTokenPriceMessageTH, Description: ErrRequestDataDescTH},
 		PreCalculationLoanSuccess:         Response{Code: SuccessCode, Title: SuccessPreCalculationLoanMessageTH},
 		PreCalculationLoanRequest:         ErrResponse{Code: ErrInvalidRequestCode, Title: ErrPreCalculationLoanMessageTH, Description: ErrRequestDataDescTH},
 		GetWalletTransactionSuccess:       Response{Code: SuccessCode, Title: SuccessGetWalletTransactionMessageTH},
@@ -135,7 +192,30 @@ var (
 		SubmitDepositBlockErr:             ErrResponse{Code: ErrBlockchainCode, Title: ErrSubmitDepositMessageTH, Description: ErrContactAdminDescTH},
 		SubmitWithdrawSuccess:             Response{Code: SuccessCode, Title: SuccessSubmitWithdrawMessageTH},
 		SubmitWithdrawRequest:             ErrResponse{Code: ErrInvalidRequestCode, Title: ErrSubmitDepositMessageTH, Description: ErrRequestDataDescTH},
+		GetWithdrawFeeSuccess:             Response{Code: SuccessCode, Title: SuccessGetWithdrawFeeMessageTH},
+		GetWithdrawFeeRequest:             ErrResponse{Code: ErrInvalidRequestCode, Title: ErrGetWithdrawFeeMessageTH, Description: ErrRequestDataDescTH},
+		GetWithdrawFeeBlockErr:            ErrResponse{Code: ErrBlockchainCode, Title: ErrGetWithdrawFeeMessageTH, Description: ErrContactAdminDescTH},
+		GetReservesSuccess:                Response{Code: SuccessCode, Title: SuccessGetReservesMessageTH},
+		GetReservesRequest:                ErrResponse{Code: ErrInvalidRequestCode, Title: ErrGetReservesMessageTH, Description: ErrRequestDataDescTH},
+		GetReserveProofSuccess:            Response{Code: SuccessCode, Title: SuccessGetReserveProofMessageTH},
+		GetReserveProofRequest:            ErrResponse{Code: ErrInvalidRequestCode, Title: ErrGetReserveProofMessageTH, Description: ErrRequestDataDescTH},
+		GetTreasuryAdminSuccess:           Response{Code: SuccessCode, Title: SuccessGetTreasuryAdminMessageTH},
+		GetTreasuryAdminRequest:           ErrResponse{Code: ErrInvalidRequestCode, Title: ErrGetTreasuryAdminMessageTH, Description: ErrRequestDataDescTH},
+		GetTreasuryAdminBlockErr:          ErrResponse{Code: ErrBlockchainCode, Title: ErrGetTreasuryAdminMessageTH, Description: ErrContactAdminDescTH},
+		GetPriceHistorySuccess:            Response{Code: SuccessCode, Title: SuccessGetPriceHistoryMessageTH},
+		GetPriceHistoryRequest:            ErrResponse{Code: ErrInvalidRequestCode, Title: ErrGetPriceHistoryMessageTH, Description: ErrRequestDataDescTH},
+		GetBreakerAdminSuccess:            Response{Code: SuccessCode, Title: SuccessGetBreakerAdminMessageTH},
+		GetBreakerAdminRequest:            ErrResponse{Code: ErrInvalidRequestCode, Title: ErrGetBreakerAdminMessageTH, Description: ErrRequestDataDescTH},
+		OverrideBreakerAdminSuccess:       Response{Code: SuccessCode, Title: SuccessOverrideBreakerAdminMessageTH},
+		OverrideBreakerAdminRequest:       ErrResponse{Code: ErrInvalidRequestCode, Title: ErrOverrideBreakerAdminMessageTH, Description: ErrRequestDataDescTH},
+		GetHaircutAdminSuccess:            Response{Code: SuccessCode, Title: SuccessGetHaircutAdminMessageTH},
+		GetHaircutAdminRequest:            ErrResponse{Code: ErrInvalidRequestCode, Title: ErrGetHaircutAdminMessageTH, Description: ErrRequestDataDescTH},
+		ApproveHaircutAdminSuccess:        Response{Code: SuccessCode, Title: SuccessApproveHaircutAdminMessageTH},
+		ApproveHaircutAdminRequest:        ErrResponse{Code: ErrInvalidRequestCode, Title: ErrApproveHaircutAdminMessageTH, Description: ErrRequestDataDescTH},
+		RejectHaircutAdminSuccess:         Response{Code: SuccessCode, Title: SuccessRejectHaircutAdminMessageTH},
+		RejectHaircutAdminRequest:         ErrResponse{Code: ErrInvalidRequestCode, Title: ErrRejectHaircutAdminMessageTH, Description: ErrRequestDataDescTH},
 		GetCreditAvailableSuccess:         Response{Code: SuccessCode, Title: SuccessGetCreditAvailableMessageTH},
+		GetCreditAvailableRequest:         ErrResponse{Code: ErrInvalidRequestCode, Title: ErrGetCreditAvailableMessageTH, Description: ErrRequestDataDescTH},
 		GetLoanSuccess:                    Response{Code: SuccessCode, Title: SuccessGetLoanMessageTH},
 		BorrowLoanSuccess:                 Response{Code: SuccessCode, Title: SuccessBorrowLoanMessageTH},
 		BorrowLoanRequest:                 ErrResponse{Code: ErrInvalidRequestCode, Title: ErrBorrowLoanMessageTH, Description: ErrRequestDataDescTH},
@@ -144,6 +224,14 @@ var (
 		GetRepaymentRequest:               ErrResponse{Code: ErrInvalidRequestCode, Title: ErrGetRepaymentMessageTH, Description: ErrRequestDataDescTH},
 		SubmitRepaymentSuccess:            Response{Code: SuccessCode, Title: SuccessSubmitRepaymentMessageTH},
 		SubmitRepaymentRequest:            ErrResponse{Code: ErrInvalidRequestCode, Title: ErrSubmitRepaymentMessageTH, Description: ErrRequestDataDescTH},
+		GetDepositAddressSuccess:          Response{Code: SuccessCode, Title: SuccessGetDepositAddressMessageTH},
+		GetDepositAddressRequest:          ErrResponse{Code: ErrInvalidRequestCode, Title: ErrGetDepositAddressMessageTH, Description: ErrRequestDataDescTH},
+		GetWithdrawAddressSuccess:         Response{Code: SuccessCode, Title: SuccessGetWithdrawAddressMessageTH},
+		GetWithdrawAddressRequest:         ErrResponse{Code: ErrInvalidRequestCode, Title: ErrGetWithdrawAddressMessageTH, Description: ErrRequestDataDescTH},
+		AddWithdrawAddressSuccess:         Response{Code: SuccessCode, Title: SuccessAddWithdrawAddressMessageTH},
+		AddWithdrawAddressRequest:         ErrResponse{Code: ErrInvalidRequestCode, Title: ErrAddWithdrawAddressMessageTH, Description: ErrRequestDataDescTH},
+		RemoveWithdrawAddressSuccess:      Response{Code: SuccessCode, Title: SuccessRemoveWithdrawAddressMessageTH},
+		RemoveWithdrawAddressRequest:      ErrResponse{Code: ErrInvalidRequestCode, Title: ErrRemoveWithdrawAddressMessageTH, Description: ErrRequestDataDescTH},
 		GetAccountAdminSuccess:            Response{Code: SuccessCode, Title: SuccessGetAccountAdminMessageTH},
 		GetAccountAdminRequest:            ErrResponse{Code: ErrInvalidRequestCode, Title: ErrGetAccountAdminMessageTH, Description: ErrRequestDataDescTH},
 		ConfirmAccountAdminSuccess:        Response{Code: SuccessCode, Title: SuccessConfirmAccountAdminMessageTH},
@@ -160,8 +248,27 @@ var (
 		RejectDepostiAdminRequest:         ErrResponse{Code: ErrInvalidRequestCode, Title: ErrRejectDepositAdminMessageTH, Description: ErrRequestDataDescTH},
 		ConfirmWithdrawAdminSuccess:       Response{Code: SuccessCode, Title: SuccessConfirmWithdrawAdminMessageTH},
 		ConfirmWithdrawAdminRequest:       ErrResponse{Code: ErrInvalidRequestCode, Title: ErrConfirmWithdrawAdminMessageTH, Description: ErrRequestDataDescTH},
+		ConfirmWithdrawAdminBlockErr:      ErrResponse{Code: ErrBlockchainCode, Title: ErrConfirmWithdrawAdminMessageTH, Description: ErrContactAdminDescTH},
 		RejectWithdrawAdminSuccess:        Response{Code: SuccessCode, Title: SuccessRejectWithdrawAdminMessageTH},
 		RejectWithdrawAdminRequest:        ErrResponse{Code: ErrInvalidRequestCode, Title: ErrRejectWithdrawAdminMessageTH, Description: ErrRequestDataDescTH},
+		SpeedUpWithdrawAdminSuccess:       Response{Code: SuccessCode, Title: SuccessSpeedUpWithdrawAdminMessageTH},
+		SpeedUpWithdrawAdminRequest:       ErrResponse{Code: ErrInvalidRequestCode, Title: ErrSpeedUpWithdrawAdminMessageTH, Description: ErrRequestDataDescTH},
+		SpeedUpWithdrawAdminBlockErr:      ErrResponse{Code: ErrBlockchainCode, Title: ErrSpeedUpWithdrawAdminMessageTH, Description: ErrContactAdminDescTH},
+		BatchWithdrawAdminSuccess:         Response{Code: SuccessCode, Title: SuccessBatchWithdrawAdminMessageTH},
+		BatchWithdrawAdminRequest:         ErrResponse{Code: ErrInvalidRequestCode, Title: ErrBatchWithdrawAdminMessageTH, Description: ErrRequestDataDescTH},
+		BatchWithdrawAdminBlockErr:        ErrResponse{Code: ErrBlockchainCode, Title: ErrBatchWithdrawAdminMessageTH, Description: ErrContactAdminDescTH},
+		GetUnsignedWithdrawAdminSuccess:   Response{Code: SuccessCode, Title: SuccessGetUnsignedWithdrawAdminMessageTH},
+		GetUnsignedWithdrawAdminRequest:   ErrResponse{Code: ErrInvalidRequestCode, Title: ErrGetUnsignedWithdrawAdminMessageTH, Description: ErrRequestDataDescTH},
+		GetUnsignedWithdrawAdminBlockErr:  ErrResponse{Code: ErrBlockchainCode, Title: ErrGetUnsignedWithdrawAdminMessageTH, Description: ErrContactAdminDescTH},
+		ImportSignedWithdrawAdminSuccess:  Response{Code: SuccessCode, Title: SuccessImportSignedWithdrawAdminMessageTH},
+		ImportSignedWithdrawAdminRequest:  ErrResponse{Code: ErrInvalidRequestCode, Title: ErrImportSignedWithdrawAdminMessageTH, Description: ErrRequestDataDescTH},
+		ImportSignedWithdrawAdminBlockErr: ErrResponse{Code: ErrBlockchainCode, Title: ErrImportSignedWithdrawAdminMessageTH, Description: ErrContactAdminDescTH},
+		GetUnsignedSweepAdminSuccess:      Response{Code: SuccessCode, Title: SuccessGetUnsignedSweepAdminMessageTH},
+		GetUnsignedSweepAdminRequest:      ErrResponse{Code: ErrInvalidRequestCode, Title: ErrGetUnsignedSweepAdminMessageTH, Description: ErrRequestDataDescTH},
+		GetUnsignedSweepAdminBlockErr:     ErrResponse{Code: ErrBlockchainCode, Title: ErrGetUnsignedSweepAdminMessageTH, Description: ErrContactAdminDescTH},
+		ImportSignedSweepAdminSuccess:     Response{Code: SuccessCode, Title: SuccessImportSignedSweepAdminMessageTH},
+		ImportSignedSweepAdminRequest:     ErrResponse{Code: ErrInvalidRequestCode, Title: ErrImportSignedSweepAdminMessageTH, Description: ErrRequestDataDescTH},
+		ImportSignedSweepAdminBlockErr:    ErrResponse{Code: ErrBlockchainCode, Title: ErrImportSignedSweepAdminMessageTH, Description: ErrContactAdminDescTH},
 		GetContractAdminSuccess:           Response{Code: SuccessCode, Title: SuccessGetContractAdminMessageTH},
 		GetContractAdminRequest:           ErrResponse{Code: ErrInvalidRequestCode, Title: ErrGetContractAdminMessageTH, Description: ErrRequestDataDescTH},
 		ConfirmContractAdminSuccess:       Response{Code: SuccessCode, Title: SuccessConfirmContractAdminMessageTH},
@@ -179,6 +286,8 @@ var (
 		LiquidateFundAdminSuccess:         Response{Code: SuccessCode, Title: SuccessLiquidateFundAdminMessageTH},
 		LiquidateFundAdminRequest:         ErrResponse{Code: ErrInvalidRequestCode, Title: ErrLiquidateFundAdminMessageTH, Description: ErrRequestDataDescTH},
 		LiquidateFundAdminThirdParty:      ErrResponse{Code: ErrThirdPartyCode, Title: ErrLiquidateFundAdminMessageTH, Description: ErrThirdPartyDescTH},
+		StressTestAdminSuccess:            Response{Code: SuccessCode, Title: SuccessStressTestAdminMessageTH},
+		StressTestAdminRequest:            ErrResponse{Code: ErrInvalidRequestCode, Title: ErrStressTestAdminMessageTH, Description: ErrRequestDataDescTH},
 		GetOTPSuccess:                     Response{Code: SuccessCode, Title: SuccessOTPRequestMessageTH},
 		GetOTPRequest:                     ErrResponse{Code: ErrInvalidRequestCode, Title: ErrOTPRequestMessageTH, Description: ErrRequestDataDescTH},
 		GetOTPThirdParty:                  ErrResponse{Code: ErrThirdPartyCode, Title: ErrOTPRequestMessageTH, Description: ErrThirdPartyDescTH},
@@ -189,6 +298,9 @@ var (
 		InternalOperation:                 ErrResponse{Code: ErrOperationCode, Title: ErrInternalServerMessageTH, Description: ErrContactAdminDescTH},
 		InternalDatabase:                  ErrResponse{Code: ErrDatabaseCode, Title: ErrInternalServerMessageTH, Description: ErrContactAdminDescTH},
 		InternalRedis:                     ErrResponse{Code: ErrRedisCode, Title: ErrInternalServerMessageTH, Description: ErrContactAdminDescTH},
+		InternalPrice:                     ErrResponse{Code: ErrPriceCode, Title: ErrInternalServerMessageTH, Description: ErrContactAdminDescTH},
+		StalePrice:                        ErrResponse{Code: ErrPriceCode, Title: ErrStalePriceMessageTH, Description: ErrThirdPartyDescTH},
+		PriceHalted:                       ErrResponse{Code: ErrPriceCode, Title: ErrPriceHaltedMessageTH, Description: ErrCooldownDescTH},
 	}
 
 	Language = map[interface{}]Global{
@@ -230,24 +342,56 @@ type Global struct {
 	UpdateDocumentInfoAdminRequest ErrResponse
 	// Lending
 	//// User
-	GetTokenPriceSuccess        Response
-	PreCalculationLoanSuccess   Response
-	PreCalculationLoanRequest   ErrResponse
-	GetWalletTransactionSuccess Response
-	SubmitDepositSuccess        Response
-	SubmitDepositRequest        ErrResponse
-	SubmitDepositBlockErr       ErrResponse
-	SubmitWithdrawSuccess       Response
-	SubmitWithdrawRequest       ErrResponse
-	GetCreditAvailableSuccess   Response
-	GetLoanSuccess              Response
-	BorrowLoanSuccess           Response
-	BorrowLoanRequest           ErrResponse
-	GetInterestTermSuccess      Response
-	GetRepaymentSuccess         Response
-	GetRepaymentRequest         ErrResponse
-	SubmitRepaymentSuccess      Response
-	SubmitRepaymentRequest      ErrResponse
+	GetTokenPriceSuccess         Response
+	GetTokenPriceRequest         ErrResponse
+	PreCalculationLoanSuccess    Response
+	PreCalculationLoanRequest    ErrResponse
+	GetWalletTransactionSuccess  Response
+	SubmitDepositSuccess         Response
+	SubmitDepositRequest         ErrResponse
+	SubmitDepositBlockErr        ErrResponse
+	SubmitWithdrawSuccess        Response
+	SubmitWithdrawRequest        ErrResponse
+	GetWithdrawFeeSuccess        Response
+	GetWithdrawFeeRequest        ErrResponse
+	GetWithdrawFeeBlockErr       ErrResponse
+	GetReservesSuccess           Response
+	GetReservesRequest           ErrResponse
+	GetReserveProofSuccess       Response
+	GetReserveProofRequest       ErrResponse
+	GetTreasuryAdminSuccess      Response
+	GetTreasuryAdminRequest      ErrResponse
+	GetTreasuryAdminBlockErr     ErrResponse
+	GetPriceHistorySuccess       Response
+	GetPriceHistoryRequest       ErrResponse
+	GetBreakerAdminSuccess       Response
+	GetBreakerAdminRequest       ErrResponse
+	OverrideBreakerAdminSuccess  Response
+	OverrideBreakerAdminRequest  ErrResponse
+	GetHaircutAdminSuccess       Response
+	GetHaircutAdminRequest       ErrResponse
+	ApproveHaircutAdminSuccess   Response
+	ApproveHaircutAdminRequest   ErrResponse
+	RejectHaircutAdminSuccess    Response
+	RejectHaircutAdminRequest    ErrResponse
+	GetCreditAvailableSuccess    Response
+	GetCreditAvailableRequest    ErrResponse
+	GetLoanSuccess               Response
+	BorrowLoanSuccess            Response
+	BorrowLoanRequest            ErrResponse
+	GetInterestTermSuccess       Response
+	GetRepaymentSuccess          Response
+	GetRepaymentRequest          ErrResponse
+	SubmitRepaymentSuccess       Response
+	SubmitRepaymentRequest       ErrResponse
+	GetDepositAddressSuccess     Response
+	GetDepositAddressRequest     ErrResponse
+	GetWithdrawAddressSuccess    Response
+	GetWithdrawAddressRequest    ErrResponse
+	AddWithdrawAddressSuccess    Response
+	AddWithdrawAddressRequest    ErrResponse
+	RemoveWithdrawAddressSuccess Response
+	RemoveWithdrawAddressRequest ErrResponse
 	//// Admin
 	GetAccountAdminSuccess            Response
 	GetAccountAdminRequest            ErrResponse
@@ -265,8 +409,27 @@ type Global struct {
 	RejectDepostiAdminRequest         ErrResponse
 	ConfirmWithdrawAdminSuccess       Response
 	ConfirmWithdrawAdminRequest       ErrResponse
+	ConfirmWithdrawAdminBlockErr      ErrResponse
 	RejectWithdrawAdminSuccess        Response
 	RejectWithdrawAdminRequest        ErrResponse
+	SpeedUpWithdrawAdminSuccess       Response
+	SpeedUpWithdrawAdminRequest       ErrResponse
+	SpeedUpWithdrawAdminBlockErr      ErrResponse
+	BatchWithdrawAdminSuccess         Response
+	BatchWithdrawAdminRequest         ErrResponse
+	BatchWithdrawAdminBlockErr        ErrResponse
+	GetUnsignedWithdrawAdminSuccess   Response
+	GetUnsignedWithdrawAdminRequest   ErrResponse
+	GetUnsignedWithdrawAdminBlockErr  ErrResponse
+	ImportSignedWithdrawAdminSuccess  Response
+	ImportSignedWithdrawAdminRequest  ErrResponse
+	ImportSignedWithdrawAdminBlockErr ErrResponse
+	GetUnsignedSweepAdminSuccess      Response
+	GetUnsignedSweepAdminRequest      ErrResponse
+	GetUnsignedSweepAdminBlockErr     ErrResponse
+	ImportSignedSweepAdminSuccess     Response
+	ImportSignedSweepAdminRequest     ErrResponse
+	ImportSignedSweepAdminBlockErr    ErrResponse
 	GetContractAdminSuccess           Response
 	GetContractAdminRequest           ErrResponse
 	ConfirmContractAdminSuccess       Response
@@ -284,6 +447,8 @@ type Global struct {
 	LiquidateFundAdminSuccess         Response
 	LiquidateFundAdminRequest         ErrResponse
 	LiquidateFundAdminThirdParty      ErrResponse
+	StressTestAdminSuccess            Response
+	StressTestAdminRequest            ErrResponse
 	// Mail
 	GetOTPSuccess       Response
 	GetOTPRequest       ErrResponse
@@ -296,6 +461,9 @@ type Global struct {
 	InternalOperation ErrResponse
 	InternalDatabase  ErrResponse
 	InternalRedis     ErrResponse
+	InternalPrice     ErrResponse
+	StalePrice        ErrResponse
+	PriceHalted       ErrResponse
 }
 
 func ResponseContextLocale(ctx context.Context) *Global {
@@ -0,0 +1,28 @@
+package risk
+
+import (
+	"context"
+	"time"
+)
+
+type HaircutProposal struct {
+	ID                    *int       `db:"id" json:"id" example:"1"`
+	Asset                 *string    `db:"asset" json:"asset" example:"BTC"`
+	Volatility            *float64   `db:"volatility" json:"volatility" example:"0.0081"`
+	Samples               *int       `db:"samples" json:"samples" example:"719"`
+	CurrentHaircut        *float64   `db:"current_haircut" json:"currentHaircut" example:"0.5"`
+	ProposedHaircut       *float64   `db:"proposed_haircut" json:"proposedHaircut" example:"0.55"`
+	CurrentMarginCallLTV  *float64   `db:"current_margin_call_ltv" json:"currentMarginCallLtv" example:"0.5"`
+	ProposedMarginCallLTV *float64   `db:"proposed_margin_call_ltv" json:"proposedMarginCallLtv" example:"0.85"`
+	Status                *string    `db:"status" json:"status" example:"PENDING"`
+	CreatedDatetime       *time.Time `db:"created_datetime" json:"createdDatetime" example:"2021-01-02 12:13:14"`
+	UpdatedDatetime       *time.Time `db:"updated_datetime" json:"updatedDatetime" example:"2021-01-02 12:13:14"`
+}
+
+type RiskRepository interface {
+	QueryHaircutProposalByIDRepo(context.Context, int) (*HaircutProposal, error)
+	QueryHaircutProposalRepo(context.Context, map[string]interface{}) (*[]HaircutProposal, error)
+	InsertHaircutProposalRepo(context.Context, string, float64, int, float64, float64, float64, float64) (int64, error)
+	UpdateHaircutProposalRepo(context.Context, int, string, string) (int64, error)
+	SupersedeHaircutProposalRepo(context.Context, string, string) (int64, error)
+}
@@ -0,0 +1,156 @@
+package risk
+
+import (
+	"fmt"
+	"lending-engine/common"
+	"lending-engine/internal/handler"
+	"lending-engine/response"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/viper"
+)
+
+type riskHandler struct {
+	RiskRepository  RiskRepository
+	GetParametersFn GetParametersFn
+	SetParametersFn SetParametersFn
+}
+
+func NewRiskHandler(riskRepository RiskRepository, getParametersFn GetParametersFn, setParametersFn SetParametersFn) *riskHandler {
+	return &riskHandler{
+		RiskRepository:  riskRepository,
+		GetParametersFn: getParametersFn,
+		SetParametersFn: setParametersFn,
+	}
+}
+
+// GetHaircutAdmin
+// @Summary Get Haircut Admin
+// @Description get live haircut and margin call LTV of every asset with the volatility-driven proposals, newest first
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param asset query string false "Asset"
+// @Param status query string false "PENDING, CONFIRMED, REJECTED or SUPERSEDED"
+// @Success 200 {object} response.Response{data=risk.GetHaircutAdminResponse} "Success"
+// @Failure 400 {object} response.ErrResponse "Bad Request"
+// @Failure 500 {object} response.ErrResponse "Internal Server Error"
+// @Router /admin/haircut [get]
+func (s *riskHandler) GetHaircutAdmin(c *handler.Ctx) error {
+	var req GetHaircutAdminRequest
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).GetHaircutAdminRequest, err.Error()))
+	}
+
+	getHaircutAdminResponse := GetHaircutAdminResponse{
+		Parameters: make([]Parameters, 0),
+	}
+	for _, asset := range viper.GetStringSlice("oracle.assets") {
+		parameters, err := s.GetParametersFn(asset)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalRedis, err.Error()))
+		}
+		getHaircutAdminResponse.Parameters = append(getHaircutAdminResponse.Parameters, *parameters)
+	}
+
+	filter := make(map[string]interface{})
+	if req.Asset != nil {
+		filter["asset"] = strings.ToUpper(*req.Asset)
+	}
+	if req.Status != nil {
+		filter["status"] = strings.ToUpper(*req.Status)
+	}
+	proposals, err := s.RiskRepository.QueryHaircutProposalRepo(c.Context(), filter)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+	}
+	getHaircutAdminResponse.Proposals = *proposals
+	return c.Status(fiber.StatusOK).JSON(response.NewResponse(response.ResponseContextLocale(c.Context()).GetHaircutAdminSuccess, &getHaircutAdminResponse))
+}
+
+// ApproveHaircutAdmin
+// @Summary Approve Haircut Admin
+// @Description approve a pending haircut proposal, its haircut and margin call LTV become live on every instance
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param ApproveHaircutAdmin body risk.ApproveHaircutAdminRequest true "request body to approve haircut proposal"
+// @Success 200 {object} response.Response{data=risk.Parameters} "Success"
+// @Failure 400 {object} response.ErrResponse "Bad Request"
+// @Failure 500 {object} response.ErrResponse "Internal Server Error"
+// @Security AdminAuth
+// @Router /admin/haircut/approve [post]
+func (s *riskHandler) ApproveHaircutAdmin(c *handler.Ctx) error {
+	var req ApproveHaircutAdminRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).ApproveHaircutAdminRequest, err.Error()))
+	}
+	if err := req.validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).ApproveHaircutAdminRequest, err.Error()))
+	}
+
+	proposal, err := s.RiskRepository.QueryHaircutProposalByIDRepo(c.Context(), req.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+	}
+	if proposal == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).ApproveHaircutAdminRequest, "ID doesn't exist."))
+	}
+	if *proposal.Status != common.PendingStatus {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).ApproveHaircutAdminRequest, fmt.Sprintf("Proposal is %s.", *proposal.Status)))
+	}
+
+	rows, err := s.RiskRepository.UpdateHaircutProposalRepo(c.Context(), req.ID, common.ConfirmStatus, time.Now().Format(common.DateYYYYMMDDHHMMSSFormat))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+	}
+	if rows != 1 {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).ApproveHaircutAdminRequest, "Proposal isn't pending."))
+	}
+
+	parameters := Parameters{
+		Asset:         *proposal.Asset,
+		Haircut:       *proposal.ProposedHaircut,
+		MarginCallLTV: *proposal.ProposedMarginCallLTV,
+		ProposalID:    proposal.ID,
+	}
+	if err := s.SetParametersFn(parameters); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalRedis, err.Error()))
+	}
+	c.Log().Info(fmt.Sprintf("ProposalID: %d - Asset: %s | Haircut: %.4f - Margin Call LTV: %.4f", req.ID, parameters.Asset, parameters.Haircut, parameters.MarginCallLTV))
+	return c.Status(fiber.StatusOK).JSON(response.NewResponse(response.ResponseContextLocale(c.Context()).ApproveHaircutAdminSuccess, &parameters))
+}
+
+// RejectHaircutAdmin
+// @Summary Reject Haircut Admin
+// @Description reject a pending haircut proposal, the live parameters stay
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param RejectHaircutAdmin body risk.RejectHaircutAdminRequest true "request body to reject haircut proposal"
+// @Success 200 {object} response.Response "Success"
+// @Failure 400 {object} response.ErrResponse "Bad Request"
+// @Failure 500 {object} response.ErrResponse "Internal Server Error"
+// @Security AdminAuth
+// @Router /admin/haircut/reject [post]
+func (s *riskHandler) RejectHaircutAdmin(c *handler.Ctx) error {
+	var req RejectHaircutAdminRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).RejectHaircutAdminRequest, err.Error()))
+	}
+	if err := req.validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).RejectHaircutAdminRequest, err.Error()))
+	}
+
+	rows, err := s.RiskRepository.UpdateHaircutProposalRepo(c.Context(), req.ID, common.RejectStatus, time.Now().Format(common.DateYYYYMMDDHHMMSSFormat))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).InternalDatabase, err.Error()))
+	}
+	if rows != 1 {
+		return c.Status(fiber.StatusBadRequest).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).RejectHaircutAdminRequest, "ID doesn't exist or isn't pending."))
+	}
+	c.Log().Info(fmt.Sprintf("ProposalID: %d - Status: %s", req.ID, common.RejectStatus))
+	return c.Status(fiber.StatusOK).JSON(response.NewResponse(response.ResponseContextLocale(c.Context()).RejectHaircutAdminSuccess, nil))
+}
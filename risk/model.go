@@ -0,0 +1,43 @@
+package risk
+
+import (
+	"fmt"
+	"lending-engine/response"
+
+	"github.com/pkg/errors"
+)
+
+// get haircut admin
+type GetHaircutAdminRequest struct {
+	Asset  *string `json:"asset" example:"BTC"`
+	Status *string `json:"status" example:"PENDING"`
+}
+
+type GetHaircutAdminResponse struct {
+	Parameters []Parameters      `json:"parameters"`
+	Proposals  []HaircutProposal `json:"proposals"`
+}
+
+// approve haircut admin
+type ApproveHaircutAdminRequest struct {
+	ID int `json:"id" example:"1"`
+}
+
+func (req *ApproveHaircutAdminRequest) validate() error {
+	if req.ID == 0 {
+		return errors.Wrapf(errors.New(fmt.Sprintf("'id' must be REQUIRED field but the input is '%v'.", req.ID)), response.ValidateFieldError)
+	}
+	return nil
+}
+
+// reject haircut admin
+type RejectHaircutAdminRequest struct {
+	ID int `json:"id" example:"1"`
+}
+
+func (req *RejectHaircutAdminRequest) validate() error {
+	if req.ID == 0 {
+		return errors.Wrapf(errors.New(fmt.Sprintf("'id' must be REQUIRED field but the input is '%v'.", req.ID)), response.ValidateFieldError)
+	}
+	return nil
+}
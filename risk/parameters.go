@@ -0,0 +1,51 @@
+package risk
+
+import (
+	"encoding/json"
+	"fmt"
+	"lending-engine/internal/redis"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Parameters are the live haircut and margin call LTV of an asset. Approved proposals are kept at "RISK/<asset>" in redis
+// for every engine instance, an asset without one uses "loan.haircut.<asset>" and "loan.margin-call-ltv.<asset>".
+type Parameters struct {
+	Asset         string  `json:"asset" example:"BTC"`
+	Haircut       float64 `json:"haircut" example:"0.5"`
+	MarginCallLTV float64 `json:"marginCallLtv" example:"0.5"`
+	ProposalID    *int    `json:"proposalId,omitempty" example:"1"`
+}
+
+func parametersKey(asset string) string {
+	return fmt.Sprintf("RISK/%s", strings.ToUpper(asset))
+}
+
+type GetParametersFn func(asset string) (*Parameters, error)
+
+func NewGetParametersFn(getStructDataRedisFn redis.GetStructDataRedisFn) GetParametersFn {
+	return func(asset string) (*Parameters, error) {
+		parameters := Parameters{
+			Asset:         strings.ToUpper(asset),
+			Haircut:       viper.GetFloat64(fmt.Sprintf("loan.haircut.%s", strings.ToLower(asset))),
+			MarginCallLTV: viper.GetFloat64(fmt.Sprintf("loan.margin-call-ltv.%s", strings.ToLower(asset))),
+		}
+		if err := getStructDataRedisFn(parametersKey(asset), &parameters); err != nil {
+			return nil, err
+		}
+		return &parameters, nil
+	}
+}
+
+type SetParametersFn func(parameters Parameters) error
+
+func NewSetParametersFn(setDataNoExpireRedisFn redis.SetDataNoExpireRedisFn) SetParametersFn {
+	return func(parameters Parameters) error {
+		b, err := json.Marshal(&parameters)
+		if err != nil {
+			return err
+		}
+		return setDataNoExpireRedisFn(parametersKey(parameters.Asset), string(b))
+	}
+}
@@ -0,0 +1,100 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"lending-engine/common"
+	"lending-engine/mail"
+	"lending-engine/oracle"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// haircutRecommender proposes the haircut and margin call LTV of every "oracle.assets" from the realized volatility of its
+// 1h candles over "haircut.window". A proposal waits for an admin, one that differs from the live parameters supersedes
+// the pending one of the asset and alerts ops; when the model agrees with the live parameters again the pending one is retired.
+type haircutRecommender struct {
+	RiskRepository    RiskRepository
+	HistoryRepository oracle.HistoryRepository
+	GetParametersFn   GetParametersFn
+	AlertOpsFn        mail.AlertOpsFn
+	Logger            *zap.Logger
+	mu                sync.Mutex
+}
+
+func NewHaircutRecommender(riskRepository RiskRepository, historyRepository oracle.HistoryRepository, getParametersFn GetParametersFn, alertOpsFn mail.AlertOpsFn, logger *zap.Logger) *haircutRecommender {
+	return &haircutRecommender{
+		RiskRepository:    riskRepository,
+		HistoryRepository: historyRepository,
+		GetParametersFn:   getParametersFn,
+		AlertOpsFn:        alertOpsFn,
+		Logger:            logger,
+	}
+}
+
+func (r *haircutRecommender) Run(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, asset := range viper.GetStringSlice("oracle.assets") {
+		if err := r.recommend(ctx, asset); err != nil {
+			r.Logger.Error(fmt.Sprintf("Asset: %s | %s", asset, err.Error()))
+		}
+	}
+	return nil
+}
+
+func (r *haircutRecommender) recommend(ctx context.Context, asset string) error {
+	now := time.Now()
+	candles, err := r.HistoryRepository.QueryPriceCandleRepo(ctx, asset, "1h", now.Add(-viper.GetDuration("haircut.window")).Format(common.DateYYYYMMDDHHMMSSFormat), now.Format(common.DateYYYYMMDDHHMMSSFormat))
+	if err != nil {
+		return err
+	}
+	closes := make([]float64, 0, len(*candles))
+	for _, candle := range *candles {
+		closes = append(closes, *candle.ClosePrice)
+	}
+	volatility, samples := realizedVolatility(closes)
+	if samples < viper.GetInt("haircut.min-samples") {
+		r.Logger.Info(fmt.Sprintf("Asset: %s | %d of %d returns, no recommendation", asset, samples, viper.GetInt("haircut.min-samples")))
+		return nil
+	}
+	recommendation := recommend(volatility, samples, time.Hour)
+
+	current, err := r.GetParametersFn(asset)
+	if err != nil {
+		return err
+	}
+	r.Logger.Info(fmt.Sprintf("Asset: %s | Volatility: %f over %d returns - Haircut: %.4f (live %.4f) - Margin Call LTV: %.4f (live %.4f)", asset, volatility, samples, recommendation.Haircut, current.Haircut, recommendation.MarginCallLTV, current.MarginCallLTV))
+
+	if recommendation.Haircut == current.Haircut && recommendation.MarginCallLTV == current.MarginCallLTV {
+		if _, err := r.RiskRepository.SupersedeHaircutProposalRepo(ctx, asset, now.Format(common.DateYYYYMMDDHHMMSSFormat)); err != nil {
+			return err
+		}
+		return nil
+	}
+	pending, err := r.RiskRepository.QueryHaircutProposalRepo(ctx, map[string]interface{}{"asset": asset, "status": common.PendingStatus})
+	if err != nil {
+		return err
+	}
+	if len(*pending) > 0 && *(*pending)[0].ProposedHaircut == recommendation.Haircut && *(*pending)[0].ProposedMarginCallLTV == recommendation.MarginCallLTV {
+		return nil
+	}
+
+	if _, err := r.RiskRepository.SupersedeHaircutProposalRepo(ctx, asset, now.Format(common.DateYYYYMMDDHHMMSSFormat)); err != nil {
+		return err
+	}
+	id, err := r.RiskRepository.InsertHaircutProposalRepo(ctx, asset, volatility, samples, current.Haircut, recommendation.Haircut, current.MarginCallLTV, recommendation.MarginCallLTV)
+	if err != nil {
+		return err
+	}
+	message := fmt.Sprintf("Haircut proposal %d of %s waits for approval: haircut %.4f -> %.4f, margin call LTV %.4f -> %.4f from hourly volatility %.4f%% over %d returns.",
+		id, asset, current.Haircut, recommendation.Haircut, current.MarginCallLTV, recommendation.MarginCallLTV, volatility*100, samples)
+	if err := r.AlertOpsFn(r.Logger, "Haircut proposal", message); err != nil {
+		r.Logger.Error(err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package risk
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"lending-engine/common"
+	"lending-engine/oracle"
+
+	"go.uber.org/zap"
+)
+
+// memRiskRepository keeps haircut proposals in memory.
+type memRiskRepository struct {
+	RiskRepository
+	mu        sync.Mutex
+	proposals []HaircutProposal
+}
+
+func (r *memRiskRepository) QueryHaircutProposalRepo(ctx context.Context, request map[string]interface{}) (*[]HaircutProposal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	proposals := make([]HaircutProposal, 0)
+	for i := len(r.proposals) - 1; i >= 0; i-- {
+		proposal := r.proposals[i]
+		if asset, ok := request["asset"]; ok && *proposal.Asset != asset {
+			continue
+		}
+		if status, ok := request["status"]; ok && *proposal.Status != status {
+			continue
+		}
+		proposals = append(proposals, proposal)
+	}
+	return &proposals, nil
+}
+
+func (r *memRiskRepository) InsertHaircutProposalRepo(ctx context.Context, asset string, volatility float64, samples int, currentHaircut float64, proposedHaircut float64, currentMarginCallLTV float64, proposedMarginCallLTV float64) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := len(r.proposals) + 1
+	status := common.PendingStatus
+	r.proposals = append(r.proposals, HaircutProposal{
+		ID:                    &id,
+		Asset:                 &asset,
+		Volatility:            &volatility,
+		Samples:               &samples,
+		CurrentHaircut:        &currentHaircut,
+		ProposedHaircut:       &proposedHaircut,
+		CurrentMarginCallLTV:  &currentMarginCallLTV,
+		ProposedMarginCallLTV: &proposedMarginCallLTV,
+		Status:                &status,
+	})
+	return int64(id), nil
+}
+
+func (r *memRiskRepository) SupersedeHaircutProposalRepo(ctx context.Context, asset string, timestamp string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var rows int64
+	for i := range r.proposals {
+		if *r.proposals[i].Asset == asset && *r.proposals[i].Status == common.PendingStatus {
+			superseded := common.SupersededStatus
+			r.proposals[i].Status = &superseded
+			rows++
+		}
+	}
+	return rows, nil
+}
+
+// statuses returns the status of every proposal in insertion order.
+func (r *memRiskRepository) statuses() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	statuses := make([]string, 0, len(r.proposals))
+	for _, proposal := range r.proposals {
+		statuses = append(statuses, *proposal.Status)
+	}
+	return statuses
+}
+
+// candleHistory answers 1h candles whose closes move up and down by move, so their log returns alternate around zero.
+type candleHistory struct {
+	oracle.HistoryRepository
+	move float64
+}
+
+func (h *candleHistory) QueryPriceCandleRepo(ctx context.Context, asset string, interval string, from string, to string) (*[]oracle.PriceCandle, error) {
+	candles := make([]oracle.PriceCandle, 0, 50)
+	price := 1000000.0
+	for i := 0; i < 50; i++ {
+		if i%2 == 0 {
+			price *= 1 + h.move
+		} else {
+			price /= 1 + h.move
+		}
+		closePrice := price
+		candles = append(candles, oracle.PriceCandle{ClosePrice: &closePrice})
+	}
+	return &candles, nil
+}
+
+func TestHaircutRecommender(t *testing.T) {
+	setRecommendConfig(t, map[string]interface{}{
+		"oracle.assets":        []string{"BTC"},
+		"haircut.window":       720 * time.Hour,
+		"haircut.min-samples":  10,
+		"haircut.horizon":      time.Hour,
+		"haircut.confidence":   0.99,
+		"haircut.var-multiple": 3,
+		"haircut.step":         0.05,
+		"haircut.min":          0,
+		"haircut.max":          1,
+	})
+	history := &candleHistory{move: 0.01}
+	repository := &memRiskRepository{}
+	live := Parameters{Asset: "BTC", Haircut: 0.5, MarginCallLTV: 0.5}
+	getParametersFn := func(asset string) (*Parameters, error) {
+		parameters := live
+		return &parameters, nil
+	}
+	var alerts []string
+	alertOpsFn := func(logger *zap.Logger, title string, message string) error {
+		alerts = append(alerts, message)
+		return nil
+	}
+	recommender := NewHaircutRecommender(repository, history, getParametersFn, alertOpsFn, zap.NewNop())
+	ctx := context.Background()
+
+	if err := recommender.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	pending, err := repository.QueryHaircutProposalRepo(ctx, map[string]interface{}{"asset": "BTC", "status": common.PendingStatus})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*pending) != 1 || len(alerts) != 1 {
+		t.Fatalf("%d pending proposals and %d alerts, want 1 and 1", len(*pending), len(alerts))
+	}
+	first := (*pending)[0]
+	if *first.CurrentHaircut != 0.5 || *first.ProposedHaircut == 0.5 {
+		t.Errorf("proposal of haircut %v -> %v, want a change from 0.5", *first.CurrentHaircut, *first.ProposedHaircut)
+	}
+
+	// the same recommendation isn't proposed again.
+	if err := recommender.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if statuses := repository.statuses(); len(statuses) != 1 || len(alerts) != 1 {
+		t.Errorf("proposals %v and %d alerts after an unchanged run, want one pending and 1", statuses, len(alerts))
+	}
+
+	// a more volatile market supersedes it with a new proposal.
+	history.move = 0.05
+	if err := recommender.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if statuses := repository.statuses(); len(statuses) != 2 || statuses[0] != common.SupersededStatus || statuses[1] != common.PendingStatus {
+		t.Fatalf("proposals %v after the model changed, want superseded and pending", statuses)
+	}
+	if len(alerts) != 2 {
+		t.Errorf("%d alerts, want 2", len(alerts))
+	}
+	pending, err = repository.QueryHaircutProposalRepo(ctx, map[string]interface{}{"asset": "BTC", "status": common.PendingStatus})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second := (*pending)[0]
+	if *second.ProposedHaircut >= *first.ProposedHaircut {
+		t.Errorf("haircut %v at higher volatility, want below %v", *second.ProposedHaircut, *first.ProposedHaircut)
+	}
+
+	// once the live parameters are what the model recommends, the pending proposal is retired without a new one.
+	live = Parameters{Asset: "BTC", Haircut: *second.ProposedHaircut, MarginCallLTV: *second.ProposedMarginCallLTV}
+	if err := recommender.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if statuses := repository.statuses(); len(statuses) != 2 || statuses[1] != common.SupersededStatus {
+		t.Errorf("proposals %v when the model agrees with live parameters, want both superseded", statuses)
+	}
+	if len(alerts) != 2 {
+		t.Errorf("%d alerts, want 2", len(alerts))
+	}
+	if *second.Samples != 49 {
+		t.Errorf("proposal from %d returns, want 49", *second.Samples)
+	}
+}
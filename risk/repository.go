@@ -0,0 +1,115 @@
+package risk
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"lending-engine/common"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type riskRepositoryDB struct {
+	db *sqlx.DB
+}
+
+func NewRiskRepositoryDB(db *sqlx.DB) riskRepositoryDB {
+	return riskRepositoryDB{
+		db: db,
+	}
+}
+
+func (r riskRepositoryDB) QueryHaircutProposalByIDRepo(ctx context.Context, id int) (*HaircutProposal, error) {
+	var proposal HaircutProposal
+	err := r.db.GetContext(ctx, &proposal, `
+		SELECT id, asset, volatility, samples, current_haircut, proposed_haircut, current_margin_call_ltv, proposed_margin_call_ltv, status, created_datetime, updated_datetime
+		FROM lending.public.haircut_proposal
+		WHERE id = $1
+	;`, id)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, nil
+	case err != nil:
+		return nil, err
+	default:
+		return &proposal, nil
+	}
+}
+
+func (r riskRepositoryDB) QueryHaircutProposalRepo(ctx context.Context, request map[string]interface{}) (*[]HaircutProposal, error) {
+	proposals := make([]HaircutProposal, 0)
+	query := `
+		SELECT id, asset, volatility, samples, current_haircut, proposed_haircut, current_margin_call_ltv, proposed_margin_call_ltv, status, created_datetime, updated_datetime
+		FROM lending.public.haircut_proposal
+		WHERE 1 = 1
+	`
+	for key := range request {
+		query = fmt.Sprintf("%s AND %s = :%s", query, key, key)
+	}
+	query = fmt.Sprintf("%s ORDER BY id DESC", query)
+	rows, err := r.db.NamedQueryContext(ctx, query, request)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var proposal HaircutProposal
+		if err := rows.StructScan(&proposal); err != nil {
+			return nil, err
+		}
+		proposals = append(proposals, proposal)
+	}
+	return &proposals, nil
+}
+
+func (r riskRepositoryDB) InsertHaircutProposalRepo(ctx context.Context, asset string, volatility float64, samples int, currentHaircut float64, proposedHaircut float64, currentMarginCallLTV float64, proposedMarginCallLTV float64) (int64, error) {
+	var id int64
+	err := r.db.QueryRowxContext(ctx, `
+		INSERT INTO lending.public.haircut_proposal
+		(asset, volatility, samples, current_haircut, proposed_haircut, current_margin_call_ltv, proposed_margin_call_ltv, status)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	;`, asset, volatility, samples, currentHaircut, proposedHaircut, currentMarginCallLTV, proposedMarginCallLTV, common.PendingStatus).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// UpdateHaircutProposalRepo decides a proposal that is still pending, so a proposal is approved or rejected at most once.
+func (r riskRepositoryDB) UpdateHaircutProposalRepo(ctx context.Context, id int, status string, timestamp string) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE lending.public.haircut_proposal
+		SET		status = $1,
+				updated_datetime = $2
+		WHERE id = $3
+		AND status = $4
+	;`, status, timestamp, id, common.PendingStatus)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return rows, nil
+}
+
+// SupersedeHaircutProposalRepo retires the pending proposals of asset when a newer one replaces them.
+func (r riskRepositoryDB) SupersedeHaircutProposalRepo(ctx context.Context, asset string, timestamp string) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE lending.public.haircut_proposal
+		SET		status = $1,
+				updated_datetime = $2
+		WHERE asset = $3
+		AND status = $4
+	;`, common.SupersededStatus, timestamp, asset, common.PendingStatus)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return rows, nil
+}
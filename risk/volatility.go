@@ -0,0 +1,72 @@
+package risk
+
+import (
+	"math"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Recommendation is the haircut and margin call LTV the VaR model derives from the realized volatility of an asset.
+type Recommendation struct {
+	Volatility    float64
+	Samples       int
+	Haircut       float64
+	MarginCallLTV float64
+}
+
+// realizedVolatility is the sample standard deviation of the log returns between consecutive closes, per interval of the closes.
+func realizedVolatility(closes []float64) (float64, int) {
+	returns := make([]float64, 0, len(closes))
+	for i := 1; i < len(closes); i++ {
+		if closes[i-1] <= 0 || closes[i] <= 0 {
+			continue
+		}
+		returns = append(returns, math.Log(closes[i]/closes[i-1]))
+	}
+	n := len(returns)
+	if n < 2 {
+		return 0, n
+	}
+	var mean float64
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(n)
+	var variance float64
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	variance /= float64(n - 1)
+	return math.Sqrt(variance), n
+}
+
+// recommend scales volatility per interval to "haircut.horizon", the time a margin call takes to liquidate, and takes the
+// "haircut.confidence" VaR of a fall over it. The margin call LTV is where that fall would leave the loan fully collateralised,
+// the haircut leaves "haircut.var-multiple" times the fall. Both are rounded down to "haircut.step" and kept within
+// "haircut.min" and "haircut.max", the margin call LTV never below the haircut.
+func recommend(volatility float64, samples int, interval time.Duration) Recommendation {
+	horizon := viper.GetDuration("haircut.horizon")
+	z := math.Sqrt2 * math.Erfinv(2*viper.GetFloat64("haircut.confidence")-1)
+	fall := 1 - math.Exp(-z*volatility*math.Sqrt(float64(horizon)/float64(interval)))
+
+	step := viper.GetFloat64("haircut.step")
+	bound := func(value float64) float64 {
+		if step > 0 {
+			value = math.Floor(value/step+1e-9) * step
+		}
+		value = math.Max(value, viper.GetFloat64("haircut.min"))
+		value = math.Min(value, viper.GetFloat64("haircut.max"))
+		return math.Round(value*1e4) / 1e4
+	}
+	recommendation := Recommendation{
+		Volatility:    volatility,
+		Samples:       samples,
+		Haircut:       bound(1 - viper.GetFloat64("haircut.var-multiple")*fall),
+		MarginCallLTV: bound(1 - fall),
+	}
+	if recommendation.MarginCallLTV < recommendation.Haircut {
+		recommendation.MarginCallLTV = recommendation.Haircut
+	}
+	return recommendation
+}
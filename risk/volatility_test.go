@@ -0,0 +1,109 @@
+package risk
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestRealizedVolatility(t *testing.T) {
+	tests := []struct {
+		name       string
+		closes     []float64
+		volatility float64
+		samples    int
+	}{
+		{name: "no closes", closes: nil},
+		{name: "one close", closes: []float64{100}},
+		{name: "one return", closes: []float64{100, 110}, samples: 1},
+		{name: "constant return", closes: []float64{100, 110, 121}, samples: 2},
+		// the sample deviation of two returns a and b is |a - b| / sqrt(2).
+		{name: "two returns", closes: []float64{100, 110, 99}, volatility: (math.Log(1.1) - math.Log(0.9)) / math.Sqrt2, samples: 2},
+		{name: "zero close skips both of its returns", closes: []float64{100, 110, 0, 121, 133.1}, samples: 2},
+		{name: "negative close", closes: []float64{-100, 110}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			volatility, samples := realizedVolatility(tt.closes)
+			if samples != tt.samples {
+				t.Errorf("samples = %d, want %d", samples, tt.samples)
+			}
+			if math.Abs(volatility-tt.volatility) > 1e-9 {
+				t.Errorf("volatility = %v, want %v", volatility, tt.volatility)
+			}
+		})
+	}
+}
+
+// setRecommendConfig sets the model parameters for the test and resets them after it.
+func setRecommendConfig(t *testing.T, values map[string]interface{}) {
+	t.Helper()
+	for key, value := range values {
+		viper.Set(key, value)
+	}
+	t.Cleanup(func() {
+		for key := range values {
+			viper.Set(key, nil)
+		}
+	})
+}
+
+func TestRecommend(t *testing.T) {
+	tests := []struct {
+		name          string
+		volatility    float64
+		varMultiple   float64
+		step          float64
+		min           float64
+		max           float64
+		haircut       float64
+		marginCallLTV float64
+	}{
+		// a fall of 2.30% at 99%: margin call LTV 0.9770 and haircut 0.9310 round down to 0.95 and 0.90.
+		{name: "step rounding", volatility: 0.01, varMultiple: 3, step: 0.05, min: 0, max: 1, haircut: 0.9, marginCallLTV: 0.95},
+		{name: "no step", volatility: 0.01, varMultiple: 3, step: 0, min: 0, max: 1, haircut: 0.931, marginCallLTV: 0.977},
+		{name: "max clamp", volatility: 0.01, varMultiple: 3, step: 0.05, min: 0, max: 0.8, haircut: 0.8, marginCallLTV: 0.8},
+		// a fall of 68.75% leaves no haircut at 3 times the fall and 0.3125 of margin call LTV.
+		{name: "min clamp", volatility: 0.5, varMultiple: 3, step: 0.05, min: 0.2, max: 1, haircut: 0.2, marginCallLTV: 0.3},
+		// half the fall of 20.75% leaves a haircut of 0.85 over the margin call LTV of 0.75.
+		{name: "margin call ltv never below haircut", volatility: 0.1, varMultiple: 0.5, step: 0.05, min: 0, max: 1, haircut: 0.85, marginCallLTV: 0.85},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRecommendConfig(t, map[string]interface{}{
+				"haircut.horizon":      time.Hour,
+				"haircut.confidence":   0.99,
+				"haircut.var-multiple": tt.varMultiple,
+				"haircut.step":         tt.step,
+				"haircut.min":          tt.min,
+				"haircut.max":          tt.max,
+			})
+			recommendation := recommend(tt.volatility, 100, time.Hour)
+			if recommendation.Haircut != tt.haircut || recommendation.MarginCallLTV != tt.marginCallLTV {
+				t.Errorf("haircut %v and margin call LTV %v, want %v and %v", recommendation.Haircut, recommendation.MarginCallLTV, tt.haircut, tt.marginCallLTV)
+			}
+			if recommendation.Volatility != tt.volatility || recommendation.Samples != 100 {
+				t.Errorf("recommendation = %+v", recommendation)
+			}
+		})
+	}
+}
+
+func TestRecommendScalesToHorizon(t *testing.T) {
+	setRecommendConfig(t, map[string]interface{}{
+		"haircut.horizon":      4 * time.Hour,
+		"haircut.confidence":   0.99,
+		"haircut.var-multiple": 1,
+		"haircut.step":         0,
+		"haircut.min":          0,
+		"haircut.max":          1,
+	})
+	// hourly volatility of 1% over 4 hours is 2% over the horizon, the same fall as 2% per 4h interval.
+	hourly := recommend(0.01, 100, time.Hour)
+	perHorizon := recommend(0.02, 100, 4*time.Hour)
+	if hourly.MarginCallLTV != perHorizon.MarginCallLTV {
+		t.Errorf("margin call LTV = %v from hourly volatility, want %v", hourly.MarginCallLTV, perHorizon.MarginCallLTV)
+	}
+}
@@ -0,0 +1,85 @@
+package treasury
+
+import (
+	"context"
+	"fmt"
+	"lending-engine/blockchain"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// SnapshotFn reads hot and cold balances of every token on every chain and the PENDING withdrawals against them.
+type SnapshotFn func(ctx context.Context) ([]Balance, error)
+
+// NewSnapshotFn uses "treasury.hot.addresses", else hotWallet, else the chain's address as hot addresses,
+// and "treasury.cold.addresses", else the cold wallet, as cold addresses.
+func NewSnapshotFn(treasuryRepository TreasuryRepository, listChainFn blockchain.ListChainFn, queryTokenBalanceClientFn blockchain.QueryTokenBalanceClientFn, hotWallet string) SnapshotFn {
+	return func(ctx context.Context) ([]Balance, error) {
+		pendingWithdraws, err := treasuryRepository.QueryPendingWithdrawRepo(ctx)
+		if err != nil {
+			return nil, err
+		}
+		pending := make(map[string]float64)
+		for _, pendingWithdraw := range *pendingWithdraws {
+			pending[balanceKey(*pendingWithdraw.ChainID, *pendingWithdraw.CollateralType)] += *pendingWithdraw.Volume
+		}
+
+		coldAddresses := viper.GetStringSlice("treasury.cold.addresses")
+		if len(coldAddresses) == 0 && blockchain.ColdWalletAddress() != "" {
+			coldAddresses = []string{blockchain.ColdWalletAddress()}
+		}
+		balances := make([]Balance, 0)
+		for _, chain := range listChainFn() {
+			hotAddresses := viper.GetStringSlice("treasury.hot.addresses")
+			if len(hotAddresses) == 0 && hotWallet != "" {
+				hotAddresses = []string{hotWallet}
+			}
+			if len(hotAddresses) == 0 {
+				hotAddresses = []string{chain.Address}
+			}
+			collateralTypes := make([]string, 0, len(chain.Tokens))
+			for collateralType := range chain.Tokens {
+				collateralTypes = append(collateralTypes, collateralType)
+			}
+			sort.Strings(collateralTypes)
+			for _, collateralType := range collateralTypes {
+				hot, err := queryTokenBalanceClientFn(ctx, chain.ChainID, collateralType, hotAddresses)
+				if err != nil {
+					return nil, err
+				}
+				cold, err := queryTokenBalanceClientFn(ctx, chain.ChainID, collateralType, coldAddresses)
+				if err != nil {
+					return nil, err
+				}
+				balance := Balance{
+					ChainID:        chain.ChainID,
+					Name:           chain.Name,
+					CollateralType: collateralType,
+					HotAddresses:   hotAddresses,
+					ColdAddresses:  coldAddresses,
+					Hot:            hot,
+					Cold:           cold,
+					Pending:        pending[balanceKey(chain.ChainID, collateralType)],
+					Floor:          viper.GetFloat64(fmt.Sprintf("treasury.hot.floor.%s", strings.ToLower(collateralType))),
+					Ceiling:        viper.GetFloat64(fmt.Sprintf("treasury.hot.ceiling.%s", strings.ToLower(collateralType))),
+					Status:         OKStatus,
+				}
+				balance.Available = balance.Hot - balance.Pending
+				switch {
+				case balance.Hot < balance.Floor:
+					balance.Status = BelowFloorStatus
+				case balance.Ceiling > 0 && balance.Hot > balance.Ceiling:
+					balance.Status = AboveCeilingStatus
+				}
+				balances = append(balances, balance)
+			}
+		}
+		return balances, nil
+	}
+}
+
+func balanceKey(chainId int, collateralType string) string {
+	return fmt.Sprintf("%d|%s", chainId, collateralType)
+}
@@ -0,0 +1,13 @@
+package treasury
+
+import "context"
+
+type PendingWithdraw struct {
+	ChainID        *int     `db:"chain_id" json:"chainId" example:"56"`
+	CollateralType *string  `db:"collateral_type" json:"collateralType" example:"BTC"`
+	Volume         *float64 `db:"volume" json:"volume" example:"1.25"`
+}
+
+type TreasuryRepository interface {
+	QueryPendingWithdrawRepo(context.Context) (*[]PendingWithdraw, error)
+}
@@ -0,0 +1,39 @@
+package treasury
+
+import (
+	"lending-engine/internal/handler"
+	"lending-engine/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type treasuryHandler struct {
+	SnapshotFn SnapshotFn
+}
+
+func NewTreasuryHandler(snapshotFn SnapshotFn) *treasuryHandler {
+	return &treasuryHandler{
+		SnapshotFn: snapshotFn,
+	}
+}
+
+// GetTreasuryAdmin
+// @Summary Get Treasury Admin
+// @Description get hot and cold wallet balances per chain and collateral type against PENDING withdrawals
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=treasury.GetTreasuryAdminResponse} "Success"
+// @Failure 400 {object} response.ErrResponse "Bad Request"
+// @Failure 500 {object} response.ErrResponse "Internal Server Error"
+// @Router /admin/treasury [get]
+func (s *treasuryHandler) GetTreasuryAdmin(c *handler.Ctx) error {
+	balances, err := s.SnapshotFn(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(response.NewErrResponse(response.ResponseContextLocale(c.Context()).GetTreasuryAdminBlockErr, err.Error()))
+	}
+	getTreasuryAdminResponse := GetTreasuryAdminResponse{
+		Balances: balances,
+	}
+	return c.Status(fiber.StatusOK).JSON(response.NewResponse(response.ResponseContextLocale(c.Context()).GetTreasuryAdminSuccess, &getTreasuryAdminResponse))
+}
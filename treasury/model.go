@@ -0,0 +1,28 @@
+package treasury
+
+const (
+	OKStatus           string = "OK"
+	BelowFloorStatus   string = "BELOW_FLOOR"
+	AboveCeilingStatus string = "ABOVE_CEILING"
+)
+
+// Balance is the treasury of one collateral type on one chain. Available is Hot less Pending withdrawals,
+// Status compares Hot with "treasury.hot.floor.<collateral>" and "treasury.hot.ceiling.<collateral>".
+type Balance struct {
+	ChainID        int      `json:"chainId" example:"56"`
+	Name           string   `json:"name" example:"binance"`
+	CollateralType string   `json:"collateralType" example:"BTC"`
+	HotAddresses   []string `json:"hotAddresses"`
+	ColdAddresses  []string `json:"coldAddresses"`
+	Hot            float64  `json:"hot" example:"2.5"`
+	Cold           float64  `json:"cold" example:"30"`
+	Pending        float64  `json:"pending" example:"1.25"`
+	Available      float64  `json:"available" example:"1.25"`
+	Floor          float64  `json:"floor" example:"1"`
+	Ceiling        float64  `json:"ceiling" example:"5"`
+	Status         string   `json:"status" example:"OK"`
+}
+
+type GetTreasuryAdminResponse struct {
+	Balances []Balance `json:"balances"`
+}
@@ -0,0 +1,70 @@
+package treasury
+
+import (
+	"context"
+	"fmt"
+	"lending-engine/mail"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// treasuryMonitor alerts ops when a hot wallet crosses its floor or ceiling, or can't pay its PENDING withdrawals.
+// An alert is sent once when the state changes, not on every run.
+type treasuryMonitor struct {
+	SnapshotFn SnapshotFn
+	AlertOpsFn mail.AlertOpsFn
+	Logger     *zap.Logger
+	mu         sync.Mutex
+	statuses   map[string]string
+	shortfalls map[string]bool
+}
+
+func NewTreasuryMonitor(snapshotFn SnapshotFn, alertOpsFn mail.AlertOpsFn, logger *zap.Logger) *treasuryMonitor {
+	return &treasuryMonitor{
+		SnapshotFn: snapshotFn,
+		AlertOpsFn: alertOpsFn,
+		Logger:     logger,
+		statuses:   make(map[string]string),
+		shortfalls: make(map[string]bool),
+	}
+}
+
+func (t *treasuryMonitor) Run(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	balances, err := t.SnapshotFn(ctx)
+	if err != nil {
+		return err
+	}
+	for _, balance := range balances {
+		key := balanceKey(balance.ChainID, balance.CollateralType)
+		t.Logger.Info(fmt.Sprintf("ChainID: %d | %s - Hot: %f - Cold: %f - Pending: %f | Status: %s", balance.ChainID, balance.CollateralType, balance.Hot, balance.Cold, balance.Pending, balance.Status))
+
+		if last, ok := t.statuses[key]; (ok || balance.Status != OKStatus) && last != balance.Status {
+			switch balance.Status {
+			case BelowFloorStatus:
+				t.alert("Hot wallet below floor", fmt.Sprintf("%s hot wallet on %s holds %f, below floor %f. Cold wallet holds %f.", balance.CollateralType, balance.Name, balance.Hot, balance.Floor, balance.Cold))
+			case AboveCeilingStatus:
+				t.alert("Hot wallet above ceiling", fmt.Sprintf("%s hot wallet on %s holds %f, above ceiling %f. Move the excess to the cold wallet.", balance.CollateralType, balance.Name, balance.Hot, balance.Ceiling))
+			default:
+				t.Logger.Info(fmt.Sprintf("ChainID: %d | %s hot wallet is back within floor and ceiling.", balance.ChainID, balance.CollateralType))
+			}
+		}
+		t.statuses[key] = balance.Status
+
+		shortfall := balance.Available < 0
+		if shortfall && !t.shortfalls[key] {
+			t.alert("Hot wallet can't cover pending withdrawals", fmt.Sprintf("%s hot wallet on %s holds %f, pending withdrawals are %f.", balance.CollateralType, balance.Name, balance.Hot, balance.Pending))
+		}
+		t.shortfalls[key] = shortfall
+	}
+	return nil
+}
+
+func (t *treasuryMonitor) alert(title string, message string) {
+	if err := t.AlertOpsFn(t.Logger, title, message); err != nil {
+		t.Logger.Error(err.Error())
+	}
+}
@@ -0,0 +1,38 @@
+package treasury
+
+import (
+	"context"
+	"database/sql"
+	"lending-engine/common"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type treasuryRepositoryDB struct {
+	db *sqlx.DB
+}
+
+func NewTreasuryRepositoryDB(db *sqlx.DB) treasuryRepositoryDB {
+	return treasuryRepositoryDB{
+		db: db,
+	}
+}
+
+// QueryPendingWithdrawRepo sums PENDING withdrawals per chain and collateral type.
+func (r treasuryRepositoryDB) QueryPendingWithdrawRepo(ctx context.Context) (*[]PendingWithdraw, error) {
+	pendingWithdraws := make([]PendingWithdraw, 0)
+	err := r.db.SelectContext(ctx, &pendingWithdraws, `
+		SELECT chain_id, collateral_type, SUM(volume) AS volume
+		FROM lending.public.wallet_transaction
+		WHERE txn_type = $1 AND status = $2
+		GROUP BY chain_id, collateral_type
+	;`, common.WithdrawStatus, common.PendingStatus)
+	switch {
+	case err == sql.ErrNoRows:
+		return &pendingWithdraws, nil
+	case err != nil:
+		return nil, err
+	default:
+		return &pendingWithdraws, nil
+	}
+}